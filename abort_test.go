@@ -0,0 +1,116 @@
+package gqlscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTooDeep = errors.New("query too deep")
+
+func TestIteratorAbort(t *testing.T) {
+	abort := func(i *gqlscan.Iterator) {
+		switch {
+		case string(i.Value()) == "secret":
+			i.Abort(errForbiddenField)
+		case i.LevelSelect() > 2:
+			i.Abort(errTooDeep)
+		}
+	}
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect error
+		fields []string
+	}{
+		{decl(1), `{a secret b}`, errForbiddenField, []string{"a", "secret"}},
+		{decl(1), `{a{b{c}} d}`, errTooDeep, []string{"a", "b", "c"}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			check := func(t *testing.T, fields []string, err gqlscan.Error) {
+				require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+				require.True(t, errors.Is(err, td.expect))
+				require.Equal(t, td.fields, fields)
+			}
+			t.Run("ScanAll", func(t *testing.T) {
+				var fields []string
+				err := gqlscan.ScanAll(
+					[]byte(td.input),
+					func(i *gqlscan.Iterator) {
+						if i.Token() == gqlscan.TokenField {
+							fields = append(fields, string(i.Value()))
+						}
+						abort(i)
+					},
+				)
+				check(t, fields, err)
+			})
+			t.Run("Scan", func(t *testing.T) {
+				var fields []string
+				err := gqlscan.Scan(
+					[]byte(td.input),
+					func(i *gqlscan.Iterator) bool {
+						if i.Token() == gqlscan.TokenField {
+							fields = append(fields, string(i.Value()))
+						}
+						abort(i)
+						return false
+					},
+				)
+				check(t, fields, err)
+			})
+			t.Run("ScanErr", func(t *testing.T) {
+				var fields []string
+				err := gqlscan.ScanErr(
+					[]byte(td.input),
+					func(i *gqlscan.Iterator) error {
+						if i.Token() == gqlscan.TokenField {
+							fields = append(fields, string(i.Value()))
+						}
+						abort(i)
+						return nil
+					},
+				)
+				check(t, fields, err)
+			})
+		})
+	}
+}
+
+func TestIteratorAbortTypeSystem(t *testing.T) {
+	err := gqlscan.ScanTypeSystem(
+		[]byte(`type T {a: Int secret: Int}`),
+		func(i *gqlscan.Iterator) bool {
+			if string(i.Value()) == "secret" {
+				i.Abort(errForbiddenField)
+			}
+			return false
+		},
+	)
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+	require.True(t, errors.Is(err, errForbiddenField))
+}
+
+func TestIteratorAbortNil(t *testing.T) {
+	err := gqlscan.ScanAll([]byte(`{a b}`), func(i *gqlscan.Iterator) {
+		i.Abort(nil)
+	})
+	require.False(t, err.IsErr())
+}
+
+func TestIteratorAbortReuse(t *testing.T) {
+	i := gqlscan.NewIterator()
+	i.Reset([]byte(`{a}`))
+	err := i.Scan(func(i *gqlscan.Iterator) bool {
+		i.Abort(errTooDeep)
+		return false
+	})
+	require.True(t, errors.Is(err, errTooDeep))
+
+	i.Reset([]byte(`{a}`))
+	err = i.Scan(func(i *gqlscan.Iterator) bool { return false })
+	require.False(t, err.IsErr())
+}
@@ -0,0 +1,68 @@
+package gqlscan
+
+import "sync"
+
+// Allowlist holds the hashes of approved documents.
+// Documents are hashed in their compact form without ignored tokens,
+// hence formatting and comments don't affect the hash.
+// Allowlist is safe for concurrent use of Check but Add and AddHash
+// mustn't be used concurrently with any other method.
+type Allowlist struct {
+	hash   func([]byte) DocumentHash
+	hashes map[DocumentHash]struct{}
+	pool   sync.Pool
+}
+
+// NewAllowlist returns an empty allowlist hashing documents using hash.
+// If hash is nil then HashDocument is used.
+func NewAllowlist(hash func([]byte) DocumentHash) *Allowlist {
+	if hash == nil {
+		hash = HashDocument
+	}
+	return &Allowlist{
+		hash:   hash,
+		hashes: map[DocumentHash]struct{}{},
+		pool:   sync.Pool{New: func() any { return new(writer) }},
+	}
+}
+
+// Add approves document src and returns its hash.
+// Returns an error if src is invalid.
+func (a *Allowlist) Add(src []byte) (DocumentHash, Error) {
+	h, err := a.hashOf(src)
+	if err.IsErr() {
+		return DocumentHash{}, err
+	}
+	a.hashes[h] = struct{}{}
+	return h, Error{}
+}
+
+// AddHash approves the document with hash h computed by
+// the hash function of a from a document in compact form.
+func (a *Allowlist) AddHash(h DocumentHash) {
+	a.hashes[h] = struct{}{}
+}
+
+// Check returns whether document src is approved
+// together with its hash.
+// Returns an error if src is invalid.
+func (a *Allowlist) Check(src []byte) (ok bool, hash DocumentHash, err Error) {
+	if hash, err = a.hashOf(src); err.IsErr() {
+		return false, DocumentHash{}, err
+	}
+	_, ok = a.hashes[hash]
+	return ok, hash, Error{}
+}
+
+// hashOf returns the hash of the compact form of src.
+func (a *Allowlist) hashOf(src []byte) (DocumentHash, Error) {
+	w := a.pool.Get().(*writer)
+	defer a.pool.Put(w)
+	w.reset(w.dst[:0])
+	if err := ScanAll(src, func(i *Iterator) {
+		w.write(i.Token(), i.Value())
+	}); err.IsErr() {
+		return DocumentHash{}, err
+	}
+	return a.hash(w.dst), Error{}
+}
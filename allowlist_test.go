@@ -0,0 +1,60 @@
+package gqlscan_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowlist(t *testing.T) {
+	a := gqlscan.NewAllowlist(nil)
+	h, err := a.Add([]byte("query Q($v: Int = 1) {\n  a(x: $v) { b }\n}"))
+	require.False(t, err.IsErr())
+	require.Equal(t, gqlscan.HashDocument(
+		[]byte("query Q($v:Int=1){a(x:$v){b}}"),
+	), h)
+	a.AddHash(gqlscan.HashDocument([]byte("{c}")))
+
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect bool
+		err    string
+	}{
+		{decl(1), "query Q($v: Int = 1) {\n  a(x: $v) { b }\n}", true, ""},
+		{decl(1), "# comment\nquery Q ($v:Int=1,) {a(x:$v),{b}}", true, ""},
+		{decl(1), "query Q($v: Int = 2) {a(x: $v) {b}}", false, ""},
+		{decl(1), "{ c }", true, ""},
+		{decl(1), "query { c }", true, ""},
+		{decl(1), "{ d }", false, ""},
+		{decl(1), "{ c", false,
+			"error at index 3: unexpected end of file; expected field name or alias"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			ok, h, err := a.Check([]byte(td.input))
+			require.Equal(t, td.err, err.Error())
+			require.Equal(t, td.expect, ok)
+			if td.err == "" {
+				require.NotZero(t, h)
+			}
+		})
+	}
+}
+
+func TestAllowlistHash(t *testing.T) {
+	hash := func(b []byte) gqlscan.DocumentHash {
+		return sha256.Sum256(append([]byte("salt:"), b...))
+	}
+	a := gqlscan.NewAllowlist(hash)
+	h, err := a.Add([]byte("{ a }"))
+	require.False(t, err.IsErr())
+	require.Equal(t, hash([]byte("{a}")), h)
+
+	ok, h2, err := a.Check([]byte("{a}"))
+	require.False(t, err.IsErr())
+	require.True(t, ok)
+	require.Equal(t, h, h2)
+}
@@ -0,0 +1,83 @@
+package gqlscan
+
+// AliasAmplification describes the field with the greatest number
+// of distinct response keys within a single selection set.
+type AliasAmplification struct {
+	// Factor is the number of distinct response keys of the field,
+	// which is 0 if the document contains no fields.
+	Factor int
+
+	// Field is the name of the field.
+	Field string
+
+	// Index is the index of the alias or name of the field
+	// at which Factor is reached.
+	Index int
+}
+
+// ScanAliasAmplification returns the alias amplification of document
+// src, which is the greatest number of distinct aliases resolving to
+// the same field name within a selection set. An unaliased field
+// counts as an alias equal to its name.
+// Selections of inline fragments count as selections of
+// the selection set containing the inline fragment.
+// Returns an error if src is invalid.
+func ScanAliasAmplification(src []byte) (a AliasAmplification, err Error) {
+	type scope struct {
+		// keys holds the response keys of every field name.
+		keys map[string]map[string]struct{}
+	}
+	var (
+		scopes []*scope
+		inline bool
+		alias  []byte
+		// aliasIndex is the index of alias.
+		aliasIndex int
+	)
+	err = ScanAll(src, func(i *Iterator) {
+		switch i.Token() {
+		case TokenSet:
+			if l := len(scopes); inline && l > 0 {
+				scopes = append(scopes, scopes[l-1])
+			} else {
+				scopes = append(scopes, &scope{
+					keys: map[string]map[string]struct{}{},
+				})
+			}
+			inline = false
+		case TokenSetEnd:
+			scopes = scopes[:len(scopes)-1]
+		case TokenFragInline:
+			inline = true
+		case TokenNamedSpread:
+			inline = false
+		case TokenFieldAlias:
+			alias, aliasIndex = i.Value(), i.tail
+		case TokenField:
+			inline = false
+			name, key, index := i.Value(), i.Value(), i.tail
+			if alias != nil {
+				key, index = alias, aliasIndex
+				alias = nil
+			}
+			s := scopes[len(scopes)-1]
+			keys := s.keys[string(name)]
+			if keys == nil {
+				keys = map[string]struct{}{}
+				s.keys[string(name)] = keys
+			}
+			keys[string(key)] = struct{}{}
+			if len(keys) > a.Factor {
+				a = AliasAmplification{
+					Factor: len(keys),
+					Field:  string(name),
+					Index:  index,
+				}
+			}
+		}
+	})
+	if err.IsErr() {
+		return AliasAmplification{}, err
+	}
+	return a, Error{}
+}
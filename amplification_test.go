@@ -0,0 +1,41 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanAliasAmplification(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect gqlscan.AliasAmplification
+		err    string
+	}{
+		{decl(1), `fragment F on T {...G}`, gqlscan.AliasAmplification{}, ""},
+		{decl(1), `{a b}`, gqlscan.AliasAmplification{
+			Factor: 1, Field: "a", Index: 1,
+		}, ""},
+		{decl(1), `{a a x: a x: a}`, gqlscan.AliasAmplification{
+			Factor: 2, Field: "a", Index: 5,
+		}, ""},
+		{decl(1), `{x: a b {x: b y: b z: b} y: a}`, gqlscan.AliasAmplification{
+			Factor: 3, Field: "b", Index: 19,
+		}, ""},
+		{decl(1), `{x: a ... {y: a ... on T {z: a}} b {w: a}}`,
+			gqlscan.AliasAmplification{
+				Factor: 3, Field: "a", Index: 26,
+			}, ""},
+		{decl(1), `{x: a`, gqlscan.AliasAmplification{},
+			"error at index 5: unexpected end of file; expected field name"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			a, err := gqlscan.ScanAliasAmplification([]byte(td.input))
+			require.Equal(t, td.err, err.Error())
+			require.Equal(t, td.expect, a)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package gqlscan
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Annotate returns the error message followed by the line of src
+// the error occurred on and a caret pointing at the error column,
+// for example:
+//
+//	error at index 15 ('}'): unexpected token; expected argument name
+//	2 |   a(
+//	3 | }
+//	  | ^
+//
+// contextLines is the number of lines printed before and after
+// the erroneous line.
+// src must be the source the error was returned for.
+// Returns an empty string if there is no error.
+func (e Error) Annotate(src []byte, contextLines int) string {
+	if e.Code == 0 {
+		return ""
+	}
+	if contextLines < 0 {
+		contextLines = 0
+	}
+	line, column := e.Position(src)
+	lines := splitLines(src)
+	first, last := line-contextLines, line+contextLines
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+	w := len(strconv.Itoa(last))
+
+	var b strings.Builder
+	b.WriteString(e.Error())
+	gutter := func(n int) {
+		b.WriteByte('\n')
+		s := ""
+		if n > 0 {
+			s = strconv.Itoa(n)
+		}
+		b.WriteString(strings.Repeat(" ", w-len(s)))
+		b.WriteString(s)
+		b.WriteString(" |")
+	}
+	for n := first; n <= last; n++ {
+		gutter(n)
+		if l := lines[n-1]; len(l) > 0 {
+			b.WriteByte(' ')
+			b.Write(l)
+		}
+		if n != line {
+			continue
+		}
+		gutter(0)
+		b.WriteByte(' ')
+		// Preserve tabs to keep the caret aligned.
+		l := lines[n-1]
+		for c := 1; c < column && len(l) > 0; c++ {
+			r, s := utf8.DecodeRune(l)
+			l = l[s:]
+			if r == '\t' {
+				b.WriteByte('\t')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteByte('^')
+	}
+	return b.String()
+}
+
+// splitLines splits str into lines excluding the line terminators
+// "\n", "\r" and "\r\n". The result always contains at least one line.
+func splitLines(str []byte) [][]byte {
+	lines := make([][]byte, 0, 8)
+	s := 0
+	for i := 0; i < len(str); i++ {
+		switch str[i] {
+		case '\r':
+			lines = append(lines, str[s:i])
+			if i+1 < len(str) && str[i+1] == '\n' {
+				i++
+			}
+			s = i + 1
+		case '\n':
+			lines = append(lines, str[s:i])
+			s = i + 1
+		}
+	}
+	return append(lines, str[s:])
+}
@@ -0,0 +1,55 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorAnnotate(t *testing.T) {
+	for _, td := range []struct {
+		decl         string
+		input        string
+		contextLines int
+		expect       string
+	}{
+		{decl(1), "{a}", 1, ""},
+		{decl(1), "{a(}", 0,
+			"error at index 3 ('}'): unexpected token; " +
+				"expected argument name\n" +
+				"1 | {a(}\n" +
+				"  |    ^"},
+		{decl(1), "query Q {\n  a(\n}", 1,
+			"error at index 15 ('}'): unexpected token; " +
+				"expected argument name\n" +
+				"2 |   a(\n" +
+				"3 | }\n" +
+				"  | ^"},
+		{decl(1), "query Q {\r\n\ta(b: \"ä\" c:)\r\n}\r\n", 1,
+			"error at index 24 (')'): unexpected token; " +
+				"expected enum value\n" +
+				"1 | query Q {\n" +
+				"2 | \ta(b: \"ä\" c:)\n" +
+				"  | \t           ^\n" +
+				"3 | }"},
+		{decl(1), "\n\n\n\n\n\n\n\n\n{\n  a\n", 1,
+			"error at index 15: unexpected end of file; " +
+				"expected field name or alias\n" +
+				"11 |   a\n" +
+				"12 |\n" +
+				"   | ^"},
+		{decl(1), "{\n  a\n", -1,
+			"error at index 6: unexpected end of file; " +
+				"expected field name or alias\n" +
+				"3 |\n" +
+				"  | ^"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			src := []byte(td.input)
+			err := gqlscan.ScanAll(src, func(*gqlscan.Iterator) {})
+			require.Equal(t, td.expect, err.Annotate(src, td.contextLines))
+		})
+	}
+}
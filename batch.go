@@ -0,0 +1,208 @@
+package gqlscan
+
+import "strconv"
+
+// BatchError is an error of an element of a batched request.
+type BatchError struct {
+	// Element is the index of the element in the batch.
+	Element int
+	Err     Error
+}
+
+func (e BatchError) Error() string {
+	return "element " + strconv.Itoa(e.Element) + ": " + e.Err.Error()
+}
+
+// Unwrap returns e.Err.
+func (e BatchError) Unwrap() error { return e.Err }
+
+// ScanBatch is similar to ScanHTTPRequestBody but body is a JSON array
+// of requests. For each element ScanBatch describes the request in r
+// and calls fn for every token of its document.
+// The spans and indexes of r and the indexes of errors with codes
+// ErrInvalRequest and ErrInvalJSON refer to body.
+// Returns the errors of all elements in order, or nil if there were none.
+// If body isn't a JSON array then a BatchError with code ErrInvalRequest
+// is returned for the element at which the array is malformed.
+//
+// body isn't modified, see ScanHTTPRequestBody.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanBatch returns!
+func ScanBatch(
+	body []byte,
+	r *HTTPRequest,
+	fn func(element int, i *Iterator) (err bool),
+) (errs []BatchError) {
+	return ScanBatchLimited(body, r, BatchLimits{}, fn)
+}
+
+// BatchLimits defines limits on the totals of all elements
+// of a batched request. A limit is disabled if it's < 1.
+type BatchLimits struct {
+	// MaxOperations limits the number of operation definitions.
+	MaxOperations int
+
+	// MaxBytes limits the size of the query literals excluding
+	// the quotes, which bounds the size of the decoded documents.
+	// It's checked before a query is decoded.
+	MaxBytes int
+
+	// MaxComplexity limits the sum of the scores returned by
+	// Complexity for the documents with the options Complexity.
+	MaxComplexity int
+	Complexity    ComplexityOptions
+}
+
+// ScanBatchLimited is similar to ScanBatch but enforces limits on
+// the totals of all elements. When a limit is exceeded the scan stops
+// returning a BatchError for the element exceeding it with code
+// ErrTooManyOprs or ErrTooComplex at the index of the document
+// at which it's exceeded, or with code ErrDocTooLarge at the index
+// of body at which it's exceeded. Err of the error is a *LimitError.
+// The limits are enforced for the tokens skipped by fn too.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanBatchLimited returns!
+func ScanBatchLimited(
+	body []byte,
+	r *HTTPRequest,
+	limits BatchLimits,
+	fn func(element int, i *Iterator) (err bool),
+) (errs []BatchError) {
+	element := 0
+	l := batchLimiter{BatchLimits: limits}
+	fail := func(x int) []BatchError {
+		return append(errs, BatchError{
+			Element: element,
+			Err:     errorAt(body, x, ErrInvalRequest),
+		})
+	}
+
+	x := skipJSONSpace(body, 0)
+	if x >= len(body) || body[x] != '[' {
+		return fail(x)
+	}
+	if x = skipJSONSpace(body, x+1); x < len(body) && body[x] == ']' {
+		x++
+	} else {
+		for ; ; element++ {
+			v := x
+			if x = skipJSONValue(body, v); x < 0 {
+				return fail(v)
+			}
+			l.element()
+			err := parseHTTPRequest(body[:x], v, r)
+			if !err.IsErr() {
+				if err = l.checkSize(body, r.QueryIndex); err.IsErr() {
+					return append(errs, BatchError{Element: element, Err: err})
+				}
+				err = scanHTTPQuery(body, r, l.check, func(i *Iterator) bool {
+					return fn(element, i)
+				})
+			}
+			if l.code != 0 {
+				err = l.error(err)
+				return append(errs, BatchError{Element: element, Err: err})
+			}
+			if err.IsErr() {
+				errs = append(errs, BatchError{Element: element, Err: err})
+			}
+			if x = skipJSONSpace(body, x); x >= len(body) {
+				return fail(x)
+			}
+			if body[x] == ']' {
+				x++
+				break
+			}
+			if body[x] != ',' {
+				return fail(x)
+			}
+			x = skipJSONSpace(body, x+1)
+		}
+	}
+	if x = skipJSONSpace(body, x); x < len(body) {
+		return fail(x)
+	}
+	return errs
+}
+
+// batchLimiter enforces BatchLimits.
+type batchLimiter struct {
+	BatchLimits
+	operations, bytes, complexity int
+	// elementComplexity computes the complexity of the current element
+	// and is nil if complexity isn't limited.
+	elementComplexity *complexity
+
+	// str, code, index and err describe the exceeded limit
+	// if code isn't 0.
+	str   []byte
+	code  ErrorCode
+	index int
+	err   LimitError
+}
+
+// element prepares l for the next element.
+func (l *batchLimiter) element() {
+	if l.elementComplexity != nil {
+		l.complexity = addSaturated(l.complexity, l.elementComplexity.score)
+	}
+	if l.MaxComplexity > 0 {
+		l.elementComplexity = newComplexity(l.Complexity)
+	}
+}
+
+// checkSize adds the size of the query literal at index q of body
+// to bytes and returns an error if the size limit is exceeded.
+func (l *batchLimiter) checkSize(body []byte, q int) Error {
+	before := l.bytes
+	n := skipJSONString(body, q) - q - 2
+	if l.bytes = addSaturated(l.bytes, n); l.bytes > l.MaxBytes &&
+		l.MaxBytes > 0 {
+		e := errorAt(body, q+1+l.MaxBytes-before, ErrDocTooLarge)
+		e.Err = &LimitError{Limit: l.MaxBytes, Actual: l.bytes}
+		return e
+	}
+	return Error{}
+}
+
+// check returns true if a limit is exceeded at the current token of i.
+func (l *batchLimiter) check(i *Iterator) (exceeded bool) {
+	switch i.token {
+	case TokenDefQry, TokenDefMut, TokenDefSub:
+		if l.operations++; l.operations > l.MaxOperations &&
+			l.MaxOperations > 0 {
+			return l.exceed(i, ErrTooManyOprs, i.index(),
+				l.MaxOperations, l.operations)
+		}
+	}
+	if c := l.elementComplexity; c != nil {
+		c.token(i)
+		if s := addSaturated(l.complexity, c.score); s > l.MaxComplexity {
+			return l.exceed(i, ErrTooComplex, i.index(), l.MaxComplexity, s)
+		}
+	}
+	return false
+}
+
+// exceed records that limit was exceeded by actual
+// at the given index of the document of i.
+func (l *batchLimiter) exceed(
+	i *Iterator,
+	code ErrorCode,
+	index, limit, actual int,
+) (exceeded bool) {
+	l.str, l.code, l.index = i.str, code, index
+	l.err = LimitError{Limit: limit, Actual: actual}
+	return true
+}
+
+// error returns the error of the exceeded limit
+// based on err returned by the scan.
+func (l *batchLimiter) error(err Error) Error {
+	e := errorAt(l.str, l.index, l.code)
+	e.DefinitionIndex, e.TokenOrdinal = err.DefinitionIndex, err.TokenOrdinal
+	e.Trail, e.Err = err.Trail, &l.err
+	return e
+}
@@ -0,0 +1,171 @@
+package gqlscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanBatch(t *testing.T) {
+	body := []byte(`[
+		{"query": "query A {a}", "operationName": "A"},
+		{"query": "{b(}"},
+		{"query": "{c}", "variables": {"x": 1}},
+		42,
+		{"operationName": "D"}
+	]`)
+	var r gqlscan.HTTPRequest
+	var fields, operations, variables []string
+	errs := gqlscan.ScanBatch(body, &r, func(element int, i *gqlscan.Iterator) bool {
+		switch i.Token() {
+		case gqlscan.TokenDefQry:
+			operations = append(operations, string(r.OperationName))
+			variables = append(variables,
+				string(body[r.Variables.Tail:r.Variables.Head]))
+		case gqlscan.TokenField:
+			fields = append(fields, string(i.Value()))
+		}
+		return false
+	})
+	require.Equal(t, []string{"a", "b", "c"}, fields)
+	require.Equal(t, []string{"A", "", ""}, operations)
+	require.Equal(t, []string{"", "", `{"x": 1}`}, variables)
+
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	require.Equal(t, []string{
+		"element 1: error at index 3 ('}'): " +
+			"unexpected token; expected argument name",
+		"element 3: error at index 118 ('4'): invalid request",
+		"element 4: error at index 124 ('{'): invalid request",
+	}, msgs)
+	require.True(t, errors.Is(errs[0], errs[0].Err))
+}
+
+func TestScanBatchErr(t *testing.T) {
+	noop := func(int, *gqlscan.Iterator) bool { return false }
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect []string
+	}{
+		{decl(1), `[]`, nil},
+		{decl(1), ` [ ] `, nil},
+		{decl(1), `{"query": "{a}"}`, []string{
+			"element 0: error at index 0 ('{'): invalid request",
+		}},
+		{decl(1), `[{"query": "{a}"}`, []string{
+			"element 0: error at index 17 (0x0): invalid request",
+		}},
+		{decl(1), `[{"query": "{a("}, {"query": "{b}"} {}]`, []string{
+			"element 0: error at index 3: unexpected end of file; " +
+				"expected argument name",
+			"element 1: error at index 36 ('{'): invalid request",
+		}},
+		{decl(1), `[{"query": "{a\x}"}, x]`, []string{
+			"element 0: error at index 14 ('\\'): invalid JSON string",
+			"element 1: error at index 21 ('x'): invalid request",
+		}},
+		{decl(1), `[{"query": "{a}"}] x`, []string{
+			"element 0: error at index 19 ('x'): invalid request",
+		}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var r gqlscan.HTTPRequest
+			errs := gqlscan.ScanBatch([]byte(td.input), &r, noop)
+			var msgs []string
+			for _, e := range errs {
+				msgs = append(msgs, e.Error())
+			}
+			require.Equal(t, td.expect, msgs)
+		})
+	}
+}
+
+func TestScanBatchLimitedSkipped(t *testing.T) {
+	var r gqlscan.HTTPRequest
+	errs := gqlscan.ScanBatchLimited(
+		[]byte(`[{"query": "{a {b c}}"}]`), &r,
+		gqlscan.BatchLimits{MaxComplexity: 2},
+		func(element int, i *gqlscan.Iterator) bool {
+			if i.Token() == gqlscan.TokenSet {
+				i.SkipSelectionSet()
+			}
+			return false
+		},
+	)
+	require.Len(t, errs, 1)
+	require.Equal(t, "element 0: error at index 6 ('c'): "+
+		"complexity limit exceeded: got 3, limit 2", errs[0].Error())
+}
+
+func TestScanBatchLimited(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		limits gqlscan.BatchLimits
+		fields int
+		expect []string
+	}{
+		{decl(1), `[{"query": "{a}"}, {"query": "{b}"}]`,
+			gqlscan.BatchLimits{
+				MaxOperations: 2, MaxBytes: 6, MaxComplexity: 2,
+			}, 2, nil},
+		{decl(1), `[{"query": "{a}"}, {"query": "{b"}, {"query": "{c} {d}"}]`,
+			gqlscan.BatchLimits{MaxOperations: 2}, 1, []string{
+				"element 1: error at index 2: unexpected end of file; " +
+					"expected field name or alias",
+				"element 2: error at index 0 ('{'): operation limit exceeded: " +
+					"got 3, limit 2",
+			}},
+		{decl(1), `[{"query": "{a}"}, {"query": "{b c}"}, {"query": "{d}"}]`,
+			gqlscan.BatchLimits{MaxBytes: 6}, 1, []string{
+				"element 1: error at index 33 ('c'): " +
+					"document size limit exceeded: got 8, limit 6",
+			}},
+		{decl(1), `[{"query": "{a}"}, {"query": "{\u0062}"}]`,
+			gqlscan.BatchLimits{MaxBytes: 10}, 1, []string{
+				"element 1: error at index 37 ('}'): " +
+					"document size limit exceeded: got 11, limit 10",
+			}},
+		{decl(1), `[{"query": "{a(first: 5) {b}}"}, {"query": "{c d}"}]`,
+			gqlscan.BatchLimits{
+				MaxComplexity: 7,
+				Complexity: gqlscan.ComplexityOptions{
+					ListArguments: []string{"first"},
+				},
+			}, 3, []string{
+				"element 1: error at index 3 ('d'): " +
+					"complexity limit exceeded: got 8, limit 7",
+			}},
+		{decl(1), `[{"query": "{a}"}, x]`,
+			gqlscan.BatchLimits{MaxOperations: 1}, 1, []string{
+				"element 1: error at index 19 ('x'): invalid request",
+			}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var r gqlscan.HTTPRequest
+			fields := 0
+			errs := gqlscan.ScanBatchLimited(
+				[]byte(td.input), &r, td.limits,
+				func(element int, i *gqlscan.Iterator) bool {
+					if i.Token() == gqlscan.TokenField {
+						fields++
+					}
+					return false
+				},
+			)
+			var msgs []string
+			for _, e := range errs {
+				msgs = append(msgs, e.Error())
+			}
+			require.Equal(t, td.expect, msgs)
+			require.Equal(t, td.fields, fields)
+		})
+	}
+}
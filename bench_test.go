@@ -69,6 +69,34 @@ func BenchmarkScanAllErr(b *testing.B) {
 	}
 }
 
+func BenchmarkValidate(b *testing.B) {
+	for _, td := range testdata {
+		b.Run(td.decl, func(b *testing.B) {
+			in := []byte(td.input)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := gqlscan.Validate(in); err.IsErr() {
+					panic(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkValidateErr(b *testing.B) {
+	for _, td := range testdataErr {
+		b.Run(td.decl, func(b *testing.B) {
+			in := []byte(td.input)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := gqlscan.Validate(in); !err.IsErr() {
+					panic("unexpected success")
+				}
+			}
+		})
+	}
+}
+
 var InterpretedBuffer []byte
 
 func BenchmarkScanInterpreted(b *testing.B) {
@@ -0,0 +1,220 @@
+package gqlscan
+
+import (
+	"bytes"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Canonicalize appends the canonical form of document src to dst
+// and returns the extended buffer. The canonical form is minified
+// like by Minify. In addition, block strings are replaced by strings
+// of the same value, strings are escaped uniformly, integers and
+// floats are written in a normalized form and floats with
+// the same decimal value are written identically.
+// Thus syntactically equivalent documents have the same canonical form.
+// If src is invalid then dst is returned unchanged together
+// with the error.
+func Canonicalize(dst, src []byte) ([]byte, Error) {
+	w := writer{dst: dst}
+	var buf []byte
+	if err := ScanAll(src, func(i *Iterator) {
+		switch t := i.Token(); t {
+		case TokenStr:
+			buf = appendCanonicalString(buf[:0], unescapeString(i.Value()))
+			w.write(TokenStr, buf)
+		case TokenStrBlock:
+			buf = appendCanonicalString(buf[:0], blockStringValue(i.Value()))
+			w.write(TokenStr, buf)
+		case TokenInt:
+			if v := i.Value(); string(v) == "-0" {
+				w.write(t, v[1:])
+			} else {
+				w.write(t, v)
+			}
+		case TokenFloat:
+			buf = appendCanonicalFloat(buf[:0], i.Value())
+			w.write(t, buf)
+		default:
+			w.write(t, i.Value())
+		}
+	}); err.IsErr() {
+		return dst, err
+	}
+	return w.dst, Error{}
+}
+
+// unescapeString returns the value of string literal s
+// excluding the quotes. s must be valid.
+func unescapeString(s []byte) []byte {
+	if bytes.IndexByte(s, '\\') < 0 {
+		return s
+	}
+	v := make([]byte, 0, len(s))
+	for x := 0; x < len(s); {
+		if s[x] != '\\' {
+			v = append(v, s[x])
+			x++
+			continue
+		}
+		switch s[x+1] {
+		case 'b':
+			v = append(v, '\b')
+		case 'f':
+			v = append(v, '\f')
+		case 'n':
+			v = append(v, '\n')
+		case 'r':
+			v = append(v, '\r')
+		case 't':
+			v = append(v, '\t')
+		case 'u':
+			r, _ := hex4(s, x+2)
+			x += 6
+			if utf16.IsSurrogate(r) {
+				r2, ok := rune(0), false
+				if x+1 < len(s) && s[x] == '\\' && s[x+1] == 'u' {
+					r2, ok = hex4(s, x+2)
+				}
+				if d := utf16.DecodeRune(r, r2); ok && d != utf8.RuneError {
+					r, x = d, x+6
+				} else {
+					r = utf8.RuneError
+				}
+			}
+			v = utf8.AppendRune(v, r)
+			continue
+		default:
+			// '"', '\\' and '/'
+			v = append(v, s[x+1])
+		}
+		x += 2
+	}
+	return v
+}
+
+// blockStringValue returns the value of the block string literal s
+// excluding the quotes according to the BlockStringValue algorithm
+// of the specification.
+func blockStringValue(s []byte) []byte {
+	s = bytes.ReplaceAll(s, []byte(`\"""`), []byte(`"""`))
+	var lines [][]byte
+	for {
+		x := bytes.IndexAny(s, "\r\n")
+		if x < 0 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:x])
+		if s[x] == '\r' && x+1 < len(s) && s[x+1] == '\n' {
+			x++
+		}
+		s = s[x+1:]
+	}
+
+	// Remove the common indentation of all lines but the first one.
+	common := -1
+	for _, l := range lines[1:] {
+		indent := len(l) - len(bytes.TrimLeft(l, " \t"))
+		if indent < len(l) && (common < 0 || indent < common) {
+			common = indent
+		}
+	}
+	if common > 0 {
+		for x := 1; x < len(lines); x++ {
+			if len(lines[x]) < common {
+				lines[x] = nil
+			} else {
+				lines[x] = lines[x][common:]
+			}
+		}
+	}
+
+	// Remove the leading and trailing blank lines.
+	for len(lines) > 0 && len(bytes.TrimLeft(lines[0], " \t")) < 1 {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 &&
+		len(bytes.TrimLeft(lines[len(lines)-1], " \t")) < 1 {
+		lines = lines[:len(lines)-1]
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// appendCanonicalString appends the string value v
+// escaped for a string literal to dst.
+// Only quotes, backslashes and control characters are escaped.
+func appendCanonicalString(dst, v []byte) []byte {
+	const hex = "0123456789abcdef"
+	for _, c := range v {
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c == '\b':
+			dst = append(dst, '\\', 'b')
+		case c == '\f':
+			dst = append(dst, '\\', 'f')
+		case c == '\n':
+			dst = append(dst, '\\', 'n')
+		case c == '\r':
+			dst = append(dst, '\\', 'r')
+		case c == '\t':
+			dst = append(dst, '\\', 't')
+		case c < 0x20:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// appendCanonicalFloat appends the float value f in the form
+// "d.ddde±x" to dst, where the mantissa has no trailing zeros except
+// for a single zero fraction digit and the exponent is omitted if
+// it's 0. Zero is written as "0.0".
+// f is appended unchanged if its exponent is out of range.
+func appendCanonicalFloat(dst, f []byte) []byte {
+	v, neg := f, false
+	if v[0] == '-' {
+		v, neg = v[1:], true
+	}
+	exp := 0
+	if x := bytes.IndexAny(v, "eE"); x > -1 {
+		e, err := strconv.Atoi(string(v[x+1:]))
+		if err != nil {
+			return append(dst, f...)
+		}
+		v, exp = v[:x], e
+	}
+	var digits []byte
+	if x := bytes.IndexByte(v, '.'); x > -1 {
+		digits = append(append(digits, v[:x]...), v[x+1:]...)
+		exp -= len(v) - x - 1
+	} else {
+		digits = append(digits, v...)
+	}
+	// The value is digits * 10^exp.
+	digits = bytes.TrimLeft(digits, "0")
+	for len(digits) > 0 && digits[len(digits)-1] == '0' {
+		digits, exp = digits[:len(digits)-1], exp+1
+	}
+	if len(digits) < 1 {
+		return append(dst, "0.0"...)
+	}
+	if neg {
+		dst = append(dst, '-')
+	}
+	dst = append(dst, digits[0], '.')
+	if len(digits) > 1 {
+		dst = append(dst, digits[1:]...)
+	} else {
+		dst = append(dst, '0')
+	}
+	if exp += len(digits) - 1; exp != 0 {
+		dst = append(dst, 'e')
+		dst = strconv.AppendInt(dst, int64(exp), 10)
+	}
+	return dst
+}
@@ -0,0 +1,75 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		inputs []string
+		expect string
+	}{
+		{decl(1), []string{
+			"{ a }",
+			"query {\n  a\n}",
+			"# comment\nquery{a,}",
+		}, "{a}"},
+		{decl(1), []string{
+			`{a(i: 0, j: -0, k: -12)}`,
+			`{a(i: -0, j: 0, k: -12)}`,
+		}, "{a(i:0 j:0 k:-12)}"},
+		{decl(1), []string{
+			`{a(f: 150.0, g: 0.015, h: 1e3, i: 1.5, j: -0.0, k: 0e10)}`,
+			`{a(f: 1.5e2, g: 15E-3, h: 1000.0, i: 15.0e-1, j: 0.0, k: -0.0e-5)}`,
+			`{a(f: 1.50E+2, g: 0.0150, h: 10e2, i: 0.15e1, j: 0e0, k: 0.00)}`,
+		}, "{a(f:1.5e2 g:1.5e-2 h:1.0e3 i:1.5 j:0.0 k:0.0)}"},
+		{decl(1), []string{
+			`{a(s: "éé\"\\\/\b\f\n\r\t\u0001")}`,
+			`{a(s: "\u00e9\u00E9\u0022\u005c/\u0008\u000C\u000A\u000d\u0009\u0001")}`,
+		}, `{a(s:"éé\"\\/\b\f\n\r\t\u0001")}`},
+		{decl(1), []string{
+			`{a(s: "😀 😀")}`,
+			`{a(s: "\ud83d\ude00 \uD83D\uDE00")}`,
+		}, `{a(s:"😀 😀")}`},
+		{decl(1), []string{
+			"{a(s: \"\"\"\n    first\n      second\n    \\\"\"\" third\n\n  \"\"\")}",
+			"{a(s: \"\"\"first\n  second\n\\\"\"\" third\"\"\")}",
+			`{a(s: "first\n  second\n\"\"\" third")}`,
+		}, `{a(s:"first\n  second\n\"\"\" third")}`},
+		{decl(1), []string{
+			"{a(s: \"\"\"\"\"\")}",
+			"{a(s: \"\"\"  \n\t\n\"\"\")}",
+			`{a(s: "")}`,
+		}, `{a(s:"")}`},
+		{decl(1), []string{
+			"query Q($v: [Float!] = [1.0, 2.50]) { a(x: $v, y: {z: ENUM}) }",
+			"query Q($v:[Float!]=[10e-1 25e-1]){a(x:$v y:{z:ENUM})}",
+		}, "query Q($v:[Float!]=[1.0 2.5]){a(x:$v y:{z:ENUM})}"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			for _, input := range td.inputs {
+				dst := []byte("prefix:")
+				actual, err := gqlscan.Canonicalize(dst, []byte(input))
+				require.False(t, err.IsErr(), err.Error())
+				require.Equal(t, "prefix:"+td.expect, string(actual), input)
+
+				// The canonical form is canonical.
+				again, err := gqlscan.Canonicalize(nil, actual[len(dst):])
+				require.False(t, err.IsErr(), err.Error())
+				require.Equal(t, td.expect, string(again))
+			}
+		})
+	}
+}
+
+func TestCanonicalizeErr(t *testing.T) {
+	dst := []byte("prefix:")
+	actual, err := gqlscan.Canonicalize(dst, []byte(`{a(s: "x)}`))
+	require.True(t, err.IsErr())
+	require.Equal(t, "prefix:", string(actual))
+}
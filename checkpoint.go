@@ -0,0 +1,81 @@
+package gqlscan
+
+// Checkpoint is a captured state of an iterator.
+// The zero value is the state before the first token.
+type Checkpoint struct {
+	defHead    int
+	size       int
+	token      Token
+	tail, head int
+	levelSel   int
+
+	// defs and tokens are the numbers of definitions begun
+	// and tokens scanned up to the token.
+	defs, tokens int
+}
+
+// Checkpoint captures the current state of the iterator
+// which can be restored using Resume after the scan returned.
+func (i *Iterator) Checkpoint() Checkpoint {
+	return Checkpoint{
+		defHead:  i.defHead,
+		size:     len(i.str),
+		token:    i.token,
+		tail:     i.tail,
+		head:     i.head,
+		levelSel: i.levelSel,
+		defs:     i.defs,
+		tokens:   i.tokens,
+	}
+}
+
+// Token returns the token the checkpoint was captured at.
+func (c Checkpoint) Token() Token { return c.token }
+
+// LevelSelect returns the selector level the checkpoint was captured at.
+func (c Checkpoint) LevelSelect() int { return c.levelSel }
+
+// IndexHead returns the head index the checkpoint was captured at.
+func (c Checkpoint) IndexHead() int { return c.head }
+
+// Resume restores the state captured by checkpoint c during a scan
+// of str and continues scanning str calling fn for every token
+// following the token c was captured at. This allows consumers to
+// stop a scan by returning true from fn and to later backtrack to
+// any token seen before.
+// The state is restored by scanning the definition c was captured in
+// again from its beginning, fn isn't called for its tokens preceding c.
+// The definition indexes and token ordinals of returned errors
+// refer to the entire document like those of the original scan.
+// If c wasn't captured during a scan of str, for example because
+// str differs in size or has a different token at the position of c,
+// then an error with code ErrInvalCheckpoint is returned
+// and fn isn't called.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after Resume returns!
+func Resume(str []byte, c Checkpoint, fn func(*Iterator) (err bool)) Error {
+	if c.token == 0 {
+		return scan(str, 0, fn)
+	}
+	if c.size != len(str) {
+		return errorAt(str, 0, ErrInvalCheckpoint)
+	}
+	restored := false
+	err := scan(str, c.defHead, func(i *Iterator) bool {
+		if restored {
+			return fn(i)
+		}
+		restored = i.token == c.token && i.head == c.head && i.tail == c.tail
+		if restored {
+			// Continue counting from the checkpoint.
+			i.defs, i.tokens = c.defs, c.tokens
+		}
+		// Stop if the scan passed the position of c without restoring it.
+		return !restored && i.head > c.head
+	})
+	if !restored {
+		return errorAt(str, c.head, ErrInvalCheckpoint)
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResume(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			for k := range td.expect {
+				var c gqlscan.Checkpoint
+				j := 0
+				err := gqlscan.Scan([]byte(td.input), func(i *gqlscan.Iterator) bool {
+					if j == k {
+						c = i.Checkpoint()
+						return true
+					}
+					j++
+					return false
+				})
+				require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+				require.Equal(t, td.expect[k].Type, c.Token())
+
+				j = k + 1
+				err = gqlscan.Resume([]byte(td.input), c, func(i *gqlscan.Iterator) bool {
+					require.Less(t, j, len(td.expect), "resumed at %d", k)
+					require.Equal(t, td.expect[j].Type, i.Token(),
+						"token %d resumed at %d", j, k)
+					require.Equal(t, td.expect[j].Value, string(i.Value()),
+						"token %d resumed at %d", j, k)
+					j++
+					return false
+				})
+				require.False(t, err.IsErr(), "unexpected error: %s", err)
+				require.Len(t, td.expect, j, "resumed at %d", k)
+			}
+		})
+	}
+}
+
+func TestResumeZero(t *testing.T) {
+	var tokens []gqlscan.Token
+	err := gqlscan.Resume(
+		[]byte(`{a}`), gqlscan.Checkpoint{},
+		func(i *gqlscan.Iterator) bool {
+			tokens = append(tokens, i.Token())
+			return false
+		},
+	)
+	require.False(t, err.IsErr())
+	require.Equal(t, []gqlscan.Token{
+		gqlscan.TokenDefQry,
+		gqlscan.TokenSet,
+		gqlscan.TokenField,
+		gqlscan.TokenSetEnd,
+		gqlscan.TokenDefEnd,
+	}, tokens)
+}
+
+func TestResumeErr(t *testing.T) {
+	src := []byte(`query A { a } query B { b(x: ) }`)
+	var c gqlscan.Checkpoint
+	err := gqlscan.Scan(src, func(i *gqlscan.Iterator) bool {
+		if i.Token() == gqlscan.TokenOprName && string(i.Value()) == "B" {
+			c = i.Checkpoint()
+			return true
+		}
+		return false
+	})
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+	require.Equal(t, 0, c.LevelSelect())
+	require.Equal(t, 21, c.IndexHead())
+
+	err = gqlscan.Resume(src, c, func(*gqlscan.Iterator) bool { return false })
+	require.Equal(t, "error at index 29 (')'): unexpected token; "+
+		"expected enum value", err.Error())
+	require.Equal(t, "in arguments of field 'b' in query 'B'",
+		err.Trail.Describe(src))
+}
+
+func TestResumeErrOrdinals(t *testing.T) {
+	src := []byte(`{x} query A { a } query B { b c(x: ) }`)
+	var c gqlscan.Checkpoint
+	original := gqlscan.Scan(src, func(i *gqlscan.Iterator) bool {
+		if i.Token() == gqlscan.TokenField && string(i.Value()) == "b" {
+			c = i.Checkpoint()
+		}
+		return false
+	})
+	require.Equal(t, 2, original.DefinitionIndex)
+
+	err := gqlscan.Resume(src, c, func(*gqlscan.Iterator) bool { return false })
+	require.Equal(t, original, err)
+	require.Equal(t, 2, err.DefinitionIndex)
+	require.Equal(t, original.TokenOrdinal, err.TokenOrdinal)
+}
+
+func TestResumeMismatch(t *testing.T) {
+	src := []byte(`query A { a } query B { b }`)
+	var c gqlscan.Checkpoint
+	err := gqlscan.Scan(src, func(i *gqlscan.Iterator) bool {
+		if i.Token() == gqlscan.TokenField && string(i.Value()) == "b" {
+			c = i.Checkpoint()
+			return true
+		}
+		return false
+	})
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `{a}`,
+			"error at index 0 ('{'): checkpoint doesn't match the document"},
+		{decl(1), `query A { a } query B {bb }`,
+			"error at index 25 (' '): checkpoint doesn't match the document"},
+		{decl(1), `query A { a } mutation { c}`,
+			"error at index 25 ('c'): checkpoint doesn't match the document"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			called := false
+			err := gqlscan.Resume(
+				[]byte(td.input), c,
+				func(*gqlscan.Iterator) bool { called = true; return false },
+			)
+			require.Equal(t, gqlscan.ErrInvalCheckpoint, err.Code)
+			require.Equal(t, td.expect, err.Error())
+			require.False(t, called)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Command cshared exposes gqlscan through the C ABI when compiled
+// as a shared library:
+//
+//	go build -buildmode=c-shared -o libgqlscan.so ./cmd/cshared
+//
+// The build also produces libgqlscan.h declaring the functions and types.
+// Documents are read in place and never retained after a call returns.
+package main
+
+/*
+#include <stddef.h>
+
+// gqlscan_error describes a scan error.
+typedef struct {
+	// index is the byte index the error occurred at.
+	long long index;
+	// code is the gqlscan.ErrorCode, 0 if there was no error.
+	int code;
+	// expectation is the gqlscan.Expect at the time of the error.
+	int expectation;
+} gqlscan_error;
+
+// gqlscan_token is a scanned token.
+typedef struct {
+	// token is the gqlscan.Token.
+	int token;
+	// tail and head are the byte indexes of the value of the token,
+	// tail is -1 if the token has no value.
+	long long tail;
+	long long head;
+} gqlscan_token;
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/graph-guard/gqlscan"
+)
+
+func main() {}
+
+// gqlscan_validate scans the document of the given size at src.
+// Returns 0 if the document is valid, otherwise returns the error code
+// and writes the details to err unless err is NULL.
+//
+//export gqlscan_validate
+func gqlscan_validate(
+	src *C.char, size C.size_t, err *C.gqlscan_error,
+) C.int {
+	e := gqlscan.ScanAll(source(src, size), func(*gqlscan.Iterator) {})
+	setError(err, e)
+	return C.int(e.Code)
+}
+
+// gqlscan_tokenize scans the document of the given size at src writing
+// at most capacity tokens to buf.
+// Returns the total number of tokens in the document, which
+// exceeds capacity if buf was too small, or -1 in case of an error
+// in which case the details are written to err unless err is NULL.
+//
+//export gqlscan_tokenize
+func gqlscan_tokenize(
+	src *C.char, size C.size_t,
+	buf *C.gqlscan_token, capacity C.size_t,
+	err *C.gqlscan_error,
+) C.longlong {
+	var tokens []C.gqlscan_token
+	if buf != nil && capacity > 0 {
+		tokens = unsafe.Slice(buf, int(capacity))
+	}
+	n := 0
+	e := gqlscan.ScanAll(source(src, size), func(i *gqlscan.Iterator) {
+		if n < len(tokens) {
+			tokens[n] = C.gqlscan_token{
+				token: C.int(i.Token()),
+				tail:  C.longlong(i.IndexTail()),
+				head:  C.longlong(i.IndexHead()),
+			}
+		}
+		n++
+	})
+	setError(err, e)
+	if e.IsErr() {
+		return -1
+	}
+	return C.longlong(n)
+}
+
+// source returns the C memory as a byte slice without copying.
+func source(src *C.char, size C.size_t) []byte {
+	if src == nil || size < 1 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(src)), int(size))
+}
+
+func setError(dst *C.gqlscan_error, e gqlscan.Error) {
+	if dst == nil {
+		return
+	}
+	*dst = C.gqlscan_error{
+		index:       C.longlong(e.Index),
+		code:        C.int(e.Code),
+		expectation: C.int(e.Expectation),
+	}
+}
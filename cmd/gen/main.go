@@ -40,7 +40,7 @@ func main() {
 	}
 	defer fl.Close()
 
-	t := template.New("").Funcs(sprig.TxtFuncMap())
+	t := template.New("").Funcs(sprig.TxtFuncMap()).Funcs(tableFuncs)
 	if err := fs.WalkDir(
 		tmpls,
 		".",
@@ -69,6 +69,7 @@ func main() {
 
 			if _, err := t.New(name).
 				Funcs(sprig.TxtFuncMap()).
+				Funcs(tableFuncs).
 				Parse(string(c)); err != nil {
 				return fmt.Errorf("parsing template (%s): %w", path, err)
 			}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// keywords is the single source of truth for the keywords
+// an isHeadKeyword function is generated for.
+var keywords = []Keyword{
+	{Name: "Query", Value: "query"},
+	{Name: "Mutation", Value: "mutation"},
+	{Name: "Subscription", Value: "subscription"},
+	{Name: "Fragment", Value: "fragment"},
+	{Name: "On", Value: "on"},
+}
+
+// charClasses is the single source of truth for the character classes
+// the charClass table and the isHead class functions are generated for.
+// There can be at most 8 classes since a class is a bit of a byte.
+var charClasses = []CharClass{
+	{
+		Name:  "Digit",
+		Doc:   "a decimal digit",
+		Chars: "0123456789",
+	},
+	{
+		Name:  "HexDigit",
+		Doc:   "a hexadecimal digit",
+		Chars: "0123456789abcdefABCDEF",
+	},
+	{
+		Name:  "NumEnd",
+		Doc:   "a character that may terminate a number",
+		Chars: " \t\r\n,)}]#",
+	},
+	{
+		Name:  "Ignored",
+		Doc:   "an ignored character",
+		Chars: " \t\r\n,",
+	},
+	{
+		Name:  "NameStart",
+		Doc:   "a character a name may start with",
+		Chars: "_" + letters,
+	},
+	{
+		Name:  "Name",
+		Doc:   "a character a name may continue with",
+		Chars: "_0123456789" + letters,
+	},
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyz" +
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Keyword is a keyword an isHeadKeyword function is generated for.
+type Keyword struct {
+	// Name is the suffix of the generated function name.
+	Name string
+
+	// Value is the keyword itself.
+	Value string
+}
+
+// KeywordByte is a byte of a keyword at an offset from the head.
+type KeywordByte struct {
+	Offset int
+	Char   string
+	Last   bool
+}
+
+// LastOffset returns the offset of the last byte of the keyword.
+func (k Keyword) LastOffset() int { return len(k.Value) - 1 }
+
+// Reversed returns the bytes of the keyword in reverse order.
+// Comparing the last byte first makes the comparison fail early
+// for names sharing a prefix with the keyword.
+func (k Keyword) Reversed() []KeywordByte {
+	b := make([]KeywordByte, len(k.Value))
+	for i := range k.Value {
+		o := len(k.Value) - 1 - i
+		b[i] = KeywordByte{
+			Offset: o,
+			Char:   fmt.Sprintf("%q", rune(k.Value[o])),
+			Last:   i == len(k.Value)-1,
+		}
+	}
+	return b
+}
+
+// CharClass is a character class a bit of the charClass table
+// is generated for.
+type CharClass struct {
+	// Name is the suffix of both the class constant
+	// and the isHead function.
+	Name string
+
+	// Doc completes the sentence "returns true if the current head is".
+	Doc string
+
+	// Chars holds all bytes of the class.
+	Chars string
+}
+
+// CharClassEntry is an entry of the charClass table.
+type CharClassEntry struct {
+	Char    string
+	Classes string
+}
+
+// charClassTable returns the non-zero entries of the charClass table.
+func charClassTable() []CharClassEntry {
+	var t []CharClassEntry
+	for b := 0; b < 256; b++ {
+		var c []string
+		for _, cl := range charClasses {
+			if strings.IndexByte(cl.Chars, byte(b)) > -1 {
+				c = append(c, "class"+cl.Name)
+			}
+		}
+		if len(c) < 1 {
+			continue
+		}
+		t = append(t, CharClassEntry{
+			Char:    fmt.Sprintf("%q", rune(b)),
+			Classes: strings.Join(c, " | "),
+		})
+	}
+	return t
+}
+
+// tableFuncs provides the tables to the templates.
+var tableFuncs = template.FuncMap{
+	"keywords":       func() []Keyword { return keywords },
+	"charClasses":    func() []CharClass { return charClasses },
+	"charClassTable": charClassTable,
+}
+
+func init() {
+	if len(charClasses) > 8 {
+		panic("too many character classes")
+	}
+}
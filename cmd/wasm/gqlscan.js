@@ -0,0 +1,44 @@
+// gqlscan.js loads gqlscan.wasm built from ./cmd/wasm.
+//
+// wasm_exec.js from the Go distribution that built gqlscan.wasm
+// ($(go env GOROOT)/lib/wasm/wasm_exec.js) must be loaded first
+// since it defines the global Go class.
+
+"use strict";
+
+// load instantiates the WebAssembly module and resolves with
+// the gqlscan object exposing the exported functions:
+//
+//   validate(document) returns null if the document is valid,
+//   otherwise returns {message, index, code, expectation, trail}.
+//
+//   hash(document) returns the hex encoded SHA-256 hash of the document.
+//
+//   minify(document) returns the minified document string without
+//   comments and redundant separators if the document is valid,
+//   otherwise returns the error object like validate.
+//
+// source is either a Response (or a promise of one) as returned by fetch
+// or a BufferSource containing the module bytes.
+async function load(source) {
+	const go = new Go();
+	let instance;
+	if (source instanceof Response || source instanceof Promise) {
+		({ instance } = await WebAssembly.instantiateStreaming(
+			source, go.importObject,
+		));
+	} else {
+		({ instance } = await WebAssembly.instantiate(
+			source, go.importObject,
+		));
+	}
+	// run only resolves once the Go program exits, which it never does.
+	go.run(instance);
+	return globalThis.gqlscan;
+}
+
+if (typeof module !== "undefined") {
+	module.exports = { load };
+} else {
+	globalThis.loadGQLScan = load;
+}
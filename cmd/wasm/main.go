@@ -0,0 +1,88 @@
+//go:build js && wasm
+
+// Command wasm exposes gqlscan to JavaScript when compiled to WebAssembly:
+//
+//	GOOS=js GOARCH=wasm go build -o gqlscan.wasm ./cmd/wasm
+//
+// The functions are registered on the global gqlscan object,
+// see gqlscan.js for the glue code loading the module.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"syscall/js"
+
+	"github.com/graph-guard/gqlscan"
+)
+
+func main() {
+	js.Global().Set("gqlscan", js.ValueOf(map[string]interface{}{
+		"validate": js.FuncOf(validate),
+		"hash":     js.FuncOf(hash),
+		"minify":   js.FuncOf(minify),
+	}))
+
+	// Keep the functions callable.
+	select {}
+}
+
+// validate returns null if the document passed as the first argument
+// is valid, otherwise returns the error object.
+func validate(this js.Value, args []js.Value) interface{} {
+	src, ok := source(args)
+	if !ok {
+		return js.ValueOf(map[string]interface{}{
+			"message": "expected the document string as first argument",
+		})
+	}
+	if err := gqlscan.ScanAll(src, func(*gqlscan.Iterator) {}); err.IsErr() {
+		return errorObject(src, err)
+	}
+	return js.Null()
+}
+
+// hash returns the hex encoded SHA-256 hash
+// of the document passed as the first argument.
+func hash(this js.Value, args []js.Value) interface{} {
+	src, ok := source(args)
+	if !ok {
+		return js.Null()
+	}
+	h := sha256.Sum256(src)
+	return js.ValueOf(hex.EncodeToString(h[:]))
+}
+
+// minify returns the minified document passed as the first argument
+// if it's valid, otherwise returns the error object.
+func minify(this js.Value, args []js.Value) interface{} {
+	src, ok := source(args)
+	if !ok {
+		return js.ValueOf(map[string]interface{}{
+			"message": "expected the document string as first argument",
+		})
+	}
+	m, err := gqlscan.Minify(nil, src)
+	if err.IsErr() {
+		return errorObject(src, err)
+	}
+	return js.ValueOf(string(m))
+}
+
+// source returns the document passed as the first argument.
+func source(args []js.Value) ([]byte, bool) {
+	if len(args) < 1 || args[0].Type() != js.TypeString {
+		return nil, false
+	}
+	return []byte(args[0].String()), true
+}
+
+func errorObject(src []byte, err gqlscan.Error) js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"message":     err.Error(),
+		"index":       err.Index,
+		"code":        int(err.Code),
+		"expectation": err.Expectation.String(),
+		"trail":       err.Trail.Describe(src),
+	})
+}
@@ -0,0 +1,158 @@
+package gqlscan
+
+import (
+	"math"
+	"strconv"
+)
+
+// ComplexityOptions defines the weights used by Complexity.
+// Negative weights are treated as 0.
+type ComplexityOptions struct {
+	// FieldWeight is the cost of a field.
+	// A FieldWeight of 0 is treated as 1.
+	FieldWeight int
+
+	// DepthMultiplier multiplies the cost of the selections of
+	// every nested selection set.
+	// A DepthMultiplier below 1 is treated as 1.
+	DepthMultiplier int
+
+	// ListArguments holds the names of the integer arguments of fields
+	// such as "first" and "last" that define the number of list items
+	// the field returns. The cost of the selections of a field is
+	// multiplied by the greatest value of its list arguments.
+	// Arguments with variables as values are ignored.
+	ListArguments []string
+
+	// AliasWeight is the additional cost of an aliased field.
+	AliasWeight int
+
+	// SpreadWeight is the cost of a fragment spread.
+	// Inline fragments have no cost.
+	SpreadWeight int
+}
+
+// Complexity returns the complexity score of document src computed
+// in a single pass according to opts.
+// The cost of every field, alias and spread is multiplied by the
+// multipliers of all selection sets containing it, fragment spreads
+// aren't expanded and fragment definitions are scored like operations.
+// The score is clamped to math.MaxInt.
+// Returns an error if src is invalid.
+func Complexity(src []byte, opts ComplexityOptions) (int, Error) {
+	c := newComplexity(opts)
+	if err := ScanAll(src, c.token); err.IsErr() {
+		return 0, err
+	}
+	return c.score, Error{}
+}
+
+// complexity computes the complexity score of the scanned tokens.
+type complexity struct {
+	opts  ComplexityOptions
+	score int
+	// multipliers holds the multipliers of the open selection sets.
+	multipliers []int
+	// list is the greatest list argument value of the last field.
+	list int
+	// fieldArgs is true while the arguments of a field are scanned.
+	fieldArgs bool
+	// listArg is true if the last token is the name
+	// of a list argument.
+	listArg bool
+}
+
+func newComplexity(opts ComplexityOptions) *complexity {
+	if opts.FieldWeight == 0 {
+		opts.FieldWeight = 1
+	}
+	if opts.DepthMultiplier < 1 {
+		opts.DepthMultiplier = 1
+	}
+	return &complexity{opts: opts}
+}
+
+// token adds the cost of the current token of i to the score.
+func (c *complexity) token(i *Iterator) {
+	isListArg := false
+	switch i.Token() {
+	case TokenDefQry, TokenDefMut, TokenDefSub, TokenDefFrag:
+		c.list, c.fieldArgs = 0, false
+	case TokenSet:
+		m := 1
+		if l := len(c.multipliers); l > 0 {
+			m = mulSaturated(c.multipliers[l-1], c.opts.DepthMultiplier)
+		}
+		if c.list > 1 {
+			m = mulSaturated(m, c.list)
+		}
+		c.multipliers = append(c.multipliers, m)
+		c.list, c.fieldArgs = 0, false
+	case TokenSetEnd:
+		c.multipliers = c.multipliers[:len(c.multipliers)-1]
+	case TokenFieldAlias:
+		c.add(c.opts.AliasWeight)
+	case TokenField:
+		c.add(c.opts.FieldWeight)
+		c.list, c.fieldArgs = 0, true
+	case TokenNamedSpread:
+		c.add(c.opts.SpreadWeight)
+		c.list, c.fieldArgs = 0, false
+	case TokenFragInline, TokenDirName:
+		c.fieldArgs = false
+	case TokenArgName:
+		if c.fieldArgs {
+			isListArg = isListArgument(i.Value(), c.opts.ListArguments)
+		}
+	case TokenInt:
+		if c.listArg {
+			v, err := strconv.Atoi(string(i.Value()))
+			if err != nil {
+				v = math.MaxInt
+			}
+			if v > c.list {
+				c.list = v
+			}
+		}
+	}
+	c.listArg = isListArg
+}
+
+// add adds cost multiplied by the multiplier
+// of the current selection set to the score.
+func (c *complexity) add(cost int) {
+	if cost < 1 {
+		return
+	}
+	if l := len(c.multipliers); l > 0 {
+		cost = mulSaturated(cost, c.multipliers[l-1])
+	}
+	c.score = addSaturated(c.score, cost)
+}
+
+func isListArgument(name []byte, listArguments []string) bool {
+	for _, a := range listArguments {
+		if string(name) == a {
+			return true
+		}
+	}
+	return false
+}
+
+// addSaturated returns a+b for non-negative a and b
+// clamped to math.MaxInt.
+func addSaturated(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// mulSaturated returns a*b for non-negative a and b
+// clamped to math.MaxInt.
+func mulSaturated(a, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
@@ -0,0 +1,51 @@
+package gqlscan_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplexity(t *testing.T) {
+	list := []string{"first", "last"}
+	for _, td := range []struct {
+		decl   string
+		input  string
+		opts   gqlscan.ComplexityOptions
+		expect int
+		err    string
+	}{
+		{decl(1), `{a b {c d}}`, gqlscan.ComplexityOptions{}, 4, ""},
+		{decl(1), `{a b {c d}}`,
+			gqlscan.ComplexityOptions{FieldWeight: 2}, 8, ""},
+		{decl(1), `{a b {c d {e}}}`,
+			gqlscan.ComplexityOptions{DepthMultiplier: 2}, 2 + 2*2 + 4, ""},
+		{decl(1), `{a(first: 10) {b c(last: 5, first: 2) {d}}}`,
+			gqlscan.ComplexityOptions{ListArguments: list}, 1 + 20 + 50, ""},
+		{decl(1), `{a(first: $n, other: 10) @d(first: 10) {b}}`,
+			gqlscan.ComplexityOptions{ListArguments: list}, 2, ""},
+		{decl(1), `{a(where: {first: 10}) {b}}`,
+			gqlscan.ComplexityOptions{ListArguments: list}, 2, ""},
+		{decl(1), `{x: a y: a ...F ... on T {b}} fragment F on T {c}`,
+			gqlscan.ComplexityOptions{AliasWeight: 3, SpreadWeight: 5},
+			2 + 6 + 5 + 1 + 1, ""},
+		{decl(1), `{a(first: 10) {...F}}`,
+			gqlscan.ComplexityOptions{SpreadWeight: 2, ListArguments: list},
+			1 + 20, ""},
+		{decl(1), `{a b}`,
+			gqlscan.ComplexityOptions{FieldWeight: -1}, 0, ""},
+		{decl(1), `{a(first: 99999999999999999999) {b(first: 1000) {c}}}`,
+			gqlscan.ComplexityOptions{ListArguments: list}, math.MaxInt, ""},
+		{decl(1), `{a`, gqlscan.ComplexityOptions{}, 0,
+			"error at index 2: unexpected end of file; expected field name or alias"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			c, err := gqlscan.Complexity([]byte(td.input), td.opts)
+			require.Equal(t, td.err, err.Error())
+			require.Equal(t, td.expect, c)
+		})
+	}
+}
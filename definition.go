@@ -0,0 +1,41 @@
+package gqlscan
+
+// ScanDefinition is similar to Scan but scans only the first
+// definition in str and returns the number of bytes consumed
+// including the ignored characters and comments
+// following the definition.
+// The remainder str[consumed:] can be passed to ScanDefinition again
+// until it's empty.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanDefinition returns!
+func ScanDefinition(
+	str []byte,
+	fn func(*Iterator) (err bool),
+) (consumed int, err Error) {
+	end := -1
+	err = Scan(str, func(i *Iterator) bool {
+		if fn(i) {
+			return true
+		}
+		if i.token == TokenDefEnd {
+			end = i.head + 1
+			return true
+		}
+		return false
+	})
+	if end < 0 {
+		return 0, err
+	}
+	i := Iterator{str: str, head: end}
+	for i.head < len(i.str) {
+		if i.isHeadIgnored() {
+			i.skipIgnored()
+		} else if i.str[i.head] == '#' {
+			i.skipComment()
+		} else {
+			break
+		}
+	}
+	return i.head, Error{}
+}
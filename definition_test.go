@@ -0,0 +1,80 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanDefinition(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			fn := func(i *gqlscan.Iterator) bool {
+				require.True(t, j < len(td.expect))
+				require.Equal(t, td.expect[j].Type, i.Token())
+				require.Equal(t, td.expect[j].Value, string(i.Value()))
+				j++
+				return false
+			}
+			for in := []byte(td.input); len(in) > 0; {
+				n, err := gqlscan.ScanDefinition(in, fn)
+				require.False(t, err.IsErr(), "unexpected error: %s", err)
+				require.Greater(t, n, 0)
+				in = in[n:]
+			}
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanDefinitionConsumed(t *testing.T) {
+	in := []byte("{a} , # comment\n query Q {b {c}}\n\n{a(}")
+	var tokens []gqlscan.Token
+	fn := func(i *gqlscan.Iterator) bool {
+		tokens = append(tokens, i.Token())
+		return false
+	}
+
+	n, err := gqlscan.ScanDefinition(in, fn)
+	require.False(t, err.IsErr())
+	require.Equal(t, 17, n)
+	require.Equal(t, []gqlscan.Token{
+		gqlscan.TokenDefQry, gqlscan.TokenSet,
+		gqlscan.TokenField, gqlscan.TokenSetEnd,
+		gqlscan.TokenDefEnd,
+	}, tokens)
+
+	tokens = nil
+	in = in[n:]
+	n, err = gqlscan.ScanDefinition(in, fn)
+	require.False(t, err.IsErr())
+	require.Equal(t, 17, n)
+	require.Equal(t, []gqlscan.Token{
+		gqlscan.TokenDefQry, gqlscan.TokenOprName,
+		gqlscan.TokenSet, gqlscan.TokenField,
+		gqlscan.TokenSet, gqlscan.TokenField,
+		gqlscan.TokenSetEnd, gqlscan.TokenSetEnd,
+		gqlscan.TokenDefEnd,
+	}, tokens)
+
+	in = in[n:]
+	n, err = gqlscan.ScanDefinition(in, fn)
+	require.Zero(t, n)
+	require.Equal(t,
+		"error at index 3 ('}'): unexpected token; expected argument name",
+		err.Error())
+}
+
+func TestScanDefinitionCallbackErr(t *testing.T) {
+	n, err := gqlscan.ScanDefinition(
+		[]byte(`{a} {b}`),
+		func(i *gqlscan.Iterator) bool {
+			return i.Token() == gqlscan.TokenSetEnd
+		},
+	)
+	require.Zero(t, n)
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+}
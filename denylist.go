@@ -0,0 +1,167 @@
+package gqlscan
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldDenyList matches the fields of documents against a list
+// of denied fields.
+type FieldDenyList struct {
+	names map[string]bool
+	paths map[string]bool
+	// prefixes holds the proper prefixes of paths.
+	prefixes map[string]bool
+}
+
+// DeniedField is a field matched by a FieldDenyList.
+type DeniedField struct {
+	// Entry is the matching entry of the deny list.
+	Entry string
+
+	// Index is the index of the name of the field.
+	Index int
+}
+
+// NewFieldDenyList returns a FieldDenyList denying fields.
+// An entry is either a field name matching fields with that name
+// anywhere in a document or a path qualified by the operation type
+// such as "mutation.deleteUser" or "query.user.password" matching
+// fields selected at that path. Aliases and inline fragments don't
+// affect paths and fragment spreads are resolved.
+func NewFieldDenyList(fields []string) *FieldDenyList {
+	l := &FieldDenyList{
+		names:    map[string]bool{},
+		paths:    map[string]bool{},
+		prefixes: map[string]bool{},
+	}
+	for _, f := range fields {
+		if !strings.Contains(f, ".") {
+			l.names[f] = true
+			continue
+		}
+		l.paths[f] = true
+		for x := strings.LastIndexByte(f, '.'); x > 0; {
+			f = f[:x]
+			l.prefixes[f] = true
+			x = strings.LastIndexByte(f, '.')
+		}
+	}
+	return l
+}
+
+// denyNode is a selection of a definition.
+type denyNode struct {
+	// name is the name of fields and named spreads
+	// and is empty for inline fragments.
+	name     string
+	spread   bool
+	index    int
+	children []int
+}
+
+// Scan returns the fields of document src matched by l
+// in order of appearance.
+// Returns an error if src is invalid.
+func (l *FieldDenyList) Scan(src []byte) ([]DeniedField, Error) {
+	var (
+		denied []DeniedField
+		nodes  []denyNode
+		// operations holds the operation type names and
+		// the root nodes of the operations.
+		operations []string
+		roots      []int
+		fragments  = map[string]int{}
+		// stack holds the nodes whose selections are scanned.
+		stack []int
+		last  int
+	)
+	add := func(n denyNode) {
+		last = len(nodes)
+		nodes = append(nodes, n)
+		if s := len(stack); s > 0 {
+			p := stack[s-1]
+			nodes[p].children = append(nodes[p].children, last)
+		}
+	}
+	err := ScanAll(src, func(i *Iterator) {
+		switch i.Token() {
+		case TokenDefQry, TokenDefMut, TokenDefSub:
+			add(denyNode{})
+			operations = append(operations, definitionKeyword(i.token))
+			roots = append(roots, last)
+		case TokenDefFrag:
+			add(denyNode{})
+		case TokenFragName:
+			fragments[string(i.Value())] = last
+		case TokenField:
+			add(denyNode{name: string(i.Value()), index: i.tail})
+			if n := string(i.Value()); l.names[n] {
+				denied = append(denied, DeniedField{Entry: n, Index: i.tail})
+			}
+		case TokenNamedSpread:
+			add(denyNode{name: string(i.Value()), spread: true})
+		case TokenFragInline:
+			add(denyNode{})
+		case TokenSet:
+			stack = append(stack, last)
+		case TokenSetEnd:
+			stack = stack[:len(stack)-1]
+		}
+	})
+	if err.IsErr() {
+		return nil, err
+	}
+
+	if len(l.paths) > 0 {
+		type visit struct {
+			fragment int
+			path     string
+		}
+		visited := map[visit]bool{}
+		var walk func(n int, path string)
+		walk = func(n int, path string) {
+			for _, c := range nodes[n].children {
+				switch cn := nodes[c]; {
+				case cn.spread:
+					f, ok := fragments[cn.name]
+					if !ok || visited[visit{f, path}] {
+						continue
+					}
+					visited[visit{f, path}] = true
+					walk(f, path)
+				case cn.name == "":
+					walk(c, path)
+				default:
+					p := path + "." + cn.name
+					if l.paths[p] {
+						denied = append(denied, DeniedField{
+							Entry: p,
+							Index: cn.index,
+						})
+					}
+					if l.prefixes[p] {
+						walk(c, p)
+					}
+				}
+			}
+		}
+		for x, r := range roots {
+			walk(r, operations[x])
+		}
+	}
+
+	sort.SliceStable(denied, func(a, b int) bool {
+		return denied[a].Index < denied[b].Index
+	})
+	// Remove duplicates of fields matched more than once.
+	n := 0
+	for x := range denied {
+		if x > 0 && denied[x].Index == denied[n-1].Index {
+			continue
+		}
+		denied[n] = denied[x]
+		n++
+	}
+	return denied[:n], Error{}
+}
@@ -0,0 +1,55 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldDenyList(t *testing.T) {
+	l := gqlscan.NewFieldDenyList([]string{
+		"secret",
+		"mutation.deleteUser",
+		"query.user.password",
+	})
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect []gqlscan.DeniedField
+		err    string
+	}{
+		{decl(1), `{user {name} deleteUser password}`, nil, ""},
+		{decl(1), `mutation {user {password} deleteUser}`,
+			[]gqlscan.DeniedField{
+				{Entry: "mutation.deleteUser", Index: 26},
+			}, ""},
+		{decl(1), `{a {secret} x: secret} fragment F on T {secret}`,
+			[]gqlscan.DeniedField{
+				{Entry: "secret", Index: 4},
+				{Entry: "secret", Index: 15},
+				{Entry: "secret", Index: 40},
+			}, ""},
+		{decl(1), `{u: user {... on User {p: password}}}`,
+			[]gqlscan.DeniedField{
+				{Entry: "query.user.password", Index: 26},
+			}, ""},
+		{decl(1), "{user {...F} ...G}\n" +
+			"mutation {...G}\n" +
+			"fragment F on User {password ...F}\n" +
+			"fragment G on Root {deleteUser user {...F}}", []gqlscan.DeniedField{
+			{Entry: "query.user.password", Index: 55},
+			{Entry: "mutation.deleteUser", Index: 90},
+		}, ""},
+		{decl(1), `{...X}`, nil, ""},
+		{decl(1), `{secret`, nil,
+			"error at index 7: unexpected end of file; expected field name or alias"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			d, err := l.Scan([]byte(td.input))
+			require.Equal(t, td.err, err.Error())
+			require.Equal(t, td.expect, d)
+		})
+	}
+}
@@ -9,4 +9,4 @@
 // of lexical analysis.
 package gqlscan
 
-//go:generate go run cmd/gen/main.go
+//go:generate go run ./cmd/gen
@@ -0,0 +1,43 @@
+package gqlscan_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodeString(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		code   gqlscan.ErrorCode
+		expect string
+	}{
+		{decl(1), 0, ""},
+		{decl(1), gqlscan.ErrCallbackFn, "callback function returned error"},
+		{decl(1), gqlscan.ErrUnexpToken, "unexpected token"},
+		{decl(1), gqlscan.ErrUnexpEOF, "unexpected end of file"},
+		{decl(1), gqlscan.ErrIllegalFragName, "illegal fragment name"},
+		{decl(1), gqlscan.ErrInvalSourceChar, "invalid source character"},
+		{decl(1), gqlscan.ErrInvalOffset, "offset out of range"},
+		{decl(1), gqlscan.ErrInvalCheckpoint,
+			"checkpoint doesn't match the document"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			require.Equal(t, td.expect, td.code.String())
+			b, err := td.code.MarshalText()
+			require.NoError(t, err)
+			require.Equal(t, td.expect, string(b))
+		})
+	}
+}
+
+func TestErrorCodeMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(map[string]gqlscan.ErrorCode{
+		"code": gqlscan.ErrInvalNum,
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"code":"invalid number value"}`, string(b))
+}
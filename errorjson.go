@@ -0,0 +1,34 @@
+package gqlscan
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// MarshalJSON implements json.Marshaler encoding the error
+// in the format of GraphQL response errors:
+//
+//	{"message":"...","locations":[{"line":1,"column":5}]}
+//
+// locations is omitted if the position of the error is unknown.
+// The zero value is encoded as null.
+func (e Error) MarshalJSON() ([]byte, error) {
+	if e.Code == 0 {
+		return []byte("null"), nil
+	}
+	m, err := json.Marshal(e.Error())
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, len(m)+64)
+	b = append(b, `{"message":`...)
+	b = append(b, m...)
+	if e.Line > 0 {
+		b = append(b, `,"locations":[{"line":`...)
+		b = strconv.AppendInt(b, int64(e.Line), 10)
+		b = append(b, `,"column":`...)
+		b = strconv.AppendInt(b, int64(e.Column), 10)
+		b = append(b, "}]"...)
+	}
+	return append(b, '}'), nil
+}
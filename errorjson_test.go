@@ -0,0 +1,69 @@
+package gqlscan_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorMarshalJSON(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), "{a}", `null`},
+		{decl(1), "query Q {\n  a(\n}",
+			`{"message":"error at index 15 ('}'): unexpected token; ` +
+				`expected argument name",` +
+				`"locations":[{"line":3,"column":1}]}`},
+		{decl(1), `{a(b:"ä" c:"\"}`,
+			`{"message":"error at index 16: unexpected end of file; ` +
+				`expected end of string",` +
+				`"locations":[{"line":1,"column":16}]}`},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanAll([]byte(td.input), func(*gqlscan.Iterator) {})
+			b, jerr := json.Marshal(err)
+			require.NoError(t, jerr)
+			require.Equal(t, td.expect, string(b))
+		})
+	}
+}
+
+func TestErrorMarshalJSONEscape(t *testing.T) {
+	err := gqlscan.ScanAll([]byte(`{a(b:"x")"}`), func(*gqlscan.Iterator) {})
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	var v struct {
+		Message   string
+		Locations []struct{ Line, Column int }
+	}
+	require.NoError(t, json.Unmarshal(b, &v))
+	require.Equal(t, err.Error(), v.Message)
+	require.Equal(t, []struct{ Line, Column int }{{1, 10}}, v.Locations)
+}
+
+func TestErrorMarshalJSONUnknownPosition(t *testing.T) {
+	b, err := json.Marshal(gqlscan.Error{Code: gqlscan.ErrUnexpToken})
+	require.NoError(t, err)
+	require.Equal(t, `{"message":"error at index 0 (0x0): unexpected token"}`, string(b))
+}
+
+func TestErrorMarshalJSONPooledSource(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	err := gqlscan.ScanJSON([]byte(`"{a(}"`), noop)
+	b1, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+
+	// Reusing the pooled buffer mustn't affect the error.
+	gqlscan.ScanJSON([]byte(`"\n\n\n\n\n\n\n{a}"`), noop)
+	b2, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.Equal(t, string(b1), string(b2))
+	require.Equal(t, 1, err.Line)
+	require.Equal(t, 4, err.Column)
+}
@@ -0,0 +1,23 @@
+package gqlscan
+
+// ExpectAt returns what the grammar expects at index offset of src,
+// for example ExpectSel for offset 1 of `{}`.
+// Only the source preceding offset is scanned.
+// If offset is inside a name then the expectation of the name is
+// returned, for example ExpectFieldNameOrAlias for offset 2 of `{ab}`.
+// Returns the error if the source preceding offset is invalid.
+func ExpectAt(src []byte, offset int) (Expect, Error) {
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(src) {
+		offset = len(src)
+	}
+	err := Validate(src[:offset])
+	switch {
+	case !err.IsErr():
+		return ExpectDef, Error{}
+	case err.Code == ErrUnexpEOF:
+		return err.Expectation, Error{}
+	}
+	return 0, err
+}
@@ -0,0 +1,46 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectAt(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		offset int
+		expect gqlscan.Expect
+	}{
+		{decl(1), ``, 0, gqlscan.ExpectDef},
+		{decl(1), `{a}`, -1, gqlscan.ExpectDef},
+		{decl(1), `{a}`, 3, gqlscan.ExpectDef},
+		{decl(1), `{a}`, 42, gqlscan.ExpectDef},
+		{decl(1), `{}`, 1, gqlscan.ExpectSel},
+		{decl(1), `{ab}`, 2, gqlscan.ExpectFieldNameOrAlias},
+		{decl(1), `{a{b}}`, 3, gqlscan.ExpectSel},
+		{decl(1), `{a(b:1)}`, 3, gqlscan.ExpectArgName},
+		{decl(1), `{a(b:1)}`, 5, gqlscan.ExpectVal},
+		{decl(1), `query Q($v: Int) {a}`, 11, gqlscan.ExpectVarType},
+		{decl(1), `query Q($v: Int) {a}`, 9, gqlscan.ExpectVarName},
+		{decl(1), `{a(b:$v)}`, 6, gqlscan.ExpectVarRefName},
+		{decl(1), `fragment F on T {a}`, 13, gqlscan.ExpectFragTypeCond},
+		{decl(1), `{a @d}`, 4, gqlscan.ExpectDirName},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			e, err := gqlscan.ExpectAt([]byte(td.input), td.offset)
+			require.False(t, err.IsErr(), err.Error())
+			require.Equal(t, td.expect.String(), e.String())
+		})
+	}
+}
+
+func TestExpectAtErr(t *testing.T) {
+	e, err := gqlscan.ExpectAt([]byte(`{a(}{b}`), 5)
+	require.Equal(t, gqlscan.Expect(0), e)
+	require.Equal(t, "error at index 3 ('}'): unexpected token; "+
+		"expected argument name", err.Error())
+}
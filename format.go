@@ -0,0 +1,237 @@
+package gqlscan
+
+// FormatOptions configures Format.
+type FormatOptions struct {
+	// Indent is the indentation of a nesting level.
+	// Two spaces are used if Indent is empty.
+	Indent string
+}
+
+// Format appends the formatted document src to dst and returns
+// the extended buffer. Every selection is written on its own line
+// indented according to its nesting level, arguments, variable
+// definitions and list and object values are separated by commas,
+// definitions are separated by blank lines and each is terminated
+// by a line feed. Comments are removed, the query keyword of
+// shorthand queries is omitted and string values are
+// preserved unchanged.
+// If src is invalid then dst is returned unchanged together
+// with the error.
+func Format(dst, src []byte, opts FormatOptions) ([]byte, Error) {
+	f := formatter{dst: dst, indent: opts.Indent}
+	if f.indent == "" {
+		f.indent = "  "
+	}
+	if err := ScanAll(src, func(i *Iterator) {
+		f.write(i.Token(), i.Value())
+	}); err.IsErr() {
+		return dst, err
+	}
+	return f.dst, Error{}
+}
+
+// formatter writes a token stream as a formatted document.
+type formatter struct {
+	dst    []byte
+	indent string
+
+	// prev holds the previously written token.
+	prev Token
+
+	// defs is the number of definitions written and
+	// depth the nesting level of selection sets.
+	defs, depth int
+
+	// pendingQry is true if the query keyword of the previous
+	// TokenDefQry wasn't written yet since it's omitted
+	// when the definition is a shorthand query.
+	pendingQry bool
+
+	// levelArgs is > 0 inside of argument lists and
+	// inVarList is true inside of variable lists.
+	levelArgs int
+	inVarList bool
+
+	// values holds for every open list and object value
+	// whether it has any elements.
+	values []bool
+}
+
+func (f *formatter) write(t Token, value []byte) {
+	if f.pendingQry {
+		f.pendingQry = false
+		if t != TokenSet {
+			f.dst = append(f.dst, "query"...)
+		}
+	}
+
+	switch t {
+	case TokenDefQry:
+		f.definition()
+		f.pendingQry = true
+	case TokenDefMut:
+		f.definition()
+		f.dst = append(f.dst, "mutation"...)
+	case TokenDefSub:
+		f.definition()
+		f.dst = append(f.dst, "subscription"...)
+	case TokenDefFrag:
+		f.definition()
+		f.dst = append(f.dst, "fragment"...)
+	case TokenDefEnd:
+		f.dst = append(f.dst, '\n')
+	case TokenOprName, TokenFragName:
+		f.dst = append(f.dst, ' ')
+		f.dst = append(f.dst, value...)
+	case TokenFragTypeCond:
+		f.dst = append(f.dst, " on "...)
+		f.dst = append(f.dst, value...)
+	case TokenDirName:
+		f.dst = append(f.dst, " @"...)
+		f.dst = append(f.dst, value...)
+	case TokenVarList:
+		f.inVarList = true
+		switch f.prev {
+		case TokenDefQry, TokenDefMut, TokenDefSub:
+			f.dst = append(f.dst, ' ')
+		}
+		f.dst = append(f.dst, '(')
+	case TokenVarListEnd:
+		f.inVarList = false
+		f.dst = append(f.dst, ')')
+	case TokenVarName:
+		if f.prev != TokenVarList {
+			f.dst = append(f.dst, ", "...)
+		}
+		f.dst = append(f.dst, '$')
+		f.dst = append(f.dst, value...)
+		f.dst = append(f.dst, ": "...)
+	case TokenVarTypeName:
+		f.dst = append(f.dst, value...)
+	case TokenVarTypeArr:
+		f.dst = append(f.dst, '[')
+	case TokenVarTypeArrEnd:
+		f.dst = append(f.dst, ']')
+	case TokenVarTypeNotNull:
+		f.dst = append(f.dst, '!')
+	case TokenArgList:
+		f.levelArgs++
+		f.dst = append(f.dst, '(')
+	case TokenArgListEnd:
+		f.levelArgs--
+		f.dst = append(f.dst, ')')
+	case TokenArgName:
+		if f.prev != TokenArgList {
+			f.dst = append(f.dst, ", "...)
+		}
+		f.dst = append(f.dst, value...)
+		f.dst = append(f.dst, ": "...)
+	case TokenSet:
+		if f.prev != TokenDefQry {
+			f.dst = append(f.dst, ' ')
+		}
+		f.dst = append(f.dst, '{')
+		f.depth++
+	case TokenSetEnd:
+		f.depth--
+		f.line()
+		f.dst = append(f.dst, '}')
+	case TokenFieldAlias:
+		f.line()
+		f.dst = append(f.dst, value...)
+		f.dst = append(f.dst, ": "...)
+	case TokenField:
+		if f.prev != TokenFieldAlias {
+			f.line()
+		}
+		f.dst = append(f.dst, value...)
+	case TokenNamedSpread:
+		f.line()
+		f.dst = append(f.dst, "..."...)
+		f.dst = append(f.dst, value...)
+	case TokenFragInline:
+		// The value of TokenFragInline is its optional type condition.
+		f.line()
+		f.dst = append(f.dst, "..."...)
+		if len(value) > 0 {
+			f.dst = append(f.dst, " on "...)
+			f.dst = append(f.dst, value...)
+		}
+	default:
+		f.value(t, value)
+	}
+	f.prev = t
+}
+
+// value writes a value token prefixed with the separator
+// preceding it.
+func (f *formatter) value(t Token, value []byte) {
+	if f.inVarList && f.levelArgs < 1 && len(f.values) < 1 {
+		switch f.prev {
+		case TokenVarTypeName, TokenVarTypeNotNull, TokenVarTypeArrEnd:
+			f.dst = append(f.dst, " = "...)
+		}
+	}
+	switch t {
+	case TokenArrEnd, TokenObjEnd:
+	default:
+		if l := len(f.values); l > 0 && f.prev != TokenObjField {
+			if f.values[l-1] {
+				f.dst = append(f.dst, ", "...)
+			}
+			f.values[l-1] = true
+		}
+	}
+	switch t {
+	case TokenArr:
+		f.values = append(f.values, false)
+		f.dst = append(f.dst, '[')
+	case TokenArrEnd:
+		f.values = f.values[:len(f.values)-1]
+		f.dst = append(f.dst, ']')
+	case TokenObj:
+		f.values = append(f.values, false)
+		f.dst = append(f.dst, '{')
+	case TokenObjEnd:
+		f.values = f.values[:len(f.values)-1]
+		f.dst = append(f.dst, '}')
+	case TokenObjField:
+		f.dst = append(f.dst, value...)
+		f.dst = append(f.dst, ": "...)
+	case TokenVarRef:
+		f.dst = append(f.dst, '$')
+		f.dst = append(f.dst, value...)
+	case TokenStr:
+		f.dst = append(f.dst, '"')
+		f.dst = append(f.dst, value...)
+		f.dst = append(f.dst, '"')
+	case TokenStrBlock:
+		f.dst = append(f.dst, `"""`...)
+		f.dst = append(f.dst, value...)
+		f.dst = append(f.dst, `"""`...)
+	case TokenTrue:
+		f.dst = append(f.dst, "true"...)
+	case TokenFalse:
+		f.dst = append(f.dst, "false"...)
+	case TokenNull:
+		f.dst = append(f.dst, "null"...)
+	default:
+		// TokenInt, TokenFloat and TokenEnumVal
+		f.dst = append(f.dst, value...)
+	}
+}
+
+// definition separates the next definition from the previous one.
+func (f *formatter) definition() {
+	if f.defs++; f.defs > 1 {
+		f.dst = append(f.dst, '\n')
+	}
+}
+
+// line begins a new line indented according to the nesting level.
+func (f *formatter) line() {
+	f.dst = append(f.dst, '\n')
+	for x := 0; x < f.depth; x++ {
+		f.dst = append(f.dst, f.indent...)
+	}
+}
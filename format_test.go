@@ -0,0 +1,79 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		opts   gqlscan.FormatOptions
+		expect string
+	}{
+		{decl(1), "{a}", gqlscan.FormatOptions{}, "{\n  a\n}\n"},
+		{decl(1), "query{a{b}}", gqlscan.FormatOptions{Indent: "\t"},
+			"{\n\ta {\n\t\tb\n\t}\n}\n"},
+		{decl(1), "# comment\nquery Q($v:[Int!]!=[1 2],$w:E=A,$o:I={a:1 b:[2]})" +
+			"@d{x:a(a:$v,b:1.5,c:true,d:null)@e(f:\"s\"){b}}",
+			gqlscan.FormatOptions{},
+			"query Q($v: [Int!]! = [1, 2], $w: E = A, " +
+				"$o: I = {a: 1, b: [2]}) @d {\n" +
+				"  x: a(a: $v, b: 1.5, c: true, d: null) @e(f: \"s\") {\n" +
+				"    b\n" +
+				"  }\n" +
+				"}\n"},
+		{decl(1), "query($v:Int@d){a} mutation{b(o:{p:[[1],{q:\"\"\" x \"\"\"}]})} " +
+			"subscription S{...F ...on T{c} ...@d{d}} fragment F on T{e}",
+			gqlscan.FormatOptions{},
+			"query ($v: Int @d) {\n" +
+				"  a\n" +
+				"}\n" +
+				"\n" +
+				"mutation {\n" +
+				"  b(o: {p: [[1], {q: \"\"\" x \"\"\"}]})\n" +
+				"}\n" +
+				"\n" +
+				"subscription S {\n" +
+				"  ...F\n" +
+				"  ... on T {\n" +
+				"    c\n" +
+				"  }\n" +
+				"  ... @d {\n" +
+				"    d\n" +
+				"  }\n" +
+				"}\n" +
+				"\n" +
+				"fragment F on T {\n" +
+				"  e\n" +
+				"}\n"},
+		{decl(1), "{a(l:[]) b}", gqlscan.FormatOptions{},
+			"{\n  a(l: [])\n  b\n}\n"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			dst := []byte("prefix:")
+			actual, err := gqlscan.Format(dst, []byte(td.input), td.opts)
+			require.False(t, err.IsErr(), err.Error())
+			require.Equal(t, "prefix:"+td.expect, string(actual))
+
+			// Formatting is idempotent.
+			again, err := gqlscan.Format(nil, actual[len(dst):], td.opts)
+			require.False(t, err.IsErr(), err.Error())
+			require.Equal(t, td.expect, string(again))
+		})
+	}
+}
+
+func TestFormatErr(t *testing.T) {
+	dst := []byte("prefix:")
+	actual, err := gqlscan.Format(dst, []byte("{ a"), gqlscan.FormatOptions{})
+	require.Equal(t,
+		"error at index 3: unexpected end of file; "+
+			"expected field name or alias",
+		err.Error())
+	require.Equal(t, "prefix:", string(actual))
+}
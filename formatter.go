@@ -0,0 +1,22 @@
+package gqlscan
+
+// ErrorFormatter formats the messages of errors,
+// for example to localize them.
+// To fall back to the default message FormatError can call
+// Error on e after setting e.Formatter to nil.
+type ErrorFormatter interface {
+	FormatError(e Error) string
+}
+
+// ErrorFormatterFunc is a function implementing ErrorFormatter.
+type ErrorFormatterFunc func(e Error) string
+
+// FormatError implements ErrorFormatter.
+func (f ErrorFormatterFunc) FormatError(e Error) string {
+	return f(e)
+}
+
+// WithErrorFormatter makes the scan attach f to the returned error.
+func WithErrorFormatter(f ErrorFormatter) Option {
+	return func(o *options) { o.formatter = f }
+}
@@ -0,0 +1,66 @@
+package gqlscan_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+var germanFormatter = gqlscan.ErrorFormatterFunc(func(e gqlscan.Error) string {
+	switch e.Code {
+	case gqlscan.ErrUnexpToken:
+		return fmt.Sprintf("unerwartetes Zeichen an Position %d", e.Index)
+	}
+	e.Formatter = nil
+	return e.Error()
+})
+
+func TestErrorFormatter(t *testing.T) {
+	src := []byte(`{a(}`)
+	err := gqlscan.ScanWithOptions(
+		src,
+		func(*gqlscan.Iterator) bool { return false },
+		gqlscan.WithErrorFormatter(germanFormatter),
+	)
+	require.Equal(t, "unerwartetes Zeichen an Position 3", err.Error())
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	require.Equal(t, `{"message":"unerwartetes Zeichen an Position 3",`+
+		`"locations":[{"line":1,"column":4}]}`, string(b))
+
+	err.Formatter = nil
+	require.Equal(t, "error at index 3 ('}'): unexpected token; "+
+		"expected argument name", err.Error())
+}
+
+func TestErrorFormatterFallback(t *testing.T) {
+	err := gqlscan.ScanWithOptions(
+		[]byte(`{a(`),
+		func(*gqlscan.Iterator) bool { return false },
+		gqlscan.WithErrorFormatter(germanFormatter),
+	)
+	require.Equal(t, "error at index 3: unexpected end of file; "+
+		"expected argument name", err.Error())
+}
+
+func TestErrorFormatterNoError(t *testing.T) {
+	err := gqlscan.ScanWithOptions(
+		[]byte(`{a}`),
+		func(*gqlscan.Iterator) bool { return false },
+		gqlscan.WithErrorFormatter(germanFormatter),
+	)
+	require.False(t, err.IsErr())
+	require.Nil(t, err.Formatter)
+	require.Equal(t, "", err.Error())
+}
+
+func TestErrorFormatterAttach(t *testing.T) {
+	err := gqlscan.ScanAll([]byte(`{a(}`), func(*gqlscan.Iterator) {})
+	err.Formatter = germanFormatter
+	require.Equal(t, "unerwartetes Zeichen an Position 3", err.Error())
+}
@@ -0,0 +1,69 @@
+package gqlscan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Got describes the input encountered at the index of the error,
+// for example "']' closing bracket", "name" or "end of file".
+// Returns an empty string if there's no error.
+func (e Error) Got() string {
+	if e.Code == 0 {
+		return ""
+	}
+	if e.Code == ErrUnexpEOF {
+		return "end of file"
+	}
+	switch r := e.AtIndex; {
+	case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		return "name"
+	case r == '-' || r >= '0' && r <= '9':
+		return "number"
+	case r == '"':
+		return "string"
+	case r < 0x20:
+		return fmt.Sprintf("control character 0x%x", r)
+	default:
+		if n, ok := punctuatorNames[r]; ok {
+			return "'" + string(r) + "' " + n
+		}
+		return "'" + string(r) + "'"
+	}
+}
+
+var punctuatorNames = map[rune]string{
+	'{': "opening brace",
+	'}': "closing brace",
+	'[': "opening bracket",
+	']': "closing bracket",
+	'(': "opening parenthesis",
+	')': "closing parenthesis",
+	'$': "variable sign",
+	'@': "directive sign",
+	'!': "exclamation mark",
+	':': "colon",
+	'=': "equals sign",
+	'|': "pipe",
+	'&': "ampersand",
+	'.': "dot",
+}
+
+// Detail returns the expectation of the error along with the input
+// encountered at its index, for example
+// "expected value, got ']' closing bracket".
+// Returns an empty string if there's no error.
+func (e Error) Detail() string {
+	if e.Code == 0 {
+		return ""
+	}
+	var b strings.Builder
+	if e.Expectation != 0 {
+		b.WriteString("expected ")
+		b.WriteString(e.Expectation.String())
+		b.WriteString(", ")
+	}
+	b.WriteString("got ")
+	b.WriteString(e.Got())
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorDetail(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		got    string
+		detail string
+	}{
+		{decl(1), `{a}`, "", ""},
+		{decl(1), `{a(b:]}`, "']' closing bracket",
+			"expected enum value, got ']' closing bracket"},
+		{decl(1), `{a(b:`, "end of file",
+			"expected value, got end of file"},
+		{decl(1), `query Q(v:Int) {a}`, "name",
+			"expected variable, got name"},
+		{decl(1), `{a(1:0)}`, "number",
+			"expected argument name, got number"},
+		{decl(1), `{"a"}`, "string",
+			"expected field name or alias, got string"},
+		{decl(1), "{a\x00}", "control character 0x0",
+			"expected field name or alias, got control character 0x0"},
+		{decl(1), `{a %}`, "'%'",
+			"expected field name or alias, got '%'"},
+		{decl(1), `{a(b:$)}`, "')' closing parenthesis",
+			"expected referenced variable name, " +
+				"got ')' closing parenthesis"},
+		{decl(1), `query Q($v:Int=$x) {a}`, "'$' variable sign",
+			"expected default variable value, got '$' variable sign"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanAll([]byte(td.input), func(*gqlscan.Iterator) {})
+			require.Equal(t, td.got, err.Got())
+			require.Equal(t, td.detail, err.Detail())
+		})
+	}
+}
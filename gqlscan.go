@@ -3,6 +3,7 @@
 package gqlscan
 
 import (
+	"bytes"
 	"fmt"
 	"strconv"
 	"strings"
@@ -19,16 +20,83 @@ import (
 // used after Scan returns because it's returned to the pool
 // and may be acquired by another call to Scan!
 func Scan(str []byte, fn func(*Iterator) (err bool)) Error {
+	return scan(str, 0, fn)
+}
 
-	/*<scan_body>*/
+// ScanAt is similar to Scan but starts scanning at index offset of str
+// which must precede a definition or ignored characters and comments
+// that precede a definition.
+// The indexes of the tokens and errors are relative to str.
+// ScanAt panics if offset is out of range.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanAt returns because it's returned to the pool
+// and may be acquired by another call to Scan!
+func ScanAt(str []byte, offset int, fn func(*Iterator) (err bool)) Error {
+	if offset < 0 || offset > len(str) {
+		panic("gqlscan: offset out of range")
+	}
+	return scan(str, offset, fn)
+}
+
+// scan is similar to Scan but starts scanning at index start of str
+// which must precede a definition or ignored characters and comments
+// that precede a definition.
+func scan(str []byte, start int, fn func(*Iterator) (err bool)) Error {
 	i := iteratorPool.Get().(*Iterator)
+	defer iteratorPool.Put(i)
+	i.str = str
+	return i.scan(start, fn)
+}
+
+// NewIterator creates a new iterator that isn't taken from the pool
+// of iterators used by Scan and ScanAll.
+// Use Reset and Scan to scan a document with the iterator.
+func NewIterator() *Iterator {
+	return &Iterator{
+		stack:   make([]Token, 64),
+		parents: make([]Span, 0, 64),
+	}
+}
+
+// Reset resets the iterator to scan str when Scan is called.
+func (i *Iterator) Reset(str []byte) {
+	i.str, i.token, i.tail, i.head = str, 0, -1, 0
+	i.defs, i.tokens = 0, 0
+	i.abortErr = nil
+}
+
+// Abort stops the scan after the function the iterator
+// was passed to returns. The returned error has code ErrCallbackFn
+// and holds err in field Err.
+// Abort has no effect if err is nil.
+func (i *Iterator) Abort(err error) {
+	i.abortErr = err
+}
+
+// Scan is similar to the function Scan but scans the document
+// the iterator was reset to using the iterator itself.
+// The iterator created with NewIterator can be used after
+// Scan returns, but must not be used by multiple goroutines
+// concurrently.
+func (i *Iterator) Scan(fn func(*Iterator) (err bool)) Error {
+	return i.scan(0, fn)
+}
+
+// scan scans i.str starting at index start of i.str.
+func (i *Iterator) scan(start int, fn func(*Iterator) (err bool)) Error {
+
+	/*<scan_body>*/
+
 	i.stackReset()
 	i.expect = ExpectDef
-	i.tail, i.head = -1, 0
-	i.str = str
+	i.tail, i.head = -1, start
 	i.levelSel = 0
 	i.errc = 0
-	defer iteratorPool.Put(i)
+	i.def = 0
+	i.defs, i.tokens = 0, 0
+	i.abortErr = nil
+	i.skipEnd = 0
 
 	// inDefVal triggers different expectations after values
 	// when the iterator is in a variable default value definition.
@@ -37,84 +105,12 @@ func Scan(str []byte, fn func(*Iterator) (err bool)) Error {
 	var dirOn dirTarget
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -134,12 +130,20 @@ DEFINITION:
 		goto COMMENT
 	} else if i.str[i.head] == '{' {
 		i.token = TokenDefQry
+		i.trailDef()
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.expect = ExpectSelSet
@@ -147,12 +151,20 @@ DEFINITION:
 	} else if i.isHeadKeywordQuery() {
 		// Query
 		i.token = TokenDefQry
+		i.trailDef()
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head += len("query")
@@ -161,12 +173,20 @@ DEFINITION:
 	} else if i.isHeadKeywordMutation() {
 		// Mutation
 		i.token = TokenDefMut
+		i.trailDef()
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head += len("mutation")
@@ -175,12 +195,20 @@ DEFINITION:
 	} else if i.isHeadKeywordSubscription() {
 		// Subscription
 		i.token = TokenDefSub
+		i.trailDef()
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head += len("subscription")
@@ -190,12 +218,20 @@ DEFINITION:
 		// Fragment
 		i.tail = -1
 		i.token = TokenDefFrag
+		i.trailDef()
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head += len("fragment")
@@ -212,84 +248,12 @@ DEFINITION:
 AFTER_DEF_KEYWORD:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -312,10 +276,17 @@ AFTER_DEF_KEYWORD:
 		i.token = TokenVarList
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -339,285 +310,73 @@ AFTER_DEF_KEYWORD:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectOprName after name>
 	i.token = TokenOprName
+	i.defName = Span{i.tail, i.head}
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	}
+	/*</skip_irrelevant>*/
+
+	goto AFTER_OPR_NAME
+	// </ExpectOprName after name>
+
+	/*</name>*/
+
+	/*</l_after_def_keyword>*/
+
+	/*<l_after_dir_name>*/
+AFTER_DIR_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-	}
-	/*</skip_irrelevant>*/
-
-	goto AFTER_OPR_NAME
-	// </ExpectOprName after name>
-
-	/*</name>*/
-
-	/*</l_after_def_keyword>*/
-
-	/*<l_after_dir_name>*/
-AFTER_DIR_NAME:
-
-	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-	}
-	/*</skip_irrelevant>*/
-
-	switch dirOn {
-	case dirField:
-
-		/*<check_eof>*/
-		if i.head >= len(i.str) {
-			i.errc, i.expect = ErrUnexpEOF, ExpectAfterFieldName
-			goto ERROR
+	}
+	/*</skip_irrelevant>*/
+
+	switch dirOn {
+	case dirField:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectAfterFieldName
+			goto ERROR
 		}
 		/*</check_eof>*/
 
@@ -630,93 +389,28 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -752,93 +446,28 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -870,93 +499,28 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -991,93 +555,28 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
-
-			/*</callback>*/
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
+
+			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -1109,93 +608,28 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -1219,84 +653,12 @@ AFTER_DIR_NAME:
 AFTER_DIR_ARGS:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -1347,84 +709,12 @@ AFTER_DIR_ARGS:
 	case dirVar:
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -1442,8 +732,11 @@ AFTER_DIR_ARGS:
 			i.head++
 			i.expect = ExpectDir
 			goto DIR_NAME
+		case ')':
+			dirOn = 0
+			goto VAR_LIST_END
 		default:
-			i.expect, dirOn = ExpectAfterVarType, 0
+			i.expect, dirOn = ExpectVar, 0
 			goto OPR_VAR
 		}
 	case dirFragRef:
@@ -1483,7 +776,7 @@ AFTER_DIR_ARGS:
 			i.expect = ExpectDir
 			goto DIR_NAME
 		default:
-			i.expect = ExpectSelSet
+			i.expect, dirOn = ExpectSelSet, 0
 			goto SELECTION_SET
 		}
 	default:
@@ -1496,84 +789,12 @@ AFTER_DIR_ARGS:
 AFTER_KEYWORD_FRAGMENT:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -1599,91 +820,15 @@ AFTER_KEYWORD_FRAGMENT:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectFragName after name>
@@ -1694,12 +839,20 @@ AFTER_KEYWORD_FRAGMENT:
 		goto ERROR
 	}
 	i.token = TokenFragName
+	i.defName = Span{i.tail, i.head}
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect = ExpectFragKeywordOn
@@ -1714,84 +867,12 @@ AFTER_KEYWORD_FRAGMENT:
 OPR_VAR:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -1820,84 +901,12 @@ OPR_VAR:
 AFTER_VAR_TYPE:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -1923,84 +932,12 @@ AFTER_VAR_TYPE:
 		i.head++
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -2019,93 +956,28 @@ VAR_LIST_END:
 	i.token = TokenVarListEnd
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.head++
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -2133,87 +1005,22 @@ VAR_LIST_END:
 SELECTION_SET:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
 	if i.str[i.head] == '#' {
 		goto COMMENT
 	} else if i.str[i.head] != '{' {
@@ -2224,12 +1031,20 @@ SELECTION_SET:
 	i.token = TokenSet
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
+	i.parents = append(i.parents[:i.levelSel], i.field)
 	i.levelSel++
 	i.head++
 	i.expect = ExpectSel
@@ -2240,84 +1055,12 @@ SELECTION_SET:
 AFTER_SELECTION:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -2343,94 +1086,48 @@ SEL_END:
 	i.token = TokenSetEnd
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.levelSel--
-	i.head++
+	if i.levelSel < 1 {
+		// The selection set of the definition is complete.
+		i.token = TokenDefEnd
+		/*<callback>*/
 
-	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+		i.tokens++
+		if i.check != nil && i.check(i) {
+			i.errc = ErrCallbackFn
+			goto ERROR
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+
+		/*</callback>*/
+	}
+	i.head++
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -2444,84 +1141,12 @@ SEL_END:
 VALUE:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -2543,94 +1168,29 @@ VALUE:
 		i.token = TokenObj
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.stackPush(TokenObj)
 		i.head++
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -2647,183 +1207,42 @@ VALUE:
 		/*</check_eof>*/
 
 		i.tail = i.head
-		if i.str[i.head] != '_' &&
-			(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-			(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+		if !i.isHeadNameStart() {
 			i.errc = ErrUnexpToken
 			goto ERROR
 		}
-		i.head++
-		for {
-			if i.head+7 >= len(i.str) {
-				for ; i.head < len(i.str); i.head++ {
-					if i.str[i.head] == '_' ||
-						(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-						(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-						(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-						continue
-					} else if i.str[i.head] == ' ' ||
-						i.str[i.head] == '\n' ||
-						i.str[i.head] == '\r' ||
-						i.str[i.head] == '\t' ||
-						i.str[i.head] == ',' {
-						break
-					} else if i.str[i.head] < 0x20 {
-						i.errc = ErrUnexpToken
-						goto ERROR
-					}
-					break
-				}
-				break
-			}
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
+		for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+		}
+		if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+			i.errc = ErrUnexpToken
+			goto ERROR
 		}
 
 		// <ExpectObjFieldName after name>
 		i.token = TokenObjField
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -2842,84 +1261,12 @@ VALUE:
 		i.head++
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -2935,93 +1282,28 @@ VALUE:
 		i.token = TokenArr
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -3038,10 +1320,17 @@ VALUE:
 			i.token = TokenArrEnd
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
@@ -3067,181 +1356,79 @@ VALUE:
 		}
 
 		// String value
-		escaped := false
-		if i.head < len(i.str) && i.str[i.head] == '"' {
-			goto AFTER_STR_VAL
-		}
-		for {
-			for !escaped && i.head+7 < len(i.str) {
-				// Fast path
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
+		for i.head+7 < len(i.str) {
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
 			}
-			if i.head >= len(i.str) {
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
 				break
 			}
-			if i.str[i.head] < 0x20 {
-				i.errc = ErrUnexpToken
-				i.expect = ExpectEndOfString
-				goto ERROR
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
 			}
-			if escaped {
-				switch i.str[i.head] {
-				case '\\':
-					// Backslash
-					i.head++
-				case '/':
-					// Solidus
-					i.head++
-				case '"':
-					// Double-quotes
-					i.head++
-				case 'b':
-					// Backspace
-					i.head++
-				case 'f':
-					// Form-feed
-					i.head++
-				case 'r':
-					// Carriage-return
-					i.head++
-				case 'n':
-					// Line-break
-					i.head++
-				case 't':
-					// Tab
-					i.head++
-				case 'u':
-					// Unicode sequence
-					i.head++
-
-					/*<check_eof>*/
-					if i.head >= len(i.str) {
-						i.errc, i.expect = ErrUnexpEOF, ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					/*</check_eof>*/
-
-					if !i.isHeadHexDigit() {
-						i.errc = ErrUnexpToken
-						i.expect = ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					i.head++
-
-					/*<check_eof>*/
-					if i.head >= len(i.str) {
-						i.errc, i.expect = ErrUnexpEOF, ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					/*</check_eof>*/
-
-					if !i.isHeadHexDigit() {
-						i.errc = ErrUnexpToken
-						i.expect = ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					i.head++
-
-					/*<check_eof>*/
-					if i.head >= len(i.str) {
-						i.errc, i.expect = ErrUnexpEOF, ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					/*</check_eof>*/
-
-					if !i.isHeadHexDigit() {
-						i.errc = ErrUnexpToken
-						i.expect = ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					i.head++
-
-					/*<check_eof>*/
-					if i.head >= len(i.str) {
-						i.errc, i.expect = ErrUnexpEOF, ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					/*</check_eof>*/
-
-					if !i.isHeadHexDigit() {
-						i.errc = ErrUnexpToken
-						i.expect = ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-				default:
-					i.errc = ErrUnexpToken
-					i.expect = ExpectEscapedSequence
-					goto ERROR
-				}
-				escaped = false
-				continue
-			} else if i.str[i.head] == '"' {
-				goto AFTER_STR_VAL
-			} else if i.str[i.head] == '\\' {
-				escaped = true
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
 			}
 			i.head++
 		}
-		i.errc = ErrUnexpEOF
-		i.expect = ExpectEndOfString
-		goto ERROR
+		if i.head >= len(i.str) || i.str[i.head] != '"' {
+			// Escape sequence, control character, end of file
+			// or less than 8 bytes left
+			if i.errc = i.scanStr(); i.errc != 0 {
+				goto ERROR
+			}
+		}
 
-	AFTER_STR_VAL:
 		// Callback for argument
 		i.token = TokenStr
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		// Advance head index to include the closing double-quotes
@@ -3287,10 +1474,17 @@ VALUE:
 			i.token = TokenNull
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 		} else {
@@ -3307,100 +1501,31 @@ VALUE:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+
+			// <ExpectValEnum after name>
+			i.token = TokenEnumVal
+			/*<callback>*/
+
+			i.tokens++
+			if i.check != nil && i.check(i) {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
 				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-			}
-
-			// <ExpectValEnum after name>
-			i.token = TokenEnumVal
-			/*<callback>*/
-
-			if fn(i) {
-				i.errc = ErrCallbackFn
-				goto ERROR
 			}
 
 			/*</callback>*/
@@ -3439,10 +1564,17 @@ VALUE:
 			i.token = TokenTrue
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 		} else {
@@ -3459,101 +1591,32 @@ VALUE:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
 			}
 
 			// <ExpectValEnum after name>
 			i.token = TokenEnumVal
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.expect = ExpectAfterValueInner
@@ -3592,10 +1655,17 @@ VALUE:
 			i.token = TokenFalse
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 		} else {
@@ -3612,101 +1682,32 @@ VALUE:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
 			}
 
 			// <ExpectValEnum after name>
 			i.token = TokenEnumVal
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.expect = ExpectAfterValueInner
@@ -3718,161 +1719,31 @@ VALUE:
 		}
 	/*</false>*/
 
+	// GraphQL doesn't allow a leading '+' but scanNum
+	// rejects it as an invalid number value unless tolerated.
 	case '+', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 
 		/*<num>*/
 		// Number
 		i.tail = i.head
 
-		var s int
-
-		switch i.str[i.head] {
-		case '-':
-			// Signed
-			i.head++
-
-			/*<check_eof>*/
-			if i.head >= len(i.str) {
-				i.errc, i.expect = ErrUnexpEOF, ExpectVal
-				goto ERROR
-			}
-		/*</check_eof>*/
-
-		case '0':
-			// Leading zero
-			i.head++
-			if len(i.str) > i.head {
-				if i.str[i.head] == '.' {
-					i.head++
-					goto FRACTION
-				} else if i.str[i.head] == 'e' || i.str[i.head] == 'E' {
-					i.head++
-					goto EXPONENT_SIGN
-				} else if i.isHeadNumEnd() {
-					i.token = TokenInt
-					goto ON_NUM_VAL
-				} else {
-					i.errc = ErrInvalNum
-					i.expect = ExpectVal
-					goto ERROR
-				}
-			}
-		}
-
-		// Integer
-		for s = i.head; i.head < len(i.str); i.head++ {
-			if i.isHeadDigit() {
-				continue
-			} else if i.str[i.head] == '.' {
-				i.head++
-				goto FRACTION
-			} else if i.isHeadNumEnd() {
-				if i.head == s {
-					// Expected at least one digit
-					i.errc = ErrInvalNum
-					i.expect = ExpectVal
-					goto ERROR
-				}
-				// Integer
-				i.token = TokenInt
-				goto ON_NUM_VAL
-			} else if i.str[i.head] == 'e' || i.str[i.head] == 'E' {
-				i.head++
-				goto EXPONENT_SIGN
-			}
-
-			// Unexpected rune
-			i.errc = ErrInvalNum
-			i.expect = ExpectVal
-			goto ERROR
-		}
-
-		if i.head >= len(i.str) {
-			// Integer without exponent
-			i.token = TokenInt
-			goto ON_NUM_VAL
-		}
-		// Continue to fraction
-
-	FRACTION:
-		_ = 0 // Make code coverage count the label above
-		for s = i.head; i.head < len(i.str); i.head++ {
-			if i.isHeadDigit() {
-				continue
-			} else if i.isHeadNumEnd() {
-				if i.head == s {
-					// Expected at least one digit
-					i.errc = ErrInvalNum
-					i.expect = ExpectVal
-					goto ERROR
-				}
-				// Number with fraction
-				i.token = TokenFloat
-				goto ON_NUM_VAL
-			} else if i.str[i.head] == 'e' || i.str[i.head] == 'E' {
-				i.head++
-				goto EXPONENT_SIGN
-			}
-
-			// Unexpected rune
-			i.errc = ErrInvalNum
-			i.expect = ExpectVal
-			goto ERROR
-		}
-		if s == i.head {
-			// Unexpected end of number
-			i.errc = ErrUnexpEOF
-			i.expect = ExpectVal
-			goto ERROR
-		}
-
-		if i.head >= len(i.str) {
-			// Number (with fraction but) without exponent
-			i.token = TokenFloat
-			goto ON_NUM_VAL
-		}
-
-	EXPONENT_SIGN:
-
-		/*<check_eof>*/
-		if i.head >= len(i.str) {
-			i.errc, i.expect = ErrUnexpEOF, ExpectVal
+		if i.errc = i.scanNum(); i.errc != 0 {
 			goto ERROR
 		}
-		/*</check_eof>*/
-
-		if i.str[i.head] == '-' || i.str[i.head] == '+' {
-			i.head++
-		}
-		for s = i.head; i.head < len(i.str); i.head++ {
-			if i.isHeadDigit() {
-				continue
-			} else if i.isHeadNumEnd() {
-				if i.head == s {
-					// Expected at least one digit
-					i.errc = ErrInvalNum
-					i.expect = ExpectVal
-					goto ERROR
-				}
-				// Number with (fraction and) exponent
-				i.token = TokenFloat
-				goto ON_NUM_VAL
-			}
-			break
-		}
-		// Unexpected rune
-		i.errc = ErrInvalNum
-		i.expect = ExpectVal
-		goto ERROR
-
-	ON_NUM_VAL:
 		// Callback for argument
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 	/*</callback>*/
 	/*</num>*/
@@ -3892,101 +1763,32 @@ VALUE:
 		/*</check_eof>*/
 
 		i.tail = i.head
-		if i.str[i.head] != '_' &&
-			(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-			(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+		if !i.isHeadNameStart() {
 			i.errc = ErrUnexpToken
 			goto ERROR
 		}
-		i.head++
-		for {
-			if i.head+7 >= len(i.str) {
-				for ; i.head < len(i.str); i.head++ {
-					if i.str[i.head] == '_' ||
-						(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-						(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-						(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-						continue
-					} else if i.str[i.head] == ' ' ||
-						i.str[i.head] == '\n' ||
-						i.str[i.head] == '\r' ||
-						i.str[i.head] == '\t' ||
-						i.str[i.head] == ',' {
-						break
-					} else if i.str[i.head] < 0x20 {
-						i.errc = ErrUnexpToken
-						goto ERROR
-					}
-					break
-				}
-				break
-			}
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
+		for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+		}
+		if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+			i.errc = ErrUnexpToken
+			goto ERROR
 		}
 
 		// <ExpectValEnum after name>
 		i.token = TokenEnumVal
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.expect = ExpectAfterValueInner
@@ -4003,202 +1805,39 @@ VALUE:
 	/*<l_block_string>*/
 BLOCK_STRING:
 	i.expect = ExpectEndOfBlockString
-	for {
-		for i.head+7 < len(i.str) {
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-		}
-
-		/*<check_eof>*/
-		if i.head >= len(i.str) {
-			i.errc = ErrUnexpEOF
-			goto ERROR
-		}
-		/*</check_eof>*/
-
-		if i.str[i.head] == '\\' &&
-			i.str[i.head+3] == '"' &&
-			i.str[i.head+2] == '"' &&
-			i.str[i.head+1] == '"' {
-			i.head += len(`\"""`)
-			continue
-		} else if i.str[i.head] == '"' &&
-			i.str[i.head+2] == '"' &&
-			i.str[i.head+1] == '"' {
-			i.token = TokenStrBlock
-			/*<callback>*/
-
-			if fn(i) {
-				i.errc = ErrCallbackFn
-				goto ERROR
-			}
+	if i.errc = i.scanBlockStr(); i.errc != 0 {
+		goto ERROR
+	}
+	i.token = TokenStrBlock
+	/*<callback>*/
 
-			/*</callback>*/
-			i.head += len(`"""`)
-			goto AFTER_VALUE_INNER
-		} else if i.str[i.head] < 0x20 &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\r' {
-			i.errc = ErrUnexpToken
+	i.tokens++
+	if i.check != nil && i.check(i) {
+		i.errc = ErrCallbackFn
+		goto ERROR
+	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
 			goto ERROR
 		}
-		i.head++
 	}
+
+	/*</callback>*/
+	i.head += len(`"""`)
+	goto AFTER_VALUE_INNER
 	/*</l_block_string>*/
 
 	/*<l_after_value_inner>*/
 AFTER_VALUE_INNER:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -4221,93 +1860,28 @@ AFTER_VALUE_INNER:
 			i.token = TokenObjEnd
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -4330,183 +1904,42 @@ AFTER_VALUE_INNER:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
 			}
 
 			// <ExpectObjFieldName after name>
 			i.token = TokenObjField
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -4525,84 +1958,12 @@ AFTER_VALUE_INNER:
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -4622,93 +1983,28 @@ AFTER_VALUE_INNER:
 			i.token = TokenArrEnd
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -4758,10 +2054,17 @@ AFTER_VALUE_OUTER:
 		i.token = TokenArgListEnd
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -4783,183 +2086,42 @@ AFTER_VALUE_OUTER:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectArgName after name>
 	i.token = TokenArgName
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -4978,84 +2140,12 @@ AFTER_ARG_LIST:
 	}
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -5089,84 +2179,12 @@ AFTER_ARG_LIST:
 SELECTION:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -5195,175 +2213,27 @@ SELECTION:
 		/*</check_eof>*/
 
 		i.tail = i.head
-		if i.str[i.head] != '_' &&
-			(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-			(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+		if !i.isHeadNameStart() {
 			i.errc = ErrUnexpToken
 			goto ERROR
 		}
-		i.head++
-		for {
-			if i.head+7 >= len(i.str) {
-				for ; i.head < len(i.str); i.head++ {
-					if i.str[i.head] == '_' ||
-						(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-						(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-						(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-						continue
-					} else if i.str[i.head] == ' ' ||
-						i.str[i.head] == '\n' ||
-						i.str[i.head] == '\r' ||
-						i.str[i.head] == '\t' ||
-						i.str[i.head] == ',' {
-						break
-					} else if i.str[i.head] < 0x20 {
-						i.errc = ErrUnexpToken
-						goto ERROR
-					}
-					break
-				}
-				break
-			}
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
+		for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+		}
+		if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+			i.errc = ErrUnexpToken
+			goto ERROR
 		}
 
 		// <ExpectFieldNameOrAlias after name>
 		head := i.head
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -5380,93 +2250,28 @@ SELECTION:
 			i.token = TokenFieldAlias
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head = h2 + 1
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -5483,101 +2288,33 @@ SELECTION:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
 			}
 
 			// <ExpectFieldName after name>
 			i.token = TokenField
+			i.field = Span{i.tail, i.head}
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			goto AFTER_FIELD_NAME
@@ -5588,12 +2325,20 @@ SELECTION:
 		}
 		i.head = head
 		i.token = TokenField
+		i.field = Span{i.tail, i.head}
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		goto AFTER_FIELD_NAME
@@ -5631,84 +2376,12 @@ SELECTION:
 SPREAD:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -5719,30 +2392,45 @@ SPREAD:
 		goto COMMENT
 	} else if i.str[i.head] == '{' {
 		i.token, i.tail = TokenFragInline, -1
+		i.field = Span{}
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
-
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
+
 		/*</callback>*/
 		i.expect = ExpectSelSet
 		goto SELECTION_SET
 	} else if i.str[i.head] == '@' {
 		i.token, i.tail = TokenFragInline, -1
+		i.field = Span{}
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.expect, dirOn = ExpectDirName, dirFragInlineOrDef
 		goto AFTER_DIR_NAME
-	} else if i.str[i.head+1] == 'n' &&
-		i.str[i.head] == 'o' {
+	} else if i.isHeadKeywordOn() {
 		if i.head+2 >= len(i.str) {
 			i.head = len(i.str)
 			i.errc = ErrUnexpEOF
@@ -5773,101 +2461,39 @@ SPREAD:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectSpreadName after name>
+	if i.head-i.tail == 2 &&
+		i.str[i.tail+1] == 'n' &&
+		i.str[i.tail] == 'o' {
+		// "...on" not followed by a type condition
+		i.errc, i.head = ErrIllegalFragName, i.tail
+		goto ERROR
+	}
 	i.token = TokenNamedSpread
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect, dirOn = ExpectDirName, dirFragRef
@@ -5882,84 +2508,12 @@ SPREAD:
 AFTER_DECL_VAR_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -5985,84 +2539,12 @@ AFTER_DECL_VAR_NAME:
 VAR_TYPE:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -6080,10 +2562,17 @@ VAR_TYPE:
 		i.token = TokenVarTypeArr
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -6103,101 +2592,32 @@ VAR_TYPE:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectVarType after name>
 	i.token = TokenVarTypeName
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect = ExpectAfterVarTypeName
@@ -6212,84 +2632,12 @@ VAR_TYPE:
 VAR_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -6315,101 +2663,32 @@ VAR_NAME:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectVarName after name>
 	i.token = TokenVarName
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect = ExpectColumnAfterVar
@@ -6424,84 +2703,12 @@ VAR_NAME:
 VAR_REF_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -6527,101 +2734,32 @@ VAR_REF_NAME:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectVarRefName after name>
 	i.token = TokenVarRef
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect = ExpectAfterValueInner
@@ -6636,84 +2774,12 @@ VAR_REF_NAME:
 DIR_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -6740,103 +2806,35 @@ DIR_NAME:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectDirName after name>
 	i.token = TokenDirName
+	i.dir = Span{i.tail, i.head}
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
-
-	/*</callback>*/
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
+
+	/*</callback>*/
 	goto AFTER_DIR_NAME
 	// </ExpectDirName after name>
 
@@ -6848,84 +2846,12 @@ DIR_NAME:
 COLUMN_AFTER_ARG_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -6973,183 +2899,42 @@ ARG_LIST:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectArgName after name>
 	i.token = TokenArgName
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -7165,84 +2950,12 @@ ARG_LIST:
 AFTER_VAR_TYPE_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -7251,10 +2964,17 @@ AFTER_VAR_TYPE_NAME:
 		i.token = TokenVarTypeNotNull
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -7266,193 +2986,56 @@ AFTER_VAR_TYPE_NAME:
 AFTER_VAR_TYPE_NOT_NULL:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-	}
-	/*</skip_irrelevant>*/
-
-	/*<check_eof>*/
-	if i.head >= len(i.str) {
-		i.errc = ErrUnexpEOF
-		goto ERROR
-	}
-	/*</check_eof>*/
-
-	if i.str[i.head] == '#' {
-		goto COMMENT
-	} else if i.str[i.head] == ']' {
-		if typeArrLvl < 1 {
-			i.errc, i.expect = ErrUnexpToken, ExpectVar
-			goto ERROR
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if i.str[i.head] == ']' {
+		if typeArrLvl < 1 {
+			i.errc, i.expect = ErrUnexpToken, ExpectVar
+			goto ERROR
 		}
 		i.tail = -1
 		i.token = TokenVarTypeArrEnd
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
 		typeArrLvl--
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -7461,10 +3044,17 @@ AFTER_VAR_TYPE_NOT_NULL:
 			i.token = TokenVarTypeNotNull
 			/*<callback>*/
 
-			if fn(i) {
+			i.tokens++
+			if i.check != nil && i.check(i) {
 				i.errc = ErrCallbackFn
 				goto ERROR
 			}
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i) || i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
@@ -7482,84 +3072,12 @@ AFTER_VAR_TYPE_NOT_NULL:
 AFTER_FIELD_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -7578,93 +3096,28 @@ AFTER_FIELD_NAME:
 		i.token = TokenArgList
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -7690,84 +3143,12 @@ AFTER_FIELD_NAME:
 AFTER_OPR_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -7790,10 +3171,17 @@ AFTER_OPR_NAME:
 		i.token = TokenVarList
 		/*<callback>*/
 
-		if fn(i) {
+		i.tokens++
+		if i.check != nil && i.check(i) {
 			i.errc = ErrCallbackFn
 			goto ERROR
 		}
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i) || i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -7813,84 +3201,12 @@ AFTER_OPR_NAME:
 FRAG_KEYWORD_ON:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -7899,8 +3215,7 @@ FRAG_KEYWORD_ON:
 		goto ERROR
 	} else if i.str[i.head] == '#' {
 		goto COMMENT
-	} else if i.str[i.head+1] != 'n' ||
-		i.str[i.head] != 'o' {
+	} else if !i.isHeadKeywordOn() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
@@ -7911,84 +3226,12 @@ FRAG_KEYWORD_ON:
 FRAG_TYPE_COND:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -8014,183 +3257,42 @@ FRAG_TYPE_COND:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectFragTypeCond after name>
 	i.token = TokenFragTypeCond
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -8217,84 +3319,12 @@ FRAG_TYPE_COND:
 FRAG_INLINED:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -8320,101 +3350,33 @@ FRAG_INLINED:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectFragInlined after name>
 	i.token = TokenFragInline
+	i.field = Span{}
 	/*<callback>*/
 
-	if fn(i) {
+	i.tokens++
+	if i.check != nil && i.check(i) {
 		i.errc = ErrCallbackFn
 		goto ERROR
 	}
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i) || i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect, dirOn = ExpectDirName, dirFragInlineOrDef
@@ -8480,84 +3442,12 @@ COMMENT:
 	i.tail = -1
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -8625,84 +3515,12 @@ DEFINITION_END:
 	// Expect end of file
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -8719,11 +3537,19 @@ ERROR:
 		if i.head < len(i.str) {
 			atIndex, _ = utf8.DecodeRune(i.str[i.head:])
 		}
+		line, column := LineColumn(i.str, i.head)
+		def, token := i.ordinals(i.errc)
 		return Error{
-			Index:       i.head,
-			AtIndex:     atIndex,
-			Code:        i.errc,
-			Expectation: i.expect,
+			Index:           i.head,
+			AtIndex:         atIndex,
+			Code:            i.errc,
+			Expectation:     i.expect,
+			Line:            line,
+			Column:          column,
+			DefinitionIndex: def,
+			TokenOrdinal:    token,
+			Trail:           i.trail(dirOn, inDefVal),
+			Err:             i.abortErr,
 		}
 	}
 	/*</l_error>*/
@@ -8749,6 +3575,10 @@ func ScanAll(str []byte, fn func(*Iterator)) Error {
 	i.str = str
 	i.levelSel = 0
 	i.errc = 0
+	i.def = 0
+	i.defs, i.tokens = 0, 0
+	i.abortErr = nil
+	i.skipEnd = 0
 	defer iteratorPool.Put(i)
 
 	// inDefVal triggers different expectations after values
@@ -8758,84 +3588,12 @@ func ScanAll(str []byte, fn func(*Iterator)) Error {
 	var dirOn dirTarget
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -8855,9 +3613,16 @@ DEFINITION:
 		goto COMMENT
 	} else if i.str[i.head] == '{' {
 		i.token = TokenDefQry
+		i.trailDef()
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.expect = ExpectSelSet
@@ -8865,9 +3630,16 @@ DEFINITION:
 	} else if i.isHeadKeywordQuery() {
 		// Query
 		i.token = TokenDefQry
+		i.trailDef()
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head += len("query")
@@ -8876,9 +3648,16 @@ DEFINITION:
 	} else if i.isHeadKeywordMutation() {
 		// Mutation
 		i.token = TokenDefMut
+		i.trailDef()
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head += len("mutation")
@@ -8887,9 +3666,16 @@ DEFINITION:
 	} else if i.isHeadKeywordSubscription() {
 		// Subscription
 		i.token = TokenDefSub
+		i.trailDef()
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head += len("subscription")
@@ -8899,9 +3685,16 @@ DEFINITION:
 		// Fragment
 		i.tail = -1
 		i.token = TokenDefFrag
+		i.trailDef()
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head += len("fragment")
@@ -8918,84 +3711,12 @@ DEFINITION:
 AFTER_DEF_KEYWORD:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -9018,7 +3739,13 @@ AFTER_DEF_KEYWORD:
 		i.token = TokenVarList
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -9042,180 +3769,39 @@ AFTER_DEF_KEYWORD:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectOprName after name>
 	i.token = TokenOprName
+	i.defName = Span{i.tail, i.head}
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -9230,84 +3816,12 @@ AFTER_DEF_KEYWORD:
 AFTER_DIR_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -9330,90 +3844,24 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -9449,90 +3897,24 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -9564,90 +3946,24 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -9682,90 +3998,24 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -9797,90 +4047,24 @@ AFTER_DIR_NAME:
 			i.token = TokenArgList
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -9904,84 +4088,12 @@ AFTER_DIR_NAME:
 AFTER_DIR_ARGS:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -10032,84 +4144,12 @@ AFTER_DIR_ARGS:
 	case dirVar:
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -10127,8 +4167,11 @@ AFTER_DIR_ARGS:
 			i.head++
 			i.expect = ExpectDir
 			goto DIR_NAME
+		case ')':
+			dirOn = 0
+			goto VAR_LIST_END
 		default:
-			i.expect, dirOn = ExpectAfterVarType, 0
+			i.expect, dirOn = ExpectVar, 0
 			goto OPR_VAR
 		}
 	case dirFragRef:
@@ -10168,7 +4211,7 @@ AFTER_DIR_ARGS:
 			i.expect = ExpectDir
 			goto DIR_NAME
 		default:
-			i.expect = ExpectSelSet
+			i.expect, dirOn = ExpectSelSet, 0
 			goto SELECTION_SET
 		}
 	default:
@@ -10181,84 +4224,12 @@ AFTER_DIR_ARGS:
 AFTER_KEYWORD_FRAGMENT:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -10284,91 +4255,15 @@ AFTER_KEYWORD_FRAGMENT:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectFragName after name>
@@ -10379,9 +4274,16 @@ AFTER_KEYWORD_FRAGMENT:
 		goto ERROR
 	}
 	i.token = TokenFragName
+	i.defName = Span{i.tail, i.head}
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect = ExpectFragKeywordOn
@@ -10396,84 +4298,12 @@ AFTER_KEYWORD_FRAGMENT:
 OPR_VAR:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -10502,84 +4332,12 @@ OPR_VAR:
 AFTER_VAR_TYPE:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -10605,84 +4363,12 @@ AFTER_VAR_TYPE:
 		i.head++
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -10701,90 +4387,24 @@ VAR_LIST_END:
 	i.token = TokenVarListEnd
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.head++
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -10812,87 +4432,22 @@ VAR_LIST_END:
 SELECTION_SET:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
 	if i.str[i.head] == '#' {
 		goto COMMENT
 	} else if i.str[i.head] != '{' {
@@ -10903,9 +4458,16 @@ SELECTION_SET:
 	i.token = TokenSet
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
+	i.parents = append(i.parents[:i.levelSel], i.field)
 	i.levelSel++
 	i.head++
 	i.expect = ExpectSel
@@ -10916,84 +4478,12 @@ SELECTION_SET:
 AFTER_SELECTION:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -11019,182 +4509,59 @@ SEL_END:
 	i.token = TokenSetEnd
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.levelSel--
-	i.head++
+	if i.levelSel < 1 {
+		// The selection set of the definition is complete.
+		i.token = TokenDefEnd
+		/*<callback>*/
 
-	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
 			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+
+		/*</callback>*/
+	}
+	i.head++
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	}
+	/*</skip_irrelevant>*/
+
+	if i.levelSel < 1 {
+		goto DEFINITION_END
+	}
+	goto AFTER_SELECTION
+	/*</l_sel_end>*/
+
+	/*<l_value>*/
+VALUE:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-	}
-	/*</skip_irrelevant>*/
-
-	if i.levelSel < 1 {
-		goto DEFINITION_END
-	}
-	goto AFTER_SELECTION
-	/*</l_sel_end>*/
-
-	/*<l_value>*/
-VALUE:
-
-	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -11216,91 +4583,25 @@ VALUE:
 		i.token = TokenObj
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.stackPush(TokenObj)
 		i.head++
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -11317,180 +4618,38 @@ VALUE:
 		/*</check_eof>*/
 
 		i.tail = i.head
-		if i.str[i.head] != '_' &&
-			(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-			(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+		if !i.isHeadNameStart() {
 			i.errc = ErrUnexpToken
 			goto ERROR
 		}
-		i.head++
-		for {
-			if i.head+7 >= len(i.str) {
-				for ; i.head < len(i.str); i.head++ {
-					if i.str[i.head] == '_' ||
-						(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-						(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-						(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-						continue
-					} else if i.str[i.head] == ' ' ||
-						i.str[i.head] == '\n' ||
-						i.str[i.head] == '\r' ||
-						i.str[i.head] == '\t' ||
-						i.str[i.head] == ',' {
-						break
-					} else if i.str[i.head] < 0x20 {
-						i.errc = ErrUnexpToken
-						goto ERROR
-					}
-					break
-				}
-				break
-			}
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
+		for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+		}
+		if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+			i.errc = ErrUnexpToken
+			goto ERROR
 		}
 
 		// <ExpectObjFieldName after name>
 		i.token = TokenObjField
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -11509,84 +4668,12 @@ VALUE:
 		i.head++
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -11602,90 +4689,24 @@ VALUE:
 		i.token = TokenArr
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -11702,7 +4723,13 @@ VALUE:
 			i.token = TokenArrEnd
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
@@ -11728,178 +4755,75 @@ VALUE:
 		}
 
 		// String value
-		escaped := false
-		if i.head < len(i.str) && i.str[i.head] == '"' {
-			goto AFTER_STR_VAL
-		}
-		for {
-			for !escaped && i.head+7 < len(i.str) {
-				// Fast path
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
-				if i.str[i.head] == '"' ||
-					i.str[i.head] == '\\' ||
-					i.str[i.head] < 0x20 {
-					break
-				}
-				i.head++
+		for i.head+7 < len(i.str) {
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
 			}
-			if i.head >= len(i.str) {
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
 				break
 			}
-			if i.str[i.head] < 0x20 {
-				i.errc = ErrUnexpToken
-				i.expect = ExpectEndOfString
-				goto ERROR
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
 			}
-			if escaped {
-				switch i.str[i.head] {
-				case '\\':
-					// Backslash
-					i.head++
-				case '/':
-					// Solidus
-					i.head++
-				case '"':
-					// Double-quotes
-					i.head++
-				case 'b':
-					// Backspace
-					i.head++
-				case 'f':
-					// Form-feed
-					i.head++
-				case 'r':
-					// Carriage-return
-					i.head++
-				case 'n':
-					// Line-break
-					i.head++
-				case 't':
-					// Tab
-					i.head++
-				case 'u':
-					// Unicode sequence
-					i.head++
-
-					/*<check_eof>*/
-					if i.head >= len(i.str) {
-						i.errc, i.expect = ErrUnexpEOF, ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					/*</check_eof>*/
-
-					if !i.isHeadHexDigit() {
-						i.errc = ErrUnexpToken
-						i.expect = ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					i.head++
-
-					/*<check_eof>*/
-					if i.head >= len(i.str) {
-						i.errc, i.expect = ErrUnexpEOF, ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					/*</check_eof>*/
-
-					if !i.isHeadHexDigit() {
-						i.errc = ErrUnexpToken
-						i.expect = ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					i.head++
-
-					/*<check_eof>*/
-					if i.head >= len(i.str) {
-						i.errc, i.expect = ErrUnexpEOF, ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					/*</check_eof>*/
-
-					if !i.isHeadHexDigit() {
-						i.errc = ErrUnexpToken
-						i.expect = ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					i.head++
-
-					/*<check_eof>*/
-					if i.head >= len(i.str) {
-						i.errc, i.expect = ErrUnexpEOF, ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-					/*</check_eof>*/
-
-					if !i.isHeadHexDigit() {
-						i.errc = ErrUnexpToken
-						i.expect = ExpectEscapedUnicodeSequence
-						goto ERROR
-					}
-				default:
-					i.errc = ErrUnexpToken
-					i.expect = ExpectEscapedSequence
-					goto ERROR
-				}
-				escaped = false
-				continue
-			} else if i.str[i.head] == '"' {
-				goto AFTER_STR_VAL
-			} else if i.str[i.head] == '\\' {
-				escaped = true
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
 			}
 			i.head++
 		}
-		i.errc = ErrUnexpEOF
-		i.expect = ExpectEndOfString
-		goto ERROR
+		if i.head >= len(i.str) || i.str[i.head] != '"' {
+			// Escape sequence, control character, end of file
+			// or less than 8 bytes left
+			if i.errc = i.scanStr(); i.errc != 0 {
+				goto ERROR
+			}
+		}
 
-	AFTER_STR_VAL:
 		// Callback for argument
 		i.token = TokenStr
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		// Advance head index to include the closing double-quotes
@@ -11945,7 +4869,13 @@ VALUE:
 			i.token = TokenNull
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 		} else {
@@ -11962,98 +4892,28 @@ VALUE:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
 			}
 
 			// <ExpectValEnum after name>
 			i.token = TokenEnumVal
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.expect = ExpectAfterValueInner
@@ -12091,7 +4951,13 @@ VALUE:
 			i.token = TokenTrue
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 		} else {
@@ -12108,98 +4974,28 @@ VALUE:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
 			}
 
 			// <ExpectValEnum after name>
 			i.token = TokenEnumVal
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.expect = ExpectAfterValueInner
@@ -12238,7 +5034,13 @@ VALUE:
 			i.token = TokenFalse
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 		} else {
@@ -12255,98 +5057,28 @@ VALUE:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
 			}
 
 			// <ExpectValEnum after name>
 			i.token = TokenEnumVal
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.expect = ExpectAfterValueInner
@@ -12358,269 +5090,68 @@ VALUE:
 		}
 	/*</false>*/
 
+	// GraphQL doesn't allow a leading '+' but scanNum
+	// rejects it as an invalid number value unless tolerated.
 	case '+', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 
 		/*<num>*/
 		// Number
 		i.tail = i.head
 
-		var s int
-
-		switch i.str[i.head] {
-		case '-':
-			// Signed
-			i.head++
+		if i.errc = i.scanNum(); i.errc != 0 {
+			goto ERROR
+		}
+		// Callback for argument
+		/*<callback>*/
 
-			/*<check_eof>*/
-			if i.head >= len(i.str) {
-				i.errc, i.expect = ErrUnexpEOF, ExpectVal
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
 				goto ERROR
 			}
-		/*</check_eof>*/
-
-		case '0':
-			// Leading zero
-			i.head++
-			if len(i.str) > i.head {
-				if i.str[i.head] == '.' {
-					i.head++
-					goto FRACTION
-				} else if i.str[i.head] == 'e' || i.str[i.head] == 'E' {
-					i.head++
-					goto EXPONENT_SIGN
-				} else if i.isHeadNumEnd() {
-					i.token = TokenInt
-					goto ON_NUM_VAL
-				} else {
-					i.errc = ErrInvalNum
-					i.expect = ExpectVal
-					goto ERROR
-				}
-			}
 		}
 
-		// Integer
-		for s = i.head; i.head < len(i.str); i.head++ {
-			if i.isHeadDigit() {
-				continue
-			} else if i.str[i.head] == '.' {
-				i.head++
-				goto FRACTION
-			} else if i.isHeadNumEnd() {
-				if i.head == s {
-					// Expected at least one digit
-					i.errc = ErrInvalNum
-					i.expect = ExpectVal
-					goto ERROR
-				}
-				// Integer
-				i.token = TokenInt
-				goto ON_NUM_VAL
-			} else if i.str[i.head] == 'e' || i.str[i.head] == 'E' {
-				i.head++
-				goto EXPONENT_SIGN
-			}
+	/*</callback>*/
+	/*</num>*/
 
-			// Unexpected rune
-			i.errc = ErrInvalNum
-			i.expect = ExpectVal
-			goto ERROR
-		}
+	default:
+		// Invalid value
+		i.expect = ExpectValEnum
 
+		/*<name>*/
+		// Followed by valenum>
+
+		/*<check_eof>*/
 		if i.head >= len(i.str) {
-			// Integer without exponent
-			i.token = TokenInt
-			goto ON_NUM_VAL
+			i.errc = ErrUnexpEOF
+			goto ERROR
 		}
-		// Continue to fraction
-
-	FRACTION:
-		_ = 0 // Make code coverage count the label above
-		for s = i.head; i.head < len(i.str); i.head++ {
-			if i.isHeadDigit() {
-				continue
-			} else if i.isHeadNumEnd() {
-				if i.head == s {
-					// Expected at least one digit
-					i.errc = ErrInvalNum
-					i.expect = ExpectVal
-					goto ERROR
-				}
-				// Number with fraction
-				i.token = TokenFloat
-				goto ON_NUM_VAL
-			} else if i.str[i.head] == 'e' || i.str[i.head] == 'E' {
-				i.head++
-				goto EXPONENT_SIGN
-			}
+		/*</check_eof>*/
 
-			// Unexpected rune
-			i.errc = ErrInvalNum
-			i.expect = ExpectVal
+		i.tail = i.head
+		if !i.isHeadNameStart() {
+			i.errc = ErrUnexpToken
 			goto ERROR
 		}
-		if s == i.head {
-			// Unexpected end of number
-			i.errc = ErrUnexpEOF
-			i.expect = ExpectVal
+		for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+		}
+		if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+			i.errc = ErrUnexpToken
 			goto ERROR
 		}
 
-		if i.head >= len(i.str) {
-			// Number (with fraction but) without exponent
-			i.token = TokenFloat
-			goto ON_NUM_VAL
-		}
-
-	EXPONENT_SIGN:
-
-		/*<check_eof>*/
-		if i.head >= len(i.str) {
-			i.errc, i.expect = ErrUnexpEOF, ExpectVal
-			goto ERROR
-		}
-		/*</check_eof>*/
-
-		if i.str[i.head] == '-' || i.str[i.head] == '+' {
-			i.head++
-		}
-		for s = i.head; i.head < len(i.str); i.head++ {
-			if i.isHeadDigit() {
-				continue
-			} else if i.isHeadNumEnd() {
-				if i.head == s {
-					// Expected at least one digit
-					i.errc = ErrInvalNum
-					i.expect = ExpectVal
-					goto ERROR
-				}
-				// Number with (fraction and) exponent
-				i.token = TokenFloat
-				goto ON_NUM_VAL
-			}
-			break
-		}
-		// Unexpected rune
-		i.errc = ErrInvalNum
-		i.expect = ExpectVal
-		goto ERROR
-
-	ON_NUM_VAL:
-		// Callback for argument
-		/*<callback>*/
-
-		fn(i)
-
-	/*</callback>*/
-	/*</num>*/
-
-	default:
-		// Invalid value
-		i.expect = ExpectValEnum
-
-		/*<name>*/
-		// Followed by valenum>
-
-		/*<check_eof>*/
-		if i.head >= len(i.str) {
-			i.errc = ErrUnexpEOF
-			goto ERROR
-		}
-		/*</check_eof>*/
-
-		i.tail = i.head
-		if i.str[i.head] != '_' &&
-			(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-			(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
-			i.errc = ErrUnexpToken
-			goto ERROR
-		}
-		i.head++
-		for {
-			if i.head+7 >= len(i.str) {
-				for ; i.head < len(i.str); i.head++ {
-					if i.str[i.head] == '_' ||
-						(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-						(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-						(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-						continue
-					} else if i.str[i.head] == ' ' ||
-						i.str[i.head] == '\n' ||
-						i.str[i.head] == '\r' ||
-						i.str[i.head] == '\t' ||
-						i.str[i.head] == ',' {
-						break
-					} else if i.str[i.head] < 0x20 {
-						i.errc = ErrUnexpToken
-						goto ERROR
-					}
-					break
-				}
-				break
-			}
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-		}
-
 		// <ExpectValEnum after name>
 		i.token = TokenEnumVal
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.expect = ExpectAfterValueInner
@@ -12637,199 +5168,35 @@ VALUE:
 	/*<l_block_string>*/
 BLOCK_STRING:
 	i.expect = ExpectEndOfBlockString
-	for {
-		for i.head+7 < len(i.str) {
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-			if i.str[i.head] == '\\' ||
-				i.str[i.head] == '"' ||
-				(i.str[i.head] < 0x20 &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\r') {
-				break
-			}
-			i.head++
-		}
-
-		/*<check_eof>*/
-		if i.head >= len(i.str) {
-			i.errc = ErrUnexpEOF
-			goto ERROR
-		}
-		/*</check_eof>*/
-
-		if i.str[i.head] == '\\' &&
-			i.str[i.head+3] == '"' &&
-			i.str[i.head+2] == '"' &&
-			i.str[i.head+1] == '"' {
-			i.head += len(`\"""`)
-			continue
-		} else if i.str[i.head] == '"' &&
-			i.str[i.head+2] == '"' &&
-			i.str[i.head+1] == '"' {
-			i.token = TokenStrBlock
-			/*<callback>*/
-
-			fn(i)
+	if i.errc = i.scanBlockStr(); i.errc != 0 {
+		goto ERROR
+	}
+	i.token = TokenStrBlock
+	/*<callback>*/
 
-			/*</callback>*/
-			i.head += len(`"""`)
-			goto AFTER_VALUE_INNER
-		} else if i.str[i.head] < 0x20 &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\r' {
-			i.errc = ErrUnexpToken
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
 			goto ERROR
 		}
-		i.head++
 	}
+
+	/*</callback>*/
+	i.head += len(`"""`)
+	goto AFTER_VALUE_INNER
 	/*</l_block_string>*/
 
 	/*<l_after_value_inner>*/
 AFTER_VALUE_INNER:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -12852,90 +5219,24 @@ AFTER_VALUE_INNER:
 			i.token = TokenObjEnd
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -12958,180 +5259,38 @@ AFTER_VALUE_INNER:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
 			}
 
 			// <ExpectObjFieldName after name>
 			i.token = TokenObjField
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -13150,84 +5309,12 @@ AFTER_VALUE_INNER:
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -13247,90 +5334,24 @@ AFTER_VALUE_INNER:
 			i.token = TokenArrEnd
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -13380,7 +5401,13 @@ AFTER_VALUE_OUTER:
 		i.token = TokenArgListEnd
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -13402,180 +5429,38 @@ AFTER_VALUE_OUTER:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectArgName after name>
 	i.token = TokenArgName
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -13594,84 +5479,12 @@ AFTER_ARG_LIST:
 	}
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -13705,84 +5518,12 @@ AFTER_ARG_LIST:
 SELECTION:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -13811,175 +5552,27 @@ SELECTION:
 		/*</check_eof>*/
 
 		i.tail = i.head
-		if i.str[i.head] != '_' &&
-			(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-			(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+		if !i.isHeadNameStart() {
 			i.errc = ErrUnexpToken
 			goto ERROR
 		}
-		i.head++
-		for {
-			if i.head+7 >= len(i.str) {
-				for ; i.head < len(i.str); i.head++ {
-					if i.str[i.head] == '_' ||
-						(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-						(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-						(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-						continue
-					} else if i.str[i.head] == ' ' ||
-						i.str[i.head] == '\n' ||
-						i.str[i.head] == '\r' ||
-						i.str[i.head] == '\t' ||
-						i.str[i.head] == ',' {
-						break
-					} else if i.str[i.head] < 0x20 {
-						i.errc = ErrUnexpToken
-						goto ERROR
-					}
-					break
-				}
-				break
-			}
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
-			if !(i.str[i.head] == '_' ||
-				(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-				(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-				(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-				break
-			}
-			i.head++
+		for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+		}
+		if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+			i.errc = ErrUnexpToken
+			goto ERROR
 		}
 
 		// <ExpectFieldNameOrAlias after name>
 		head := i.head
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -13996,90 +5589,24 @@ SELECTION:
 			i.token = TokenFieldAlias
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head = h2 + 1
 
 			/*<skip_irrelevant>*/
-			for {
-				if i.head+7 >= len(i.str) {
-					for i.head < len(i.str) {
-						if i.str[i.head] != ',' &&
-							i.str[i.head] != ' ' &&
-							i.str[i.head] != '\n' &&
-							i.str[i.head] != '\t' &&
-							i.str[i.head] != '\r' {
-							break
-						}
-						i.head++
-					}
-					break
-				}
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
 				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
 			}
 			/*</skip_irrelevant>*/
 
@@ -14096,98 +5623,29 @@ SELECTION:
 			/*</check_eof>*/
 
 			i.tail = i.head
-			if i.str[i.head] != '_' &&
-				(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-				(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+			if !i.isHeadNameStart() {
 				i.errc = ErrUnexpToken
 				goto ERROR
 			}
-			i.head++
-			for {
-				if i.head+7 >= len(i.str) {
-					for ; i.head < len(i.str); i.head++ {
-						if i.str[i.head] == '_' ||
-							(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-							(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-							(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-							continue
-						} else if i.str[i.head] == ' ' ||
-							i.str[i.head] == '\n' ||
-							i.str[i.head] == '\r' ||
-							i.str[i.head] == '\t' ||
-							i.str[i.head] == ',' {
-							break
-						} else if i.str[i.head] < 0x20 {
-							i.errc = ErrUnexpToken
-							goto ERROR
-						}
-						break
-					}
-					break
-				}
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
-				if !(i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-					break
-				}
-				i.head++
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
 			}
 
 			// <ExpectFieldName after name>
 			i.token = TokenField
+			i.field = Span{i.tail, i.head}
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			goto AFTER_FIELD_NAME
@@ -14198,9 +5656,16 @@ SELECTION:
 		}
 		i.head = head
 		i.token = TokenField
+		i.field = Span{i.tail, i.head}
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		goto AFTER_FIELD_NAME
@@ -14238,84 +5703,12 @@ SELECTION:
 SPREAD:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -14326,24 +5719,37 @@ SPREAD:
 		goto COMMENT
 	} else if i.str[i.head] == '{' {
 		i.token, i.tail = TokenFragInline, -1
+		i.field = Span{}
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.expect = ExpectSelSet
 		goto SELECTION_SET
 	} else if i.str[i.head] == '@' {
 		i.token, i.tail = TokenFragInline, -1
+		i.field = Span{}
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.expect, dirOn = ExpectDirName, dirFragInlineOrDef
 		goto AFTER_DIR_NAME
-	} else if i.str[i.head+1] == 'n' &&
-		i.str[i.head] == 'o' {
+	} else if i.isHeadKeywordOn() {
 		if i.head+2 >= len(i.str) {
 			i.head = len(i.str)
 			i.errc = ErrUnexpEOF
@@ -14374,98 +5780,35 @@ SPREAD:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectSpreadName after name>
+	if i.head-i.tail == 2 &&
+		i.str[i.tail+1] == 'n' &&
+		i.str[i.tail] == 'o' {
+		// "...on" not followed by a type condition
+		i.errc, i.head = ErrIllegalFragName, i.tail
+		goto ERROR
+	}
 	i.token = TokenNamedSpread
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect, dirOn = ExpectDirName, dirFragRef
@@ -14480,84 +5823,12 @@ SPREAD:
 AFTER_DECL_VAR_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -14583,84 +5854,12 @@ AFTER_DECL_VAR_NAME:
 VAR_TYPE:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -14678,7 +5877,13 @@ VAR_TYPE:
 		i.token = TokenVarTypeArr
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -14698,98 +5903,28 @@ VAR_TYPE:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectVarType after name>
 	i.token = TokenVarTypeName
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect = ExpectAfterVarTypeName
@@ -14804,84 +5939,12 @@ VAR_TYPE:
 VAR_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -14907,98 +5970,28 @@ VAR_NAME:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectVarName after name>
 	i.token = TokenVarName
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect = ExpectColumnAfterVar
@@ -15013,84 +6006,12 @@ VAR_NAME:
 VAR_REF_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -15116,99 +6037,29 @@ VAR_REF_NAME:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectVarRefName after name>
+	i.token = TokenVarRef
+	/*<callback>*/
+
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
 		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
 	}
 
-	// <ExpectVarRefName after name>
-	i.token = TokenVarRef
-	/*<callback>*/
-
-	fn(i)
-
 	/*</callback>*/
 	i.expect = ExpectAfterValueInner
 	goto AFTER_VALUE_INNER
@@ -15222,84 +6073,12 @@ VAR_REF_NAME:
 DIR_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -15326,98 +6105,29 @@ DIR_NAME:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectDirName after name>
 	i.token = TokenDirName
+	i.dir = Span{i.tail, i.head}
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	goto AFTER_DIR_NAME
@@ -15431,84 +6141,12 @@ DIR_NAME:
 COLUMN_AFTER_ARG_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -15556,273 +6194,59 @@ ARG_LIST:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectArgName after name>
 	i.token = TokenArgName
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	}
+	/*</skip_irrelevant>*/
+
+	i.expect = ExpectColumnAfterArg
+	goto COLUMN_AFTER_ARG_NAME
+	// </ExpectArgName after name>
+
+	/*</name>*/
+
+	/*</l_arg_list>*/
+
+	/*<l_after_var_type_name>*/
+AFTER_VAR_TYPE_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-	}
-	/*</skip_irrelevant>*/
-
-	i.expect = ExpectColumnAfterArg
-	goto COLUMN_AFTER_ARG_NAME
-	// </ExpectArgName after name>
-
-	/*</name>*/
-
-	/*</l_arg_list>*/
-
-	/*<l_after_var_type_name>*/
-AFTER_VAR_TYPE_NAME:
-
-	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -15831,7 +6255,13 @@ AFTER_VAR_TYPE_NAME:
 		i.token = TokenVarTypeNotNull
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -15843,84 +6273,12 @@ AFTER_VAR_TYPE_NAME:
 AFTER_VAR_TYPE_NOT_NULL:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -15942,91 +6300,25 @@ AFTER_VAR_TYPE_NOT_NULL:
 		i.token = TokenVarTypeArrEnd
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
 		typeArrLvl--
 
 		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
 			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
 		}
 		/*</skip_irrelevant>*/
 
@@ -16035,7 +6327,13 @@ AFTER_VAR_TYPE_NOT_NULL:
 			i.token = TokenVarTypeNotNull
 			/*<callback>*/
 
-			fn(i)
+			i.tokens++
+			if i.skipEnd == 0 || !i.skipping() {
+				if fn(i); i.abortErr != nil {
+					i.errc = ErrCallbackFn
+					goto ERROR
+				}
+			}
 
 			/*</callback>*/
 			i.head++
@@ -16053,186 +6351,48 @@ AFTER_VAR_TYPE_NOT_NULL:
 AFTER_FIELD_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	// Lookahead
+	switch i.str[i.head] {
+	case '(':
+		// Argument list
+		i.tail = -1
+		i.token = TokenArgList
+		/*<callback>*/
+
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
 		}
+
+		/*</callback>*/
 		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-	}
-	/*</skip_irrelevant>*/
-
-	/*<check_eof>*/
-	if i.head >= len(i.str) {
-		i.errc = ErrUnexpEOF
-		goto ERROR
-	}
-	/*</check_eof>*/
-
-	// Lookahead
-	switch i.str[i.head] {
-	case '(':
-		// Argument list
-		i.tail = -1
-		i.token = TokenArgList
-		/*<callback>*/
-
-		fn(i)
-
-		/*</callback>*/
-		i.head++
-
-		/*<skip_irrelevant>*/
-		for {
-			if i.head+7 >= len(i.str) {
-				for i.head < len(i.str) {
-					if i.str[i.head] != ',' &&
-						i.str[i.head] != ' ' &&
-						i.str[i.head] != '\n' &&
-						i.str[i.head] != '\t' &&
-						i.str[i.head] != '\r' {
-						break
-					}
-					i.head++
-				}
-				break
-			}
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
-			if i.str[i.head] != ',' &&
-				i.str[i.head] != ' ' &&
-				i.str[i.head] != '\n' &&
-				i.str[i.head] != '\t' &&
-				i.str[i.head] != '\r' {
-				break
-			}
-			i.head++
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
 		}
 		/*</skip_irrelevant>*/
 
@@ -16258,84 +6418,12 @@ AFTER_FIELD_NAME:
 AFTER_OPR_NAME:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -16358,7 +6446,13 @@ AFTER_OPR_NAME:
 		i.token = TokenVarList
 		/*<callback>*/
 
-		fn(i)
+		i.tokens++
+		if i.skipEnd == 0 || !i.skipping() {
+			if fn(i); i.abortErr != nil {
+				i.errc = ErrCallbackFn
+				goto ERROR
+			}
+		}
 
 		/*</callback>*/
 		i.head++
@@ -16378,84 +6472,12 @@ AFTER_OPR_NAME:
 FRAG_KEYWORD_ON:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -16464,8 +6486,7 @@ FRAG_KEYWORD_ON:
 		goto ERROR
 	} else if i.str[i.head] == '#' {
 		goto COMMENT
-	} else if i.str[i.head+1] != 'n' ||
-		i.str[i.head] != 'o' {
+	} else if !i.isHeadKeywordOn() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
@@ -16476,84 +6497,12 @@ FRAG_KEYWORD_ON:
 FRAG_TYPE_COND:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -16579,180 +6528,38 @@ FRAG_TYPE_COND:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectFragTypeCond after name>
 	i.token = TokenFragTypeCond
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -16779,84 +6586,12 @@ FRAG_TYPE_COND:
 FRAG_INLINED:
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -16882,98 +6617,29 @@ FRAG_INLINED:
 	/*</check_eof>*/
 
 	i.tail = i.head
-	if i.str[i.head] != '_' &&
-		(i.str[i.head] < 'a' || i.str[i.head] > 'z') &&
-		(i.str[i.head] < 'A' || i.str[i.head] > 'Z') {
+	if !i.isHeadNameStart() {
 		i.errc = ErrUnexpToken
 		goto ERROR
 	}
-	i.head++
-	for {
-		if i.head+7 >= len(i.str) {
-			for ; i.head < len(i.str); i.head++ {
-				if i.str[i.head] == '_' ||
-					(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-					(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-					(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z') {
-					continue
-				} else if i.str[i.head] == ' ' ||
-					i.str[i.head] == '\n' ||
-					i.str[i.head] == '\r' ||
-					i.str[i.head] == '\t' ||
-					i.str[i.head] == ',' {
-					break
-				} else if i.str[i.head] < 0x20 {
-					i.errc = ErrUnexpToken
-					goto ERROR
-				}
-				break
-			}
-			break
-		}
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
-		if !(i.str[i.head] == '_' ||
-			(i.str[i.head] >= '0' && i.str[i.head] <= '9') ||
-			(i.str[i.head] >= 'a' && i.str[i.head] <= 'z') ||
-			(i.str[i.head] >= 'A' && i.str[i.head] <= 'Z')) {
-			break
-		}
-		i.head++
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
 	}
 
 	// <ExpectFragInlined after name>
 	i.token = TokenFragInline
+	i.field = Span{}
 	/*<callback>*/
 
-	fn(i)
+	i.tokens++
+	if i.skipEnd == 0 || !i.skipping() {
+		if fn(i); i.abortErr != nil {
+			i.errc = ErrCallbackFn
+			goto ERROR
+		}
+	}
 
 	/*</callback>*/
 	i.expect, dirOn = ExpectDirName, dirFragInlineOrDef
@@ -17039,84 +6705,12 @@ COMMENT:
 	i.tail = -1
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -17184,84 +6778,12 @@ DEFINITION_END:
 	// Expect end of file
 
 	/*<skip_irrelevant>*/
-	for {
-		if i.head+7 >= len(i.str) {
-			for i.head < len(i.str) {
-				if i.str[i.head] != ',' &&
-					i.str[i.head] != ' ' &&
-					i.str[i.head] != '\n' &&
-					i.str[i.head] != '\t' &&
-					i.str[i.head] != '\r' {
-					break
-				}
-				i.head++
-			}
-			break
-		}
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
-		}
-		i.head++
-		if i.str[i.head] != ',' &&
-			i.str[i.head] != ' ' &&
-			i.str[i.head] != '\n' &&
-			i.str[i.head] != '\t' &&
-			i.str[i.head] != '\r' {
-			break
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		i.head++
 	}
 	/*</skip_irrelevant>*/
 
@@ -17278,11 +6800,19 @@ ERROR:
 		if i.head < len(i.str) {
 			atIndex, _ = utf8.DecodeRune(i.str[i.head:])
 		}
+		line, column := LineColumn(i.str, i.head)
+		def, token := i.ordinals(i.errc)
 		return Error{
-			Index:       i.head,
-			AtIndex:     atIndex,
-			Code:        i.errc,
-			Expectation: i.expect,
+			Index:           i.head,
+			AtIndex:         atIndex,
+			Code:            i.errc,
+			Expectation:     i.expect,
+			Line:            line,
+			Column:          column,
+			DefinitionIndex: def,
+			TokenOrdinal:    token,
+			Trail:           i.trail(dirOn, inDefVal),
+			Err:             i.abortErr,
 		}
 	}
 	/*</l_error>*/
@@ -17291,323 +6821,3956 @@ ERROR:
 
 }
 
-// Iterator is a GraphQL iterator for lexical analysis.
-//
-// WARNING: An iterator instance shall never be aliased and/or used
-// after Scan or ScanAll returns because it's returned to a global pool!
-type Iterator struct {
-	// stack holds either TokenArr or TokenObj
-	// and is reset for every argument.
-	stack []Token
+// Validate returns an error if str isn't a valid document.
+// Validate is similar to ScanAll but doesn't call a function
+// for every token.
+func Validate(str []byte) Error {
 
-	expect Expect
-	token  Token
+	/*<scan_body>*/
+	i := iteratorPool.Get().(*Iterator)
+	i.stackReset()
+	i.expect = ExpectDef
+	i.tail, i.head = -1, 0
+	i.str = str
+	i.levelSel = 0
+	i.errc = 0
+	i.def = 0
+	i.defs, i.tokens = 0, 0
+	i.abortErr = nil
+	i.skipEnd = 0
+	defer iteratorPool.Put(i)
 
-	// str holds the original source
-	str []byte
+	// inDefVal triggers different expectations after values
+	// when the iterator is in a variable default value definition.
+	var inDefVal bool
+	var typeArrLvl int
+	var dirOn dirTarget
 
-	// tail and head represent the iterator tail and head indexes
-	tail, head int
-	levelSel   int
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
 
-	// errc holds the recent error code
-	errc ErrorCode
-}
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc, i.expect = ErrUnexpEOF, ExpectDef
+		goto ERROR
+	}
+	/*</check_eof>*/
 
-func (i *Iterator) stackReset() {
-	i.stack = i.stack[:0]
-}
+	/*<l_definition>*/
+DEFINITION:
+	if i.head >= len(i.str) {
+		goto DEFINITION_END
+	} else if i.str[i.head] == '#' {
+		i.expect = ExpectDef
+		goto COMMENT
+	} else if i.str[i.head] == '{' {
+		i.token = TokenDefQry
+		i.trailDef()
+		/*<callback>*/
 
-func (i *Iterator) stackLen() int {
-	return len(i.stack)
-}
+		i.tokens++
 
-// stackPush pushes a new token onto the stack.
-func (i *Iterator) stackPush(t Token) {
-	i.stack = append(i.stack, t)
-}
+		/*</callback>*/
+		i.expect = ExpectSelSet
+		goto SELECTION_SET
+	} else if i.isHeadKeywordQuery() {
+		// Query
+		i.token = TokenDefQry
+		i.trailDef()
+		/*<callback>*/
 
-// stackPop pops the top element of the stack returning it.
-// Returns 0 if the stack was empty.
-func (i *Iterator) stackPop() {
-	if l := len(i.stack); l > 0 {
-		i.stack = i.stack[:l-1]
-	}
-}
+		i.tokens++
 
-// stackTop returns the last pushed token.
-func (i *Iterator) stackTop() Token {
-	if l := len(i.stack); l > 0 {
-		return i.stack[l-1]
-	}
-	return 0
-}
+		/*</callback>*/
+		i.head += len("query")
+		i.expect = ExpectAfterDefKeyword
+		goto AFTER_DEF_KEYWORD
+	} else if i.isHeadKeywordMutation() {
+		// Mutation
+		i.token = TokenDefMut
+		i.trailDef()
+		/*<callback>*/
 
-var iteratorPool = sync.Pool{
-	New: func() interface{} {
-		return &Iterator{
-			stack: make([]Token, 64),
-		}
-	},
-}
+		i.tokens++
 
-// LevelSelect returns the current selector level.
-func (i *Iterator) LevelSelect() int {
-	return i.levelSel
-}
+		/*</callback>*/
+		i.head += len("mutation")
+		i.expect = ExpectAfterDefKeyword
+		goto AFTER_DEF_KEYWORD
+	} else if i.isHeadKeywordSubscription() {
+		// Subscription
+		i.token = TokenDefSub
+		i.trailDef()
+		/*<callback>*/
 
-// IndexHead returns the current head index.
-func (i *Iterator) IndexHead() int {
-	return i.head
-}
+		i.tokens++
 
-// IndexTail returns the current tail index.
-// Returns -1 if the current token doesn't reflect a dynamic value.
-func (i *Iterator) IndexTail() int {
-	return i.tail
-}
+		/*</callback>*/
+		i.head += len("subscription")
+		i.expect = ExpectAfterDefKeyword
+		goto AFTER_DEF_KEYWORD
+	} else if i.isHeadKeywordFragment() {
+		// Fragment
+		i.tail = -1
+		i.token = TokenDefFrag
+		i.trailDef()
+		/*<callback>*/
 
-// Token returns the current token type.
-func (i *Iterator) Token() Token {
-	return i.token
-}
+		i.tokens++
 
-// Value returns the raw value of the current token.
-// For TokenStrBlock it's the raw uninterpreted body of the string,
-// use ScanInterpreted for the interpreted value of the block string.
-//
-// WARNING: The returned byte slice refers to the same underlying memory
-// as the byte slice passed to Scan and ScanAll as str parameter,
-// copy it or use with caution!
-func (i *Iterator) Value() []byte {
-	if i.tail < 0 {
-		return nil
+		/*</callback>*/
+		i.head += len("fragment")
+		i.expect = ExpectFragName
+		goto AFTER_KEYWORD_FRAGMENT
 	}
-	return i.str[i.tail:i.head]
-}
 
-// ScanInterpreted calls fn writing the interpreted part of
-// the value to buffer as long as fn doesn't return true and
-// the scan didn't reach the end of the interpreted value.
-func (i *Iterator) ScanInterpreted(
-	buffer []byte,
-	fn func(buffer []byte) (stop bool),
-) {
-	if len(buffer) < 1 {
-		return
-	}
-	if i.token != TokenStrBlock {
-		offset := 0
-		for offset < len(i.Value()) {
-			b := buffer
-			v := i.Value()[offset:]
-			if len(v) > len(b) {
-				v = v[:len(b)]
-			} else {
-				b = b[:len(v)]
-			}
-			copy(b, v)
-			if fn(b) {
-				return
-			}
-			offset += len(v)
+	i.errc = ErrUnexpToken
+	i.expect = ExpectDef
+	goto ERROR
+	/*</l_definition>*/
+
+	/*<l_after_def_keyword>*/
+AFTER_DEF_KEYWORD:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
 		}
-		return
 	}
+	/*</skip_irrelevant>*/
 
-	// Determine block prefix
-	shortestPrefixLen := 0
-	v := i.Value()
-	start, end := 0, len(v)
-	{
-		lastLineBreak := 0
-		for i := range v {
-			if v[i] == '\n' {
-				lastLineBreak = i
-			}
-			if v[i] != '\n' && v[i] != ' ' && v[i] != '\t' {
-				start = lastLineBreak
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	switch i.str[i.head] {
+	case '#':
+		goto COMMENT
+	case '{':
+		i.expect = ExpectSelSet
+		goto SELECTION_SET
+	case '(':
+		// Variable list
+		i.tail = -1
+		i.token = TokenVarList
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.head++
+		i.expect = ExpectVar
+		goto OPR_VAR
+	case '@':
+		i.head++
+		dirOn, i.expect = dirOpr, ExpectDir
+		goto DIR_NAME
+	}
+	i.expect = ExpectOprName
+
+	/*<name>*/
+	// Followed by oprname>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectOprName after name>
+	i.token = TokenOprName
+	i.defName = Span{i.tail, i.head}
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	goto AFTER_OPR_NAME
+	// </ExpectOprName after name>
+
+	/*</name>*/
+
+	/*</l_after_def_keyword>*/
+
+	/*<l_after_dir_name>*/
+AFTER_DIR_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	switch dirOn {
+	case dirField:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectAfterFieldName
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '(':
+			// Directive argument list
+			i.tail = -1
+			i.token = TokenArgList
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head++
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			i.expect = ExpectArgName
+			goto ARG_LIST
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		case '{':
+			// Field selector expands without arguments
+			i.expect = ExpectSelSet
+			goto SELECTION_SET
+		default:
+			i.expect, dirOn = ExpectAfterSelection, 0
+			goto AFTER_SELECTION
+		}
+	case dirOpr:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectAfterDefKeyword
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '(':
+			// Directive argument list
+			i.tail = -1
+			i.token = TokenArgList
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head++
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			i.expect = ExpectArgName
+			goto ARG_LIST
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		default:
+			i.expect, dirOn = ExpectSelSet, 0
+			goto SELECTION_SET
+		}
+	case dirVar:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectAfterVarType
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '(':
+			// Directive argument list
+			i.tail = -1
+			i.token = TokenArgList
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head++
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			i.expect = ExpectArgName
+			goto ARG_LIST
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		case ')':
+			dirOn = 0
+			goto VAR_LIST_END
+		default:
+			i.expect, dirOn = ExpectVar, 0
+			goto OPR_VAR
+		}
+	case dirFragRef:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectAfterSelection
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '(':
+			// Directive argument list
+			i.tail = -1
+			i.token = TokenArgList
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head++
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			i.expect = ExpectArgName
+			goto ARG_LIST
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		default:
+			i.expect, dirOn = ExpectAfterSelection, 0
+			goto AFTER_SELECTION
+		}
+	case dirFragInlineOrDef:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectSelSet
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '(':
+			// Directive argument list
+			i.tail = -1
+			i.token = TokenArgList
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head++
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			i.expect = ExpectArgName
+			goto ARG_LIST
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		default:
+			i.expect, dirOn = ExpectSelSet, 0
+			goto SELECTION_SET
+		}
+	default:
+		// This line is only executed if we forgot to handle a dirOn case.
+		panic(fmt.Errorf("unhandled dirOn case: %#v", dirOn))
+	}
+	/*</l_after_dir_name>*/
+
+	/*<l_after_dir_args>*/
+AFTER_DIR_ARGS:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	switch dirOn {
+	case dirField:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectAfterFieldName
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		case '{':
+			i.expect = ExpectSelSet
+			goto SELECTION_SET
+		default:
+			i.expect, dirOn = ExpectAfterSelection, 0
+			goto AFTER_SELECTION
+		}
+	case dirOpr:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectAfterDefKeyword
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		default:
+			i.expect, dirOn = ExpectSelSet, 0
+			goto SELECTION_SET
+		}
+	case dirVar:
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
+		}
+		/*</skip_irrelevant>*/
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectAfterVarType
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		case ')':
+			dirOn = 0
+			goto VAR_LIST_END
+		default:
+			i.expect, dirOn = ExpectVar, 0
+			goto OPR_VAR
+		}
+	case dirFragRef:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectAfterSelection
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		default:
+			i.expect, dirOn = ExpectAfterSelection, 0
+			goto AFTER_SELECTION
+		}
+	case dirFragInlineOrDef:
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectSelSet
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		switch i.str[i.head] {
+		case '#':
+			goto COMMENT
+		case '@':
+			i.head++
+			i.expect = ExpectDir
+			goto DIR_NAME
+		default:
+			i.expect, dirOn = ExpectSelSet, 0
+			goto SELECTION_SET
+		}
+	default:
+		// This line is only executed if we forgot to handle a dirOn case.
+		panic(fmt.Errorf("unhandled dirOn case: %#v", dirOn))
+	}
+	/*</l_after_dir_args>*/
+
+	/*<l_after_keyword_fragment>*/
+AFTER_KEYWORD_FRAGMENT:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+
+	/*<name>*/
+	// Followed by fragname>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectFragName after name>
+	if i.head-i.tail == 2 &&
+		i.str[i.tail+1] == 'n' &&
+		i.str[i.tail] == 'o' {
+		i.errc, i.head = ErrIllegalFragName, i.tail
+		goto ERROR
+	}
+	i.token = TokenFragName
+	i.defName = Span{i.tail, i.head}
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.expect = ExpectFragKeywordOn
+	goto FRAG_KEYWORD_ON
+	// </ExpectFragName after name>
+
+	/*</name>*/
+
+	/*</l_after_keyword_fragment>*/
+
+	/*<l_opr_var>*/
+OPR_VAR:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+
+	// Variable name
+	if i.str[i.head] != '$' {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	i.head++
+	i.expect = ExpectVarName
+	goto VAR_NAME
+	/*</l_opr_var>*/
+
+	/*<l_after_var_type>*/
+AFTER_VAR_TYPE:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if typeArrLvl != 0 {
+		i.head--
+		i.errc = ErrInvalType
+		i.expect = ExpectVarType
+		goto ERROR
+	} else if i.str[i.head] == '@' {
+		i.head++
+		dirOn, i.expect = dirVar, ExpectDir
+		goto DIR_NAME
+	} else if i.str[i.head] == '=' {
+		i.head++
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
+		}
+		/*</skip_irrelevant>*/
+
+		i.expect, inDefVal = ExpectVal, true
+		goto VALUE
+	} else if i.str[i.head] == ')' {
+		goto VAR_LIST_END
+	}
+	i.expect = ExpectAfterVarType
+	goto OPR_VAR
+	/*</l_after_var_type>*/
+
+	/*<l_var_list_end>*/
+VAR_LIST_END:
+	i.tail = -1
+	i.token = TokenVarListEnd
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.head++
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	i.expect = ExpectSelSet
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		dirOn, i.expect = dirOpr, ExpectDirName
+		goto AFTER_DIR_NAME
+	} else if i.str[i.head] == '@' {
+		i.head++
+		dirOn, i.expect = dirOpr, ExpectDir
+		goto DIR_NAME
+	}
+	goto SELECTION_SET
+	/*</l_var_list_end>*/
+
+	/*<l_selection_set>*/
+SELECTION_SET:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if i.str[i.head] != '{' {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	i.tail = -1
+	i.token = TokenSet
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.parents = append(i.parents[:i.levelSel], i.field)
+	i.levelSel++
+	i.head++
+	i.expect = ExpectSel
+	goto SELECTION
+	/*</l_selection_set>*/
+
+	/*<l_after_selection>*/
+AFTER_SELECTION:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if i.str[i.head] == '}' {
+		goto SEL_END
+	}
+	i.expect = ExpectSel
+	goto SELECTION
+	/*</l_after_selection>*/
+
+	/*<l_sel_end>*/
+SEL_END:
+	i.tail = -1
+	i.token = TokenSetEnd
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.levelSel--
+	if i.levelSel < 1 {
+		// The selection set of the definition is complete.
+		i.token = TokenDefEnd
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+	}
+	i.head++
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	if i.levelSel < 1 {
+		goto DEFINITION_END
+	}
+	goto AFTER_SELECTION
+	/*</l_sel_end>*/
+
+	/*<l_value>*/
+VALUE:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	switch i.str[i.head] {
+	case '#':
+		goto COMMENT
+
+	case '{':
+		// Object begin
+		i.tail = -1
+		// Callback for argument
+		i.token = TokenObj
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.stackPush(TokenObj)
+		i.head++
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
+		}
+		/*</skip_irrelevant>*/
+
+		i.expect = ExpectObjFieldName
+
+		/*<name>*/
+		// Followed by objfieldname>
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc = ErrUnexpEOF
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		i.tail = i.head
+		if !i.isHeadNameStart() {
+			i.errc = ErrUnexpToken
+			goto ERROR
+		}
+		for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+		}
+		if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+			i.errc = ErrUnexpToken
+			goto ERROR
+		}
+
+		// <ExpectObjFieldName after name>
+		i.token = TokenObjField
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
+		}
+		/*</skip_irrelevant>*/
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectColObjFieldName
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		if i.str[i.head] != ':' {
+			i.errc = ErrUnexpToken
+			i.expect = ExpectColObjFieldName
+			goto ERROR
+		}
+		i.head++
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
+		}
+		/*</skip_irrelevant>*/
+
+		i.expect = ExpectVal
+		goto VALUE
+	// </ExpectObjFieldName after name>
+
+	/*</name>*/
+
+	case '[':
+		i.tail = -1
+		// Callback for argument
+		i.token = TokenArr
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.head++
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
+		}
+		/*</skip_irrelevant>*/
+
+		// Lookahead
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc, i.expect = ErrUnexpEOF, ExpectVal
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		if i.str[i.head] == ']' {
+			i.token = TokenArrEnd
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head++
+			i.expect = ExpectAfterValueInner
+			goto AFTER_VALUE_INNER
+		}
+		i.stackPush(TokenArr)
+		i.expect = ExpectAfterValueInner
+		goto AFTER_VALUE_INNER
+
+	case '"':
+
+		/*<str>*/
+		i.head++
+		i.tail = i.head
+
+		if i.head+1 < len(i.str) &&
+			i.str[i.head] == '"' &&
+			i.str[i.head+1] == '"' {
+			i.head += 2
+			i.tail = i.head
+			goto BLOCK_STRING
+		}
+
+		// String value
+		for i.head+7 < len(i.str) {
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+		}
+		if i.head >= len(i.str) || i.str[i.head] != '"' {
+			// Escape sequence, control character, end of file
+			// or less than 8 bytes left
+			if i.errc = i.scanStr(); i.errc != 0 {
+				goto ERROR
+			}
+		}
+
+		// Callback for argument
+		i.token = TokenStr
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		// Advance head index to include the closing double-quotes
+		i.head++
+	/*</str>*/
+
+	case '$':
+		if inDefVal {
+			i.errc, i.expect = ErrUnexpToken, ExpectDefaultVarVal
+			goto ERROR
+		}
+
+		// Variable reference
+		i.head++
+
+		// Variable name
+		i.expect = ExpectVarRefName
+		goto VAR_REF_NAME
+
+	case 'n':
+
+		/*<null>*/
+		if i.head+4 < len(i.str) &&
+			i.str[i.head+3] == 'l' &&
+			i.str[i.head+2] == 'l' &&
+			i.str[i.head+1] == 'u' &&
+			i.str[i.head] == 'n' &&
+			(i.str[i.head+4] == ' ' ||
+				i.str[i.head+4] == '\t' ||
+				i.str[i.head+4] == '\r' ||
+				i.str[i.head+4] == '\n' ||
+				i.str[i.head+4] == ',' ||
+				i.str[i.head+4] == ')' ||
+				i.str[i.head+4] == '}' ||
+				i.str[i.head+4] == '{' ||
+				i.str[i.head+4] == ']' ||
+				i.str[i.head+4] == '[' ||
+				i.str[i.head+4] == '#') {
+			i.tail = -1
+			i.head += len("null")
+
+			// Callback for null value
+			i.token = TokenNull
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+		} else {
+			i.expect = ExpectValEnum
+
+			/*<name>*/
+			// Followed by valenum>
+
+			/*<check_eof>*/
+			if i.head >= len(i.str) {
+				i.errc = ErrUnexpEOF
+				goto ERROR
+			}
+			/*</check_eof>*/
+
+			i.tail = i.head
+			if !i.isHeadNameStart() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+
+			// <ExpectValEnum after name>
+			i.token = TokenEnumVal
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.expect = ExpectAfterValueInner
+			goto AFTER_VALUE_INNER
+			// </ExpectValEnum after name>
+
+			/*</name>*/
+
+		}
+	/*</null>*/
+
+	case 't':
+
+		/*<true>*/
+		if i.head+4 < len(i.str) &&
+			i.str[i.head+3] == 'e' &&
+			i.str[i.head+2] == 'u' &&
+			i.str[i.head+1] == 'r' &&
+			i.str[i.head] == 't' &&
+			(i.str[i.head+4] == ' ' ||
+				i.str[i.head+4] == '\t' ||
+				i.str[i.head+4] == '\r' ||
+				i.str[i.head+4] == '\n' ||
+				i.str[i.head+4] == ',' ||
+				i.str[i.head+4] == ')' ||
+				i.str[i.head+4] == '}' ||
+				i.str[i.head+4] == '{' ||
+				i.str[i.head+4] == ']' ||
+				i.str[i.head+4] == '[' ||
+				i.str[i.head+4] == '#') {
+			i.tail = -1
+			i.head += len("true")
+
+			// Callback for true value
+			i.token = TokenTrue
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+		} else {
+			i.expect = ExpectValEnum
+
+			/*<name>*/
+			// Followed by valenum>
+
+			/*<check_eof>*/
+			if i.head >= len(i.str) {
+				i.errc = ErrUnexpEOF
+				goto ERROR
+			}
+			/*</check_eof>*/
+
+			i.tail = i.head
+			if !i.isHeadNameStart() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+
+			// <ExpectValEnum after name>
+			i.token = TokenEnumVal
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.expect = ExpectAfterValueInner
+			goto AFTER_VALUE_INNER
+			// </ExpectValEnum after name>
+
+			/*</name>*/
+
+		}
+	/*</true>*/
+
+	case 'f':
+
+		/*<false>*/
+		if i.head+5 < len(i.str) &&
+			i.str[i.head+4] == 'e' &&
+			i.str[i.head+3] == 's' &&
+			i.str[i.head+2] == 'l' &&
+			i.str[i.head+1] == 'a' &&
+			i.str[i.head] == 'f' &&
+			(i.str[i.head+5] == ' ' ||
+				i.str[i.head+5] == '\t' ||
+				i.str[i.head+5] == '\r' ||
+				i.str[i.head+5] == '\n' ||
+				i.str[i.head+5] == ',' ||
+				i.str[i.head+5] == ')' ||
+				i.str[i.head+5] == '}' ||
+				i.str[i.head+5] == '{' ||
+				i.str[i.head+5] == ']' ||
+				i.str[i.head+5] == '[' ||
+				i.str[i.head+5] == '#') {
+			i.tail = -1
+			i.head += len("false")
+
+			// Callback for false value
+			i.token = TokenFalse
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+		} else {
+			i.expect = ExpectValEnum
+
+			/*<name>*/
+			// Followed by valenum>
+
+			/*<check_eof>*/
+			if i.head >= len(i.str) {
+				i.errc = ErrUnexpEOF
+				goto ERROR
+			}
+			/*</check_eof>*/
+
+			i.tail = i.head
+			if !i.isHeadNameStart() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+
+			// <ExpectValEnum after name>
+			i.token = TokenEnumVal
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.expect = ExpectAfterValueInner
+			goto AFTER_VALUE_INNER
+			// </ExpectValEnum after name>
+
+			/*</name>*/
+
+		}
+	/*</false>*/
+
+	// GraphQL doesn't allow a leading '+' but scanNum
+	// rejects it as an invalid number value unless tolerated.
+	case '+', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+
+		/*<num>*/
+		// Number
+		i.tail = i.head
+
+		if i.errc = i.scanNum(); i.errc != 0 {
+			goto ERROR
+		}
+		// Callback for argument
+		/*<callback>*/
+
+		i.tokens++
+
+	/*</callback>*/
+	/*</num>*/
+
+	default:
+		// Invalid value
+		i.expect = ExpectValEnum
+
+		/*<name>*/
+		// Followed by valenum>
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc = ErrUnexpEOF
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		i.tail = i.head
+		if !i.isHeadNameStart() {
+			i.errc = ErrUnexpToken
+			goto ERROR
+		}
+		for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+		}
+		if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+			i.errc = ErrUnexpToken
+			goto ERROR
+		}
+
+		// <ExpectValEnum after name>
+		i.token = TokenEnumVal
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.expect = ExpectAfterValueInner
+		goto AFTER_VALUE_INNER
+		// </ExpectValEnum after name>
+
+		/*</name>*/
+
+	}
+	i.expect = ExpectAfterValueInner
+	goto AFTER_VALUE_INNER
+	/*</l_value>*/
+
+	/*<l_block_string>*/
+BLOCK_STRING:
+	i.expect = ExpectEndOfBlockString
+	if i.errc = i.scanBlockStr(); i.errc != 0 {
+		goto ERROR
+	}
+	i.token = TokenStrBlock
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.head += len(`"""`)
+	goto AFTER_VALUE_INNER
+	/*</l_block_string>*/
+
+	/*<l_after_value_inner>*/
+AFTER_VALUE_INNER:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+	if t := i.stackTop(); t == TokenObj {
+		if i.str[i.head] == '}' {
+			i.tail = -1
+			i.stackPop()
+
+			// Callback for end of object
+			i.token = TokenObjEnd
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head++
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			if i.stackLen() > 0 {
+				i.expect = ExpectAfterValueInner
+				goto AFTER_VALUE_INNER
+			}
+		} else {
+			// Proceed to next field in the object
+			i.expect = ExpectObjFieldName
+
+			/*<name>*/
+			// Followed by objfieldname>
+
+			/*<check_eof>*/
+			if i.head >= len(i.str) {
+				i.errc = ErrUnexpEOF
+				goto ERROR
+			}
+			/*</check_eof>*/
+
+			i.tail = i.head
+			if !i.isHeadNameStart() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+
+			// <ExpectObjFieldName after name>
+			i.token = TokenObjField
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			/*<check_eof>*/
+			if i.head >= len(i.str) {
+				i.errc, i.expect = ErrUnexpEOF, ExpectColObjFieldName
+				goto ERROR
+			}
+			/*</check_eof>*/
+
+			if i.str[i.head] != ':' {
+				i.errc = ErrUnexpToken
+				i.expect = ExpectColObjFieldName
+				goto ERROR
+			}
+			i.head++
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			i.expect = ExpectVal
+			goto VALUE
+			// </ExpectObjFieldName after name>
+
+			/*</name>*/
+
+		}
+	} else if t == TokenArr {
+		if i.str[i.head] == ']' {
+			i.tail = -1
+			i.stackPop()
+
+			// Callback for end of array
+			i.token = TokenArrEnd
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head++
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			if i.stackLen() > 0 {
+				i.expect = ExpectAfterValueInner
+				goto AFTER_VALUE_INNER
+			}
+		} else {
+			// Proceed to next value in the array
+			goto VALUE
+		}
+	}
+	goto AFTER_VALUE_OUTER
+	/*</l_after_value_inner>*/
+
+	/*<l_after_value_outer>*/
+AFTER_VALUE_OUTER:
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if inDefVal {
+		switch i.str[i.head] {
+		case ')':
+			inDefVal = false
+			goto VAR_LIST_END
+		case '@':
+			inDefVal = false
+			i.head++
+			dirOn, i.expect = dirVar, ExpectDir
+			goto DIR_NAME
+		case '#':
+			goto COMMENT
+		}
+		inDefVal = false
+		i.expect = ExpectVar
+		goto OPR_VAR
+	}
+
+	if i.str[i.head] == ')' {
+		// End of argument list
+		i.tail = -1
+		i.token = TokenArgListEnd
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.head++
+		i.expect = ExpectAfterArgList
+		goto AFTER_ARG_LIST
+	}
+
+	// Proceed to the next argument
+	i.expect = ExpectArgName
+
+	/*<name>*/
+	// Followed by argname>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectArgName after name>
+	i.token = TokenArgName
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	i.expect = ExpectColumnAfterArg
+	goto COLUMN_AFTER_ARG_NAME
+	// </ExpectArgName after name>
+
+	/*</name>*/
+
+	/*</l_after_value_outer>*/
+
+	/*<l_after_arg_list>*/
+AFTER_ARG_LIST:
+	if dirOn != 0 {
+		goto AFTER_DIR_ARGS
+	}
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+
+	if i.str[i.head] == '{' {
+		i.expect = ExpectSelSet
+		goto SELECTION_SET
+	} else if i.str[i.head] == '}' {
+		i.expect = ExpectAfterSelection
+		goto AFTER_SELECTION
+	} else if i.str[i.head] == '@' {
+		i.head++
+		dirOn, i.expect = dirField, ExpectDir
+		goto DIR_NAME
+	}
+	i.expect = ExpectSel
+	goto SELECTION
+	/*</l_after_arg_list>*/
+
+	/*<l_selection>*/
+SELECTION:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc, i.expect = ErrUnexpEOF, ExpectSel
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		i.expect = ExpectSel
+		goto COMMENT
+	} else if i.str[i.head] != '.' {
+		// Field selection
+		i.expect = ExpectFieldNameOrAlias
+
+		/*<name>*/
+		// Followed by fieldnameoralias>
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc = ErrUnexpEOF
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		i.tail = i.head
+		if !i.isHeadNameStart() {
+			i.errc = ErrUnexpToken
+			goto ERROR
+		}
+		for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+		}
+		if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+			i.errc = ErrUnexpToken
+			goto ERROR
+		}
+
+		// <ExpectFieldNameOrAlias after name>
+		head := i.head
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
+		}
+		/*</skip_irrelevant>*/
+
+		/*<check_eof>*/
+		if i.head >= len(i.str) {
+			i.errc = ErrUnexpEOF
+			goto ERROR
+		}
+		/*</check_eof>*/
+
+		if i.str[i.head] == ':' {
+			h2 := i.head
+			i.head = head
+			i.token = TokenFieldAlias
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head = h2 + 1
+
+			/*<skip_irrelevant>*/
+			if i.head < len(i.str) && i.isHeadIgnored() {
+				// Single separators are skipped inline,
+				// longer runs by the unrolled loop of skipIgnored.
+				if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+					i.skipIgnored()
+				}
+			}
+			/*</skip_irrelevant>*/
+
+			i.expect = ExpectFieldName
+
+			/*<name>*/
+			// Followed by fieldname>
+
+			/*<check_eof>*/
+			if i.head >= len(i.str) {
+				i.errc = ErrUnexpEOF
+				goto ERROR
+			}
+			/*</check_eof>*/
+
+			i.tail = i.head
+			if !i.isHeadNameStart() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+			for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+			}
+			if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+				i.errc = ErrUnexpToken
+				goto ERROR
+			}
+
+			// <ExpectFieldName after name>
+			i.token = TokenField
+			i.field = Span{i.tail, i.head}
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			goto AFTER_FIELD_NAME
+			// </ExpectFieldName after name>
+
+			/*</name>*/
+
+		}
+		i.head = head
+		i.token = TokenField
+		i.field = Span{i.tail, i.head}
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		goto AFTER_FIELD_NAME
+		// </ExpectFieldNameOrAlias after name>
+
+		/*</name>*/
+
+	}
+
+	i.expect = ExpectFrag
+	if i.head+2 >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		if i.head+1 >= len(i.str) {
+			i.head++
+		} else {
+			i.head += 2
+		}
+		goto ERROR
+	} else if i.str[i.head+2] != '.' ||
+		i.str[i.head+1] != '.' {
+		i.errc = ErrUnexpToken
+		if i.str[i.head+1] != '.' {
+			i.head += 1
+		} else if i.str[i.head+2] != '.' {
+			i.head += 2
+		}
+		goto ERROR
+	}
+
+	i.head += len("...")
+	goto SPREAD
+	/*</l_selection>*/
+
+	/*<l_spread>*/
+SPREAD:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	if i.head+1 >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	} else if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if i.str[i.head] == '{' {
+		i.token, i.tail = TokenFragInline, -1
+		i.field = Span{}
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.expect = ExpectSelSet
+		goto SELECTION_SET
+	} else if i.str[i.head] == '@' {
+		i.token, i.tail = TokenFragInline, -1
+		i.field = Span{}
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.expect, dirOn = ExpectDirName, dirFragInlineOrDef
+		goto AFTER_DIR_NAME
+	} else if i.isHeadKeywordOn() {
+		if i.head+2 >= len(i.str) {
+			i.head = len(i.str)
+			i.errc = ErrUnexpEOF
+			goto ERROR
+		} else if i.str[i.head+2] == ' ' ||
+			i.str[i.head+2] == '\n' ||
+			i.str[i.head+2] == '\r' ||
+			i.str[i.head+2] == '\t' ||
+			i.str[i.head+2] == ',' ||
+			i.str[i.head+2] == '#' {
+			// ... on Type {
+			i.head += len("on")
+			i.expect = ExpectFragInlined
+			goto FRAG_INLINED
+		}
+	}
+	// ...fragmentName
+	i.expect = ExpectSpreadName
+
+	/*<name>*/
+	// Followed by spreadname>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectSpreadName after name>
+	if i.head-i.tail == 2 &&
+		i.str[i.tail+1] == 'n' &&
+		i.str[i.tail] == 'o' {
+		// "...on" not followed by a type condition
+		i.errc, i.head = ErrIllegalFragName, i.tail
+		goto ERROR
+	}
+	i.token = TokenNamedSpread
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.expect, dirOn = ExpectDirName, dirFragRef
+	goto AFTER_DIR_NAME
+	// </ExpectSpreadName after name>
+
+	/*</name>*/
+
+	/*</l_spread>*/
+
+	/*<l_after_decl_varname>*/
+AFTER_DECL_VAR_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if i.str[i.head] != ':' {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	i.head++
+	i.expect = ExpectVarType
+	goto VAR_TYPE
+	/*</l_after_decl_varname>*/
+
+	/*<l_var_type>*/
+VAR_TYPE:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if i.str[i.head] == '[' {
+		i.tail = -1
+		i.token = TokenVarTypeArr
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.head++
+		typeArrLvl++
+		goto VAR_TYPE
+	}
+	i.expect = ExpectVarType
+
+	/*<name>*/
+	// Followed by vartype>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectVarType after name>
+	i.token = TokenVarTypeName
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.expect = ExpectAfterVarTypeName
+	goto AFTER_VAR_TYPE_NAME
+	// </ExpectVarType after name>
+
+	/*</name>*/
+
+	/*</l_var_type>*/
+
+	/*<l_var_name>*/
+VAR_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+
+	/*<name>*/
+	// Followed by varname>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectVarName after name>
+	i.token = TokenVarName
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.expect = ExpectColumnAfterVar
+	goto AFTER_DECL_VAR_NAME
+	// </ExpectVarName after name>
+
+	/*</name>*/
+
+	/*</l_var_name>*/
+
+	/*<l_var_ref>*/
+VAR_REF_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+
+	/*<name>*/
+	// Followed by varrefname>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectVarRefName after name>
+	i.token = TokenVarRef
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.expect = ExpectAfterValueInner
+	goto AFTER_VALUE_INNER
+	// </ExpectVarRefName after name>
+
+	/*</name>*/
+
+	/*</l_var_ref>*/
+
+	/*<l_dir_name>*/
+DIR_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+	i.expect = ExpectDirName
+
+	/*<name>*/
+	// Followed by dirname>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectDirName after name>
+	i.token = TokenDirName
+	i.dir = Span{i.tail, i.head}
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	goto AFTER_DIR_NAME
+	// </ExpectDirName after name>
+
+	/*</name>*/
+
+	/*</l_dir_name>*/
+
+	/*<l_collumn_after_arg_name>*/
+COLUMN_AFTER_ARG_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if i.str[i.head] != ':' {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	i.head++
+	i.stackReset()
+	i.expect = ExpectVal
+	goto VALUE
+	/*</l_collumn_after_arg_name>*/
+
+	/*<l_arg_list>*/
+ARG_LIST:
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+
+	/*<name>*/
+	// Followed by argname>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectArgName after name>
+	i.token = TokenArgName
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	i.expect = ExpectColumnAfterArg
+	goto COLUMN_AFTER_ARG_NAME
+	// </ExpectArgName after name>
+
+	/*</name>*/
+
+	/*</l_arg_list>*/
+
+	/*<l_after_var_type_name>*/
+AFTER_VAR_TYPE_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	if i.head < len(i.str) && i.str[i.head] == '!' {
+		i.tail = -1
+		i.token = TokenVarTypeNotNull
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.head++
+	}
+	goto AFTER_VAR_TYPE_NOT_NULL
+	/*</l_after_var_type_name>*/
+
+	/*<l_after_var_type_not_null>*/
+AFTER_VAR_TYPE_NOT_NULL:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if i.str[i.head] == ']' {
+		if typeArrLvl < 1 {
+			i.errc, i.expect = ErrUnexpToken, ExpectVar
+			goto ERROR
+		}
+		i.tail = -1
+		i.token = TokenVarTypeArrEnd
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.head++
+		typeArrLvl--
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
+		}
+		/*</skip_irrelevant>*/
+
+		if i.head < len(i.str) && i.str[i.head] == '!' {
+			i.tail = -1
+			i.token = TokenVarTypeNotNull
+			/*<callback>*/
+
+			i.tokens++
+
+			/*</callback>*/
+			i.head++
+		}
+
+		if typeArrLvl > 0 {
+			goto AFTER_VAR_TYPE_NAME
+		}
+	}
+	i.expect = ExpectAfterVarType
+	goto AFTER_VAR_TYPE
+	/*</l_after_var_type_not_null>*/
+
+	/*<l_after_field_name>*/
+AFTER_FIELD_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	// Lookahead
+	switch i.str[i.head] {
+	case '(':
+		// Argument list
+		i.tail = -1
+		i.token = TokenArgList
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.head++
+
+		/*<skip_irrelevant>*/
+		if i.head < len(i.str) && i.isHeadIgnored() {
+			// Single separators are skipped inline,
+			// longer runs by the unrolled loop of skipIgnored.
+			if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+				i.skipIgnored()
+			}
+		}
+		/*</skip_irrelevant>*/
+
+		i.expect = ExpectArgName
+		goto ARG_LIST
+	case '{':
+		// Field selector expands without arguments
+		i.expect = ExpectSelSet
+		goto SELECTION_SET
+	case '#':
+		i.expect = ExpectAfterFieldName
+		goto COMMENT
+	case '@':
+		i.head++
+		dirOn, i.expect = dirField, ExpectDir
+		goto DIR_NAME
+	}
+	i.expect = ExpectAfterSelection
+	goto AFTER_SELECTION
+	/*</l_after_field_name>*/
+
+	/*<l_after_opr_name>*/
+AFTER_OPR_NAME:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc, i.expect = ErrUnexpEOF, ExpectSelSet
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	switch i.str[i.head] {
+	case '#':
+		goto COMMENT
+	case '{':
+		i.expect = ExpectSelSet
+		goto SELECTION_SET
+	case '(':
+		// Variable list
+		i.tail = -1
+		i.token = TokenVarList
+		/*<callback>*/
+
+		i.tokens++
+
+		/*</callback>*/
+		i.head++
+		i.expect = ExpectVar
+		goto OPR_VAR
+	case '@':
+		i.head++
+		dirOn, i.expect = dirOpr, ExpectDir
+		goto DIR_NAME
+	}
+	i.errc = ErrUnexpToken
+	i.expect = ExpectSelSet
+	goto ERROR
+	/*</l_after_opr_name>*/
+
+	/*<l_frag_keyword_on>*/
+FRAG_KEYWORD_ON:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	if i.head+1 >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	} else if i.str[i.head] == '#' {
+		goto COMMENT
+	} else if !i.isHeadKeywordOn() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	i.head += len("on")
+	i.expect = ExpectFragTypeCond
+	goto FRAG_TYPE_COND
+
+FRAG_TYPE_COND:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+
+	/*<name>*/
+	// Followed by fragtypecond>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectFragTypeCond after name>
+	i.token = TokenFragTypeCond
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc, i.expect = ErrUnexpEOF, ExpectSelSet
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '@' {
+		dirOn = dirFragInlineOrDef
+		goto AFTER_DIR_NAME
+	}
+	i.expect = ExpectSelSet
+	goto SELECTION_SET
+	// </ExpectFragTypeCond after name>
+
+	/*</name>*/
+
+	/*</l_frag_keyword_on>*/
+
+	/*<l_frag_inlined>*/
+FRAG_INLINED:
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	if i.str[i.head] == '#' {
+		goto COMMENT
+	}
+
+	/*<name>*/
+	// Followed by fraginlined>
+
+	/*<check_eof>*/
+	if i.head >= len(i.str) {
+		i.errc = ErrUnexpEOF
+		goto ERROR
+	}
+	/*</check_eof>*/
+
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		i.errc = ErrUnexpToken
+		goto ERROR
+	}
+
+	// <ExpectFragInlined after name>
+	i.token = TokenFragInline
+	i.field = Span{}
+	/*<callback>*/
+
+	i.tokens++
+
+	/*</callback>*/
+	i.expect, dirOn = ExpectDirName, dirFragInlineOrDef
+	goto AFTER_DIR_NAME
+	// </ExpectFragInlined after name>
+
+	/*</name>*/
+
+	/*</l_frag_inlined>*/
+
+	/*<l_comment>*/
+COMMENT:
+	i.head++
+	for {
+		if i.head+7 >= len(i.str) {
+			for ; i.head < len(i.str) && i.str[i.head] != '\n'; i.head++ {
+			}
+			break
+		}
+		if i.str[i.head] != '\n' &&
+			i.str[i.head+1] != '\n' &&
+			i.str[i.head+2] != '\n' &&
+			i.str[i.head+3] != '\n' &&
+			i.str[i.head+4] != '\n' &&
+			i.str[i.head+5] != '\n' &&
+			i.str[i.head+6] != '\n' &&
+			i.str[i.head+7] != '\n' {
+			i.head += 8
+			continue
+		}
+		if i.str[i.head] == '\n' {
+			break
+		}
+		i.head++
+		if i.str[i.head] == '\n' {
+			break
+		}
+		i.head++
+		if i.str[i.head] == '\n' {
+			break
+		}
+		i.head++
+		if i.str[i.head] == '\n' {
+			break
+		}
+		i.head++
+		if i.str[i.head] == '\n' {
+			break
+		}
+		i.head++
+		if i.str[i.head] == '\n' {
+			break
+		}
+		i.head++
+		if i.str[i.head] == '\n' {
+			break
+		}
+		i.head++
+		if i.str[i.head] == '\n' {
+			break
+		}
+	}
+	i.tail = -1
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	switch i.expect {
+	case ExpectOprName:
+		goto AFTER_OPR_NAME
+	case ExpectVarRefName:
+		goto VAR_REF_NAME
+	case ExpectVarName:
+		goto VAR_NAME
+	case ExpectDef:
+		goto DEFINITION
+	case ExpectDir:
+		goto DIR_NAME
+	case ExpectDirName:
+		goto AFTER_DIR_NAME
+	case ExpectSelSet:
+		goto SELECTION_SET
+	case ExpectSel:
+		goto SELECTION
+	case ExpectAfterSelection:
+		goto AFTER_SELECTION
+	case ExpectVar:
+		goto OPR_VAR
+	case ExpectArgName:
+		goto ARG_LIST
+	case ExpectColumnAfterArg:
+		goto COLUMN_AFTER_ARG_NAME
+	case ExpectVal:
+		goto VALUE
+	case ExpectAfterFieldName:
+		goto AFTER_FIELD_NAME
+	case ExpectAfterValueInner:
+		goto AFTER_VALUE_INNER
+	case ExpectAfterValueOuter:
+		goto AFTER_VALUE_OUTER
+	case ExpectAfterArgList:
+		goto AFTER_ARG_LIST
+	case ExpectAfterDefKeyword:
+		goto AFTER_DEF_KEYWORD
+	case ExpectFragName:
+		goto AFTER_KEYWORD_FRAGMENT
+	case ExpectFragKeywordOn:
+		goto FRAG_KEYWORD_ON
+	case ExpectFragInlined:
+		goto FRAG_INLINED
+	case ExpectFragTypeCond:
+		goto FRAG_TYPE_COND
+	case ExpectFrag:
+		goto SPREAD
+	case ExpectColumnAfterVar:
+		goto AFTER_DECL_VAR_NAME
+	case ExpectVarType:
+		goto VAR_TYPE
+	case ExpectAfterVarType:
+		goto AFTER_VAR_TYPE
+	case ExpectAfterVarTypeName:
+		goto AFTER_VAR_TYPE_NAME
+	}
+	/*</l_comment>*/
+
+	/*<l_definition_end>*/
+DEFINITION_END:
+	i.levelSel, i.expect = 0, ExpectDef
+	// Expect end of file
+
+	/*<skip_irrelevant>*/
+	if i.head < len(i.str) && i.isHeadIgnored() {
+		// Single separators are skipped inline,
+		// longer runs by the unrolled loop of skipIgnored.
+		if i.head++; i.head < len(i.str) && i.isHeadIgnored() {
+			i.skipIgnored()
+		}
+	}
+	/*</skip_irrelevant>*/
+
+	if i.head < len(i.str) {
+		goto DEFINITION
+	}
+	return Error{}
+	/*</l_definition_end>*/
+
+	/*<l_error>*/
+ERROR:
+	{
+		var atIndex rune
+		if i.head < len(i.str) {
+			atIndex, _ = utf8.DecodeRune(i.str[i.head:])
+		}
+		line, column := LineColumn(i.str, i.head)
+		def, token := i.ordinals(i.errc)
+		return Error{
+			Index:           i.head,
+			AtIndex:         atIndex,
+			Code:            i.errc,
+			Expectation:     i.expect,
+			Line:            line,
+			Column:          column,
+			DefinitionIndex: def,
+			TokenOrdinal:    token,
+			Trail:           i.trail(dirOn, inDefVal),
+			Err:             i.abortErr,
+		}
+	}
+	/*</l_error>*/
+
+	/*</scan_body>*/
+
+}
+
+// Iterator is a GraphQL iterator for lexical analysis.
+//
+// WARNING: An iterator instance passed to the function of Scan or ScanAll
+// shall never be aliased and/or used after Scan or ScanAll returns
+// because it's returned to a global pool!
+// Iterators created with NewIterator are owned by the caller.
+type Iterator struct {
+	// stack holds either TokenArr or TokenObj
+	// and is reset for every argument.
+	stack []Token
+
+	expect Expect
+	token  Token
+
+	// str holds the original source
+	str []byte
+
+	// tail and head represent the iterator tail and head indexes
+	tail, head int
+	levelSel   int
+
+	// errc holds the recent error code
+	errc ErrorCode
+
+	// def holds the token of the current definition and defHead
+	// the index it begins at, defName, field and dir hold the spans
+	// of the names of the current definition, field and directive.
+	def                 Token
+	defHead             int
+	defName, field, dir Span
+
+	// parents holds the spans of the fields
+	// enclosing each selection set level.
+	parents []Span
+
+	// skipEnd holds the end token of the selection set or value
+	// skipped at depth skipDepth, fn isn't called for the tokens
+	// preceding it. skipEnd is 0 if nothing is skipped.
+	skipEnd   Token
+	skipDepth int
+
+	// check is called for every token before fn, including the
+	// skipped ones, and fails the scan like fn if it returns true.
+	// It's nil unless the scan enforces limits.
+	check func(*Iterator) (err bool)
+
+	// defs is the number of definitions begun and tokens
+	// the number of tokens scanned.
+	defs, tokens int
+
+	// abortErr is the error passed to Abort.
+	abortErr error
+
+	// lenientNums enables tolerating '+'-signed numbers
+	// and warn is called for every tolerated deviation.
+	lenientNums bool
+	warn        func(Warning)
+}
+
+// trailDef resets the trail for a new definition.
+func (i *Iterator) trailDef() {
+	i.def, i.defHead = i.token, i.head
+	i.defName, i.field, i.dir = Span{}, Span{}, Span{}
+	i.defs++
+}
+
+// ordinals returns the index of the definition and
+// the ordinal of the token an error with code c occurred at.
+func (i *Iterator) ordinals(c ErrorCode) (def, token int) {
+	def, token = i.defs-1, i.tokens
+	if def < 0 || i.expect == ExpectDef {
+		// The error occurred at the beginning of a definition.
+		def = i.defs
+	}
+	if c == ErrCallbackFn {
+		// The callback was called for the current token.
+		token--
+	}
+	return def, token
+}
+
+func (i *Iterator) stackReset() {
+	i.stack = i.stack[:0]
+}
+
+func (i *Iterator) stackLen() int {
+	return len(i.stack)
+}
+
+// stackPush pushes a new token onto the stack.
+func (i *Iterator) stackPush(t Token) {
+	i.stack = append(i.stack, t)
+}
+
+// stackPop pops the top element of the stack returning it.
+// Returns 0 if the stack was empty.
+func (i *Iterator) stackPop() {
+	if l := len(i.stack); l > 0 {
+		i.stack = i.stack[:l-1]
+	}
+}
+
+// stackTop returns the last pushed token.
+func (i *Iterator) stackTop() Token {
+	if l := len(i.stack); l > 0 {
+		return i.stack[l-1]
+	}
+	return 0
+}
+
+var iteratorPool = sync.Pool{
+	New: func() interface{} {
+		return NewIterator()
+	},
+}
+
+// LevelSelect returns the current selector level.
+func (i *Iterator) LevelSelect() int {
+	return i.levelSel
+}
+
+// IsShorthand returns true if the current definition is a query
+// in shorthand form consisting only of a selection set,
+// which is also scanned as TokenDefQry.
+func (i *Iterator) IsShorthand() bool {
+	return i.def == TokenDefQry &&
+		i.defHead < len(i.str) && i.str[i.defHead] == '{'
+}
+
+// IndexHead returns the current head index.
+func (i *Iterator) IndexHead() int {
+	return i.head
+}
+
+// IndexTail returns the current tail index.
+// Returns -1 if the current token doesn't reflect a dynamic value.
+func (i *Iterator) IndexTail() int {
+	return i.tail
+}
+
+// Token returns the current token type.
+func (i *Iterator) Token() Token {
+	return i.token
+}
+
+// Expect returns what the scanner expects to follow the current token.
+// For TokenMissing it's the construct that's missing.
+func (i *Iterator) Expect() Expect {
+	return i.expect
+}
+
+// Value returns the raw value of the current token.
+// For TokenStrBlock it's the raw uninterpreted body of the string,
+// use ScanInterpreted for the interpreted value of the block string.
+//
+// WARNING: The returned byte slice refers to the same underlying memory
+// as the byte slice passed to Scan and ScanAll as str parameter,
+// copy it or use with caution!
+func (i *Iterator) Value() []byte {
+	if i.tail < 0 {
+		return nil
+	}
+	return i.str[i.tail:i.head]
+}
+
+// ScanInterpreted calls fn writing the interpreted part of
+// the value to buffer as long as fn doesn't return true and
+// the scan didn't reach the end of the interpreted value.
+func (i *Iterator) ScanInterpreted(
+	buffer []byte,
+	fn func(buffer []byte) (stop bool),
+) {
+	if len(buffer) < 1 {
+		return
+	}
+	if i.token != TokenStrBlock {
+		offset := 0
+		for offset < len(i.Value()) {
+			b := buffer
+			v := i.Value()[offset:]
+			if len(v) > len(b) {
+				v = v[:len(b)]
+			} else {
+				b = b[:len(v)]
+			}
+			copy(b, v)
+			if fn(b) {
+				return
+			}
+			offset += len(v)
+		}
+		return
+	}
+
+	// Determine the common indentation of all lines but the first
+	// one ignoring lines consisting only of whitespace and
+	// the first and last line that aren't blank.
+	commonIndent, first, last := -1, -1, -1
+	for n, v, ok := 0, i.Value(), true; ok; n++ {
+		var line []byte
+		line, v, ok = blockStrLine(v)
+		indent := blockStrIndent(line)
+		if indent == len(line) {
+			// Blank line
+			continue
+		}
+		if first < 0 {
+			first = n
+		}
+		last = n
+		if n > 0 && (commonIndent < 0 || indent < commonIndent) {
+			commonIndent = indent
+		}
+	}
+	if first < 0 {
+		// Only blank lines
+		return
+	}
+
+	bi := 0
+	write := func(b []byte) (stop bool) {
+		for len(b) > 0 {
+			c := copy(buffer[bi:], b)
+			b, bi = b[c:], bi+c
+			if bi >= len(buffer) {
+				bi = 0
+				if fn(buffer) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for n, v := 0, i.Value(); n <= last; n++ {
+		var line []byte
+		line, v, _ = blockStrLine(v)
+		if n < first {
+			continue
+		}
+		if n > first && write(lineFeed) {
+			return
+		}
+		if n > 0 && commonIndent > 0 {
+			if commonIndent < len(line) {
+				line = line[commonIndent:]
+			} else {
+				line = line[len(line):]
+			}
+		}
+		// Unescape \"""
+		for x := 0; x+3 < len(line); x++ {
+			if line[x] == '\\' &&
+				line[x+1] == '"' &&
+				line[x+2] == '"' &&
+				line[x+3] == '"' {
+				if write(line[:x]) {
+					return
+				}
+				line, x = line[x+1:], -1
+			}
+		}
+		if write(line) {
+			return
+		}
+	}
+	if b := buffer[:bi]; len(b) > 0 {
+		if fn(buffer[:bi]) {
+			return
+		}
+	}
+}
+
+var lineFeed = []byte{'\n'}
+
+// blockStrLine returns the first line of the block string v
+// without its line terminator and the remainder of v after it.
+// ok is false if the line wasn't terminated.
+func blockStrLine(v []byte) (line, remainder []byte, ok bool) {
+	for x := 0; x < len(v); x++ {
+		switch v[x] {
+		case '\n':
+			return v[:x], v[x+1:], true
+		case '\r':
+			if x+1 < len(v) && v[x+1] == '\n' {
+				return v[:x], v[x+2:], true
+			}
+			return v[:x], v[x+1:], true
+		}
+	}
+	return v, nil, false
+}
+
+// blockStrIndent returns the number of leading whitespace
+// characters of line.
+func blockStrIndent(line []byte) (indent int) {
+	for indent < len(line) && (line[indent] == ' ' || line[indent] == '\t') {
+		indent++
+	}
+	return indent
+}
+
+/*<tables>*/
+// Character classes
+const (
+	// classDigit is set for a decimal digit.
+	classDigit uint8 = 1 << iota
+	// classHexDigit is set for a hexadecimal digit.
+	classHexDigit
+	// classNumEnd is set for a character that may terminate a number.
+	classNumEnd
+	// classIgnored is set for an ignored character.
+	classIgnored
+	// classNameStart is set for a character a name may start with.
+	classNameStart
+	// className is set for a character a name may continue with.
+	className
+)
+
+// charClass maps every byte to the set of classes it belongs to.
+var charClass = [256]uint8{
+	'\t': classNumEnd | classIgnored,
+	'\n': classNumEnd | classIgnored,
+	'\r': classNumEnd | classIgnored,
+	' ':  classNumEnd | classIgnored,
+	'#':  classNumEnd,
+	')':  classNumEnd,
+	',':  classNumEnd | classIgnored,
+	'0':  classDigit | classHexDigit | className,
+	'1':  classDigit | classHexDigit | className,
+	'2':  classDigit | classHexDigit | className,
+	'3':  classDigit | classHexDigit | className,
+	'4':  classDigit | classHexDigit | className,
+	'5':  classDigit | classHexDigit | className,
+	'6':  classDigit | classHexDigit | className,
+	'7':  classDigit | classHexDigit | className,
+	'8':  classDigit | classHexDigit | className,
+	'9':  classDigit | classHexDigit | className,
+	'A':  classHexDigit | classNameStart | className,
+	'B':  classHexDigit | classNameStart | className,
+	'C':  classHexDigit | classNameStart | className,
+	'D':  classHexDigit | classNameStart | className,
+	'E':  classHexDigit | classNameStart | className,
+	'F':  classHexDigit | classNameStart | className,
+	'G':  classNameStart | className,
+	'H':  classNameStart | className,
+	'I':  classNameStart | className,
+	'J':  classNameStart | className,
+	'K':  classNameStart | className,
+	'L':  classNameStart | className,
+	'M':  classNameStart | className,
+	'N':  classNameStart | className,
+	'O':  classNameStart | className,
+	'P':  classNameStart | className,
+	'Q':  classNameStart | className,
+	'R':  classNameStart | className,
+	'S':  classNameStart | className,
+	'T':  classNameStart | className,
+	'U':  classNameStart | className,
+	'V':  classNameStart | className,
+	'W':  classNameStart | className,
+	'X':  classNameStart | className,
+	'Y':  classNameStart | className,
+	'Z':  classNameStart | className,
+	']':  classNumEnd,
+	'_':  classNameStart | className,
+	'a':  classHexDigit | classNameStart | className,
+	'b':  classHexDigit | classNameStart | className,
+	'c':  classHexDigit | classNameStart | className,
+	'd':  classHexDigit | classNameStart | className,
+	'e':  classHexDigit | classNameStart | className,
+	'f':  classHexDigit | classNameStart | className,
+	'g':  classNameStart | className,
+	'h':  classNameStart | className,
+	'i':  classNameStart | className,
+	'j':  classNameStart | className,
+	'k':  classNameStart | className,
+	'l':  classNameStart | className,
+	'm':  classNameStart | className,
+	'n':  classNameStart | className,
+	'o':  classNameStart | className,
+	'p':  classNameStart | className,
+	'q':  classNameStart | className,
+	'r':  classNameStart | className,
+	's':  classNameStart | className,
+	't':  classNameStart | className,
+	'u':  classNameStart | className,
+	'v':  classNameStart | className,
+	'w':  classNameStart | className,
+	'x':  classNameStart | className,
+	'y':  classNameStart | className,
+	'z':  classNameStart | className,
+	'}':  classNumEnd,
+}
+
+// isHeadDigit returns true if the current head is
+// a decimal digit, otherwise returns false.
+func (i *Iterator) isHeadDigit() bool {
+	return charClass[i.str[i.head]]&classDigit != 0
+}
+
+// isHeadHexDigit returns true if the current head is
+// a hexadecimal digit, otherwise returns false.
+func (i *Iterator) isHeadHexDigit() bool {
+	return charClass[i.str[i.head]]&classHexDigit != 0
+}
+
+// isHeadNumEnd returns true if the current head is
+// a character that may terminate a number, otherwise returns false.
+func (i *Iterator) isHeadNumEnd() bool {
+	return charClass[i.str[i.head]]&classNumEnd != 0
+}
+
+// isHeadIgnored returns true if the current head is
+// an ignored character, otherwise returns false.
+func (i *Iterator) isHeadIgnored() bool {
+	return charClass[i.str[i.head]]&classIgnored != 0
+}
+
+// isHeadNameStart returns true if the current head is
+// a character a name may start with, otherwise returns false.
+func (i *Iterator) isHeadNameStart() bool {
+	return charClass[i.str[i.head]]&classNameStart != 0
+}
+
+// isHeadName returns true if the current head is
+// a character a name may continue with, otherwise returns false.
+func (i *Iterator) isHeadName() bool {
+	return charClass[i.str[i.head]]&className != 0
+}
+
+// isHeadKeywordQuery returns true if the current head equals 'query'.
+func (i *Iterator) isHeadKeywordQuery() bool {
+	return i.head+4 < len(i.str) &&
+		i.str[i.head+4] == 'y' &&
+		i.str[i.head+3] == 'r' &&
+		i.str[i.head+2] == 'e' &&
+		i.str[i.head+1] == 'u' &&
+		i.str[i.head] == 'q'
+}
+
+// isHeadKeywordMutation returns true if the current head equals 'mutation'.
+func (i *Iterator) isHeadKeywordMutation() bool {
+	return i.head+7 < len(i.str) &&
+		i.str[i.head+7] == 'n' &&
+		i.str[i.head+6] == 'o' &&
+		i.str[i.head+5] == 'i' &&
+		i.str[i.head+4] == 't' &&
+		i.str[i.head+3] == 'a' &&
+		i.str[i.head+2] == 't' &&
+		i.str[i.head+1] == 'u' &&
+		i.str[i.head] == 'm'
+}
+
+// isHeadKeywordSubscription returns true if the current head equals 'subscription'.
+func (i *Iterator) isHeadKeywordSubscription() bool {
+	return i.head+11 < len(i.str) &&
+		i.str[i.head+11] == 'n' &&
+		i.str[i.head+10] == 'o' &&
+		i.str[i.head+9] == 'i' &&
+		i.str[i.head+8] == 't' &&
+		i.str[i.head+7] == 'p' &&
+		i.str[i.head+6] == 'i' &&
+		i.str[i.head+5] == 'r' &&
+		i.str[i.head+4] == 'c' &&
+		i.str[i.head+3] == 's' &&
+		i.str[i.head+2] == 'b' &&
+		i.str[i.head+1] == 'u' &&
+		i.str[i.head] == 's'
+}
+
+// isHeadKeywordFragment returns true if the current head equals 'fragment'.
+func (i *Iterator) isHeadKeywordFragment() bool {
+	return i.head+7 < len(i.str) &&
+		i.str[i.head+7] == 't' &&
+		i.str[i.head+6] == 'n' &&
+		i.str[i.head+5] == 'e' &&
+		i.str[i.head+4] == 'm' &&
+		i.str[i.head+3] == 'g' &&
+		i.str[i.head+2] == 'a' &&
+		i.str[i.head+1] == 'r' &&
+		i.str[i.head] == 'f'
+}
+
+// isHeadKeywordOn returns true if the current head equals 'on'.
+func (i *Iterator) isHeadKeywordOn() bool {
+	return i.head+1 < len(i.str) &&
+		i.str[i.head+1] == 'n' &&
+		i.str[i.head] == 'o'
+}
+
+/*</tables>*/
+
+/*<scan_funcs>*/
+// scanName advances the head to the end of the name at the head,
+// which must not be at the end of the source.
+// Returns ErrUnexpToken if there's no valid name at the head.
+func (i *Iterator) scanName() ErrorCode {
+	i.tail = i.head
+	if !i.isHeadNameStart() {
+		return ErrUnexpToken
+	}
+	for i.head++; i.head < len(i.str) && i.isHeadName(); i.head++ {
+	}
+	if i.head < len(i.str) && i.str[i.head] < 0x20 && !i.isHeadIgnored() {
+		return ErrUnexpToken
+	}
+	return 0
+}
+
+// scanStr advances the head to the closing double-quotes
+// of the string value starting at the head.
+// Returns the error code and sets the expectation
+// if the string value is invalid.
+func (i *Iterator) scanStr() ErrorCode {
+	// String value
+	escaped := false
+	if i.head < len(i.str) && i.str[i.head] == '"' {
+		return 0
+	}
+	for {
+		for !escaped && i.head+7 < len(i.str) {
+			// Fast path
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '"' ||
+				i.str[i.head] == '\\' ||
+				i.str[i.head] < 0x20 {
+				break
+			}
+			i.head++
+		}
+		if i.head >= len(i.str) {
+			break
+		}
+		if i.str[i.head] < 0x20 {
+			i.expect = ExpectEndOfString
+			return ErrUnexpToken
+		}
+		if escaped {
+			switch i.str[i.head] {
+			case '\\':
+				// Backslash
+				i.head++
+			case '/':
+				// Solidus
+				i.head++
+			case '"':
+				// Double-quotes
+				i.head++
+			case 'b':
+				// Backspace
+				i.head++
+			case 'f':
+				// Form-feed
+				i.head++
+			case 'r':
+				// Carriage-return
+				i.head++
+			case 'n':
+				// Line-break
+				i.head++
+			case 't':
+				// Tab
+				i.head++
+			case 'u':
+				// Unicode sequence
+				i.head++
+				if i.head >= len(i.str) {
+					i.expect = ExpectEscapedUnicodeSequence
+					return ErrUnexpEOF
+				}
+				if !i.isHeadHexDigit() {
+					i.expect = ExpectEscapedUnicodeSequence
+					return ErrUnexpToken
+				}
+				i.head++
+				if i.head >= len(i.str) {
+					i.expect = ExpectEscapedUnicodeSequence
+					return ErrUnexpEOF
+				}
+				if !i.isHeadHexDigit() {
+					i.expect = ExpectEscapedUnicodeSequence
+					return ErrUnexpToken
+				}
+				i.head++
+				if i.head >= len(i.str) {
+					i.expect = ExpectEscapedUnicodeSequence
+					return ErrUnexpEOF
+				}
+				if !i.isHeadHexDigit() {
+					i.expect = ExpectEscapedUnicodeSequence
+					return ErrUnexpToken
+				}
+				i.head++
+				if i.head >= len(i.str) {
+					i.expect = ExpectEscapedUnicodeSequence
+					return ErrUnexpEOF
+				}
+				if !i.isHeadHexDigit() {
+					i.expect = ExpectEscapedUnicodeSequence
+					return ErrUnexpToken
+				}
+				// Surrogates must be escaped in pairs of
+				// a high and a low surrogate.
+				if u := i.hexCodeUnit(i.head - 3); u >= 0xDC00 && u <= 0xDFFF {
+					i.head -= 3
+					i.expect = ExpectEscapedUnicodeSequence
+					return ErrUnexpToken
+				} else if u >= 0xD800 && u <= 0xDBFF {
+					i.head++
+					i.expect = ExpectEscapedLowSurrogate
+					for x := 0; x < len(`\uDC00`); x++ {
+						if i.head+x >= len(i.str) {
+							i.head = len(i.str)
+							return ErrUnexpEOF
+						}
+						c := i.str[i.head+x]
+						if (x == 0 && c != '\\') || (x == 1 && c != 'u') ||
+							(x > 1 && charClass[c]&classHexDigit == 0) {
+							i.head += x
+							return ErrUnexpToken
+						}
+					}
+					if u := i.hexCodeUnit(i.head + 2); u < 0xDC00 || u > 0xDFFF {
+						i.head += 2
+						return ErrUnexpToken
+					}
+					i.head += len(`\uDC00`) - 1
+				}
+			default:
+				i.expect = ExpectEscapedSequence
+				return ErrUnexpToken
+			}
+			escaped = false
+			continue
+		} else if i.str[i.head] == '"' {
+			return 0
+		} else if i.str[i.head] == '\\' {
+			escaped = true
+		}
+		i.head++
+	}
+	i.expect = ExpectEndOfString
+	return ErrUnexpEOF
+}
+
+// hexCodeUnit returns the value of the four hexadecimal digits
+// at index x.
+func (i *Iterator) hexCodeUnit(x int) (u rune) {
+	for _, c := range i.str[x : x+4] {
+		switch {
+		case c >= 'a':
+			c -= 'a' - 10
+		case c >= 'A':
+			c -= 'A' - 10
+		default:
+			c -= '0'
+		}
+		u = u<<4 | rune(c)
+	}
+	return u
+}
+
+// scanBlockStr advances the head to the closing triple-quotes
+// of the block string value starting at the head.
+// Returns the error code if the block string value is invalid.
+func (i *Iterator) scanBlockStr() ErrorCode {
+	for {
+		for i.head+7 < len(i.str) {
+			if i.str[i.head] == '\\' ||
+				i.str[i.head] == '"' ||
+				(i.str[i.head] < 0x20 &&
+					i.str[i.head] != '\t' &&
+					i.str[i.head] != '\n' &&
+					i.str[i.head] != '\r') {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '\\' ||
+				i.str[i.head] == '"' ||
+				(i.str[i.head] < 0x20 &&
+					i.str[i.head] != '\t' &&
+					i.str[i.head] != '\n' &&
+					i.str[i.head] != '\r') {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '\\' ||
+				i.str[i.head] == '"' ||
+				(i.str[i.head] < 0x20 &&
+					i.str[i.head] != '\t' &&
+					i.str[i.head] != '\n' &&
+					i.str[i.head] != '\r') {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '\\' ||
+				i.str[i.head] == '"' ||
+				(i.str[i.head] < 0x20 &&
+					i.str[i.head] != '\t' &&
+					i.str[i.head] != '\n' &&
+					i.str[i.head] != '\r') {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '\\' ||
+				i.str[i.head] == '"' ||
+				(i.str[i.head] < 0x20 &&
+					i.str[i.head] != '\t' &&
+					i.str[i.head] != '\n' &&
+					i.str[i.head] != '\r') {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '\\' ||
+				i.str[i.head] == '"' ||
+				(i.str[i.head] < 0x20 &&
+					i.str[i.head] != '\t' &&
+					i.str[i.head] != '\n' &&
+					i.str[i.head] != '\r') {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '\\' ||
+				i.str[i.head] == '"' ||
+				(i.str[i.head] < 0x20 &&
+					i.str[i.head] != '\t' &&
+					i.str[i.head] != '\n' &&
+					i.str[i.head] != '\r') {
+				break
+			}
+			i.head++
+			if i.str[i.head] == '\\' ||
+				i.str[i.head] == '"' ||
+				(i.str[i.head] < 0x20 &&
+					i.str[i.head] != '\t' &&
+					i.str[i.head] != '\n' &&
+					i.str[i.head] != '\r') {
 				break
 			}
+			i.head++
 		}
-	FIND_END:
-		for i := len(v) - 1; i >= 0; i-- {
-			if v[i] == '\n' {
-				for ; i >= 0; i-- {
-					if v[i] != '\n' && v[i] != ' ' && v[i] != '\t' {
-						end = i + 1
-						break FIND_END
-					}
-				}
-			}
-		}
-		v = v[start:end]
-	COUNT_LOOP:
-		for len(v) > 0 {
-			if v[0] == '\n' {
-				// Count prefix length
-				l := 0
-				for v = v[1:]; ; l++ {
-					if l >= len(v) {
-						break COUNT_LOOP
-					} else if v[l] != ' ' && v[l] != '\t' {
-						v = v[l:]
-						if shortestPrefixLen == 0 || shortestPrefixLen > l {
-							shortestPrefixLen = l
-						}
-						break
-					}
-				}
+		if i.head >= len(i.str) {
+			return ErrUnexpEOF
+		}
+		if i.str[i.head] == '\\' {
+			if i.head+3 < len(i.str) &&
+				i.str[i.head+3] == '"' &&
+				i.str[i.head+2] == '"' &&
+				i.str[i.head+1] == '"' {
+				i.head += len(`\"""`)
 				continue
 			}
-			v = v[1:]
+		} else if i.str[i.head] == '"' {
+			if i.head+2 >= len(i.str) {
+				i.head = len(i.str)
+				return ErrUnexpEOF
+			} else if i.str[i.head+2] == '"' &&
+				i.str[i.head+1] == '"' {
+				return 0
+			}
+		} else if i.str[i.head] < 0x20 &&
+			i.str[i.head] != '\t' &&
+			i.str[i.head] != '\n' &&
+			i.str[i.head] != '\r' {
+			return ErrUnexpToken
 		}
+		i.head++
 	}
+}
 
-	{
-		v, bi := i.Value()[start:end], 0
+// scanNum advances the head to the end of the number starting at the head
+// and sets the token to either TokenInt or TokenFloat.
+// Returns the error code and sets the expectation
+// if the number is invalid.
+func (i *Iterator) scanNum() ErrorCode {
+	var s int
 
-		write := func(b byte) (stop bool) {
-			buffer[bi] = b
-			bi++
-			if bi >= len(buffer) {
-				bi = 0
-				return fn(buffer)
-			}
-			return false
+	switch i.str[i.head] {
+	case '-':
+		// Signed
+		i.head++
+		if i.head >= len(i.str) {
+			i.expect = ExpectVal
+			return ErrUnexpEOF
 		}
-
-		for i := 0; i < len(v); {
-			if v[i] == '\n' {
-				if i != 0 {
-					if write(v[i]) {
-						return
-					}
-				}
-				// Ignore prefix
-				if i+shortestPrefixLen+1 <= len(v) {
-					i += shortestPrefixLen + 1
-				}
-				if v[i] == '\n' {
-					continue
-				}
-			}
-			if v[i] == '\\' && i+3 <= len(v) &&
-				v[i+3] == '"' &&
-				v[i+2] == '"' &&
-				v[i+1] == '"' {
-				if write('"') {
-					return
-				}
-				if write('"') {
-					return
-				}
-				if write('"') {
-					return
-				}
-				i += 4
-				continue
+	case '+':
+		// GraphQL doesn't allow a leading '+',
+		// the integer part rejects it unless tolerated.
+		if i.lenientNums {
+			i.warning(WarnPlusSignedNum)
+			i.head++
+			if i.head >= len(i.str) {
+				i.expect = ExpectVal
+				return ErrUnexpEOF
 			}
-			if write(v[i]) {
-				return
+		}
+	case '0':
+		// Leading zero
+		i.head++
+		if len(i.str) > i.head {
+			if i.str[i.head] == '.' {
+				i.head++
+				goto FRACTION
+			} else if i.str[i.head] == 'e' || i.str[i.head] == 'E' {
+				i.head++
+				goto EXPONENT_SIGN
+			} else if i.isHeadNumEnd() {
+				i.token = TokenInt
+				return 0
+			} else {
+				return i.invalNum(true)
 			}
-			i++
 		}
-		if b := buffer[:bi]; len(b) > 0 {
-			if fn(buffer[:bi]) {
-				return
+	}
+
+	// Integer
+	for s = i.head; i.head < len(i.str); i.head++ {
+		if i.isHeadDigit() {
+			continue
+		} else if i.str[i.head] == '.' {
+			i.head++
+			goto FRACTION
+		} else if i.isHeadNumEnd() {
+			if i.head == s {
+				// Expected at least one digit
+				i.expect = ExpectVal
+				return ErrInvalNum
 			}
+			// Integer
+			i.token = TokenInt
+			return 0
+		} else if i.str[i.head] == 'e' || i.str[i.head] == 'E' {
+			i.head++
+			goto EXPONENT_SIGN
 		}
+
+		// Unexpected rune
+		return i.invalNum(i.head > s)
 	}
-}
 
-// isHeadDigit returns true if the current head is
-// a number start character, otherwise returns false.
-func (i *Iterator) isHeadDigit() bool {
-	switch i.str[i.head] {
-	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-		return true
+	if i.head >= len(i.str) {
+		// Integer without exponent
+		i.token = TokenInt
+		return 0
 	}
-	return false
-}
+	// Continue to fraction
 
-// isHeadDigit returns true if the current head is
-// a number start character, otherwise returns false.
-func (i *Iterator) isHeadHexDigit() bool {
-	switch i.str[i.head] {
-	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9',
-		'a', 'b', 'c', 'd', 'e', 'f',
-		'A', 'B', 'C', 'D', 'E', 'F':
-		return true
+FRACTION:
+	_ = 0 // Make code coverage count the label above
+	for s = i.head; i.head < len(i.str); i.head++ {
+		if i.isHeadDigit() {
+			continue
+		} else if i.isHeadNumEnd() {
+			if i.head == s {
+				// Expected at least one digit
+				i.expect = ExpectVal
+				return ErrInvalNum
+			}
+			// Number with fraction
+			i.token = TokenFloat
+			return 0
+		} else if i.str[i.head] == 'e' || i.str[i.head] == 'E' {
+			i.head++
+			goto EXPONENT_SIGN
+		}
+
+		// Unexpected rune
+		return i.invalNum(i.head > s)
+	}
+	if s == i.head {
+		// Unexpected end of number
+		i.expect = ExpectVal
+		return ErrUnexpEOF
 	}
-	return false
-}
 
-// isHeadNumEnd returns true if the current head is a space, line-feed,
-// horizontal tab, carriage-return, comma, right parenthesis or
-// right curly brace, otherwise returns false.
-func (i *Iterator) isHeadNumEnd() bool {
-	switch i.str[i.head] {
-	case ' ', '\t', '\r', '\n', ',', ')', '}', ']', '#':
-		return true
+	if i.head >= len(i.str) {
+		// Number (with fraction but) without exponent
+		i.token = TokenFloat
+		return 0
 	}
-	return false
-}
 
-// isHeadKeywordQuery returns true if the current head equals 'query'.
-func (i *Iterator) isHeadKeywordQuery() bool {
-	return i.head+4 < len(i.str) &&
-		i.str[i.head+4] == 'y' &&
-		i.str[i.head+3] == 'r' &&
-		i.str[i.head+2] == 'e' &&
-		i.str[i.head+1] == 'u' &&
-		i.str[i.head] == 'q'
+EXPONENT_SIGN:
+	if i.head >= len(i.str) {
+		i.expect = ExpectVal
+		return ErrUnexpEOF
+	}
+	if i.str[i.head] == '-' || i.str[i.head] == '+' {
+		i.head++
+	}
+	for s = i.head; i.head < len(i.str); i.head++ {
+		if i.isHeadDigit() {
+			continue
+		} else if i.isHeadNumEnd() {
+			if i.head == s {
+				// Expected at least one digit
+				i.expect = ExpectVal
+				return ErrInvalNum
+			}
+			// Number with (fraction and) exponent
+			i.token = TokenFloat
+			return 0
+		}
+		break
+	}
+	// Unexpected rune
+	return i.invalNum(i.head > s)
 }
 
-// isHeadKeywordMutation returns true if the current head equals 'mutation'.
-func (i *Iterator) isHeadKeywordMutation() bool {
-	return i.head+7 < len(i.str) &&
-		i.str[i.head+7] == 'n' &&
-		i.str[i.head+6] == 'o' &&
-		i.str[i.head+5] == 'i' &&
-		i.str[i.head+4] == 't' &&
-		i.str[i.head+3] == 'a' &&
-		i.str[i.head+2] == 't' &&
-		i.str[i.head+1] == 'u' &&
-		i.str[i.head] == 'm'
+// invalNum returns the error code for the unexpected rune at the head
+// and sets the expectation. Returns ErrNameAfterNum if the rune
+// is a name start immediately following the digits of a number.
+func (i *Iterator) invalNum(digits bool) ErrorCode {
+	if digits && i.head < len(i.str) && i.isHeadNameStart() {
+		i.expect = 0
+		return ErrNameAfterNum
+	}
+	i.expect = ExpectVal
+	return ErrInvalNum
 }
 
-// isHeadKeywordSubscription returns true if
-// the current head equals 'subscription'.
-func (i *Iterator) isHeadKeywordSubscription() bool {
-	return i.head+11 < len(i.str) &&
-		i.str[i.head+11] == 'n' &&
-		i.str[i.head+10] == 'o' &&
-		i.str[i.head+9] == 'i' &&
-		i.str[i.head+8] == 't' &&
-		i.str[i.head+7] == 'p' &&
-		i.str[i.head+6] == 'i' &&
-		i.str[i.head+5] == 'r' &&
-		i.str[i.head+4] == 'c' &&
-		i.str[i.head+3] == 's' &&
-		i.str[i.head+2] == 'b' &&
-		i.str[i.head+1] == 'u' &&
-		i.str[i.head] == 's'
+// skipComment advances the head to the end of the line
+// of the comment starting at the head.
+func (i *Iterator) skipComment() {
+	if n := bytes.IndexByte(i.str[i.head:], '\n'); n > -1 {
+		i.head += n
+	} else {
+		i.head = len(i.str)
+	}
 }
 
-// isHeadKeywordFragment returns true if the current head equals 'fragment'.
-func (i *Iterator) isHeadKeywordFragment() bool {
-	return i.head+7 < len(i.str) &&
-		i.str[i.head+7] == 't' &&
-		i.str[i.head+6] == 'n' &&
-		i.str[i.head+5] == 'e' &&
-		i.str[i.head+4] == 'm' &&
-		i.str[i.head+3] == 'g' &&
-		i.str[i.head+2] == 'a' &&
-		i.str[i.head+1] == 'r' &&
-		i.str[i.head] == 'f'
+// skipIgnored advances the head to the next non-ignored character.
+func (i *Iterator) skipIgnored() {
+	for {
+		if i.head+7 >= len(i.str) {
+			for i.head < len(i.str) {
+				if !i.isHeadIgnored() {
+					break
+				}
+				i.head++
+			}
+			return
+		}
+		if !i.isHeadIgnored() {
+			break
+		}
+		i.head++
+		if !i.isHeadIgnored() {
+			break
+		}
+		i.head++
+		if !i.isHeadIgnored() {
+			break
+		}
+		i.head++
+		if !i.isHeadIgnored() {
+			break
+		}
+		i.head++
+		if !i.isHeadIgnored() {
+			break
+		}
+		i.head++
+		if !i.isHeadIgnored() {
+			break
+		}
+		i.head++
+		if !i.isHeadIgnored() {
+			break
+		}
+		i.head++
+		if !i.isHeadIgnored() {
+			break
+		}
+		i.head++
+	}
 }
 
+/*</scan_funcs>*/
+
 // Expect defines an expectation
 type Expect int
 
@@ -17652,6 +10815,18 @@ const (
 	ExpectAfterDefKeyword
 	ExpectAfterVarType
 	ExpectAfterVarTypeName
+	ExpectTypeSysDef
+	ExpectTypeName
+	ExpectInterfaceName
+	ExpectUnionMember
+	ExpectFieldDef
+	ExpectColumnAfterField
+	ExpectType
+	ExpectRootOpr
+	ExpectColumnAfterRootOpr
+	ExpectDefBody
+	ExpectDirLocation
+	ExpectEscapedLowSurrogate
 )
 
 func (e Expect) String() string {
@@ -17732,6 +10907,30 @@ func (e Expect) String() string {
 		return "variable list closure or variable"
 	case ExpectAfterVarTypeName:
 		return "variable list closure or variable"
+	case ExpectTypeSysDef:
+		return "type system definition"
+	case ExpectTypeName:
+		return "type name"
+	case ExpectInterfaceName:
+		return "interface name"
+	case ExpectUnionMember:
+		return "union member type"
+	case ExpectFieldDef:
+		return "field definition"
+	case ExpectColumnAfterField:
+		return "column after field name"
+	case ExpectType:
+		return "type"
+	case ExpectRootOpr:
+		return "root operation type"
+	case ExpectColumnAfterRootOpr:
+		return "column after root operation"
+	case ExpectDefBody:
+		return "definition body"
+	case ExpectDirLocation:
+		return "directive location"
+	case ExpectEscapedLowSurrogate:
+		return "escaped low surrogate"
 	}
 	return ""
 }
@@ -17780,6 +10979,29 @@ const (
 	TokenObj
 	TokenObjEnd
 	TokenObjField
+	TokenDefEnd
+	TokenDefType
+	TokenDefInterface
+	TokenDefUnion
+	TokenDefEnum
+	TokenDefInput
+	TokenDefScalar
+	TokenTypeName
+	TokenImplements
+	TokenUnionMember
+	TokenDefBody
+	TokenDefBodyEnd
+	TokenFieldDef
+	TokenArgDef
+	TokenEnumValDef
+	TokenDefSchema
+	TokenRootQry
+	TokenRootMut
+	TokenRootSub
+	TokenDefDir
+	TokenDirRepeatable
+	TokenDirLocation
+	TokenMissing
 )
 
 func (t Token) String() string {
@@ -17860,6 +11082,52 @@ func (t Token) String() string {
 		return "object end"
 	case TokenObjField:
 		return "object field"
+	case TokenDefEnd:
+		return "definition end"
+	case TokenDefType:
+		return "type definition"
+	case TokenDefInterface:
+		return "interface definition"
+	case TokenDefUnion:
+		return "union definition"
+	case TokenDefEnum:
+		return "enum definition"
+	case TokenDefInput:
+		return "input definition"
+	case TokenDefScalar:
+		return "scalar definition"
+	case TokenTypeName:
+		return "type name"
+	case TokenImplements:
+		return "implemented interface"
+	case TokenUnionMember:
+		return "union member"
+	case TokenDefBody:
+		return "definition body"
+	case TokenDefBodyEnd:
+		return "definition body end"
+	case TokenFieldDef:
+		return "field definition"
+	case TokenArgDef:
+		return "argument definition"
+	case TokenEnumValDef:
+		return "enum value definition"
+	case TokenDefSchema:
+		return "schema definition"
+	case TokenRootQry:
+		return "root query type"
+	case TokenRootMut:
+		return "root mutation type"
+	case TokenRootSub:
+		return "root subscription type"
+	case TokenDefDir:
+		return "directive definition"
+	case TokenDirRepeatable:
+		return "repeatable directive"
+	case TokenDirLocation:
+		return "directive location"
+	case TokenMissing:
+		return "missing"
 	}
 	return ""
 }
@@ -17875,14 +11143,144 @@ const (
 	ErrIllegalFragName
 	ErrInvalNum
 	ErrInvalType
+	ErrUntrustedDoc
+	ErrDocMismatch
+	ErrInvalJSON
+	ErrReservedName
+	ErrInvalRequest
+	ErrNameAfterNum
+	ErrInvalSourceChar
+	ErrSelTooDeep
+	ErrTooManyTokens
+	ErrValTooDeep
+	ErrDocTooLarge
+	ErrTooManyAliases
+	ErrTooManyRootFields
+	ErrTooManyFrags
+	ErrTooManySpreads
+	ErrNameTooLong
+	ErrStrTooLong
+	ErrTooManyOprs
+	ErrTooManyArgs
+	ErrTooManyDirs
+	ErrAnonOprNotAlone
+	ErrSubMultiRoot
+	ErrSubIntrospection
+	ErrUndefFrag
+	ErrTooComplex
+	ErrTimeout
+	ErrTooManyTypenames
+	ErrInvalOffset
+	ErrInvalCheckpoint
 )
 
+func (c ErrorCode) String() string {
+	switch c {
+	case ErrCallbackFn:
+		return "callback function returned error"
+	case ErrUnexpToken:
+		return "unexpected token"
+	case ErrIllegalFragName:
+		return "illegal fragment name"
+	case ErrInvalNum:
+		return "invalid number value"
+	case ErrInvalType:
+		return "invalid type"
+	case ErrUntrustedDoc:
+		return "untrusted document"
+	case ErrDocMismatch:
+		return "document mismatches trusted document"
+	case ErrInvalJSON:
+		return "invalid JSON string"
+	case ErrReservedName:
+		return "reserved name"
+	case ErrInvalRequest:
+		return "invalid request"
+	case ErrNameAfterNum:
+		return "name immediately following number"
+	case ErrInvalSourceChar:
+		return "invalid source character"
+	case ErrSelTooDeep:
+		return "selection set nesting limit exceeded"
+	case ErrUnexpEOF:
+		return "unexpected end of file"
+	case ErrTooManyTokens:
+		return "token limit exceeded"
+	case ErrValTooDeep:
+		return "value nesting limit exceeded"
+	case ErrDocTooLarge:
+		return "document size limit exceeded"
+	case ErrTooManyAliases:
+		return "alias limit exceeded"
+	case ErrTooManyRootFields:
+		return "root field limit exceeded"
+	case ErrTooManyFrags:
+		return "fragment definition limit exceeded"
+	case ErrTooManySpreads:
+		return "fragment spread limit exceeded"
+	case ErrNameTooLong:
+		return "name length limit exceeded"
+	case ErrStrTooLong:
+		return "string value length limit exceeded"
+	case ErrTooManyOprs:
+		return "operation limit exceeded"
+	case ErrTooManyArgs:
+		return "argument limit exceeded"
+	case ErrTooManyDirs:
+		return "directive limit exceeded"
+	case ErrAnonOprNotAlone:
+		return "anonymous operation not alone"
+	case ErrSubMultiRoot:
+		return "subscription has multiple root fields"
+	case ErrSubIntrospection:
+		return "introspection root field in subscription"
+	case ErrUndefFrag:
+		return "undefined fragment"
+	case ErrTooComplex:
+		return "complexity limit exceeded"
+	case ErrTimeout:
+		return "time limit exceeded"
+	case ErrTooManyTypenames:
+		return "__typename limit exceeded"
+	case ErrInvalOffset:
+		return "offset out of range"
+	case ErrInvalCheckpoint:
+		return "checkpoint doesn't match the document"
+	}
+	return ""
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c ErrorCode) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
 // Error is a GraphQL lexical scan error.
 type Error struct {
 	Index       int
 	AtIndex     rune
 	Code        ErrorCode
 	Expectation Expect
+
+	// Line and Column are the 1-based line and column of Index
+	// in the scanned source. Both are zero if the position is unknown.
+	Line, Column int
+
+	// DefinitionIndex is the 0-based index of the top-level definition
+	// and TokenOrdinal the 0-based ordinal of the token the error
+	// occurred at, which is the number of tokens scanned before it.
+	DefinitionIndex, TokenOrdinal int
+
+	// Trail describes what was being scanned when the error occurred.
+	Trail Trail
+
+	// Err is the error returned by the function passed to ScanErr
+	// or passed to Iterator.Abort, or a *LimitError if the error
+	// is caused by an exceeded limit.
+	Err error
+
+	// Formatter formats the message returned by Error if it's not nil.
+	Formatter ErrorFormatter
 }
 
 // IsErr returns true if there is an error, otherwise returns false.
@@ -17894,6 +11292,9 @@ func (e Error) Error() string {
 	if e.Code == 0 {
 		return ""
 	}
+	if e.Formatter != nil {
+		return e.Formatter.FormatError(e)
+	}
 	var b strings.Builder
 	b.WriteString("error at index ")
 	b.WriteString(strconv.Itoa(e.Index))
@@ -17908,27 +11309,230 @@ func (e Error) Error() string {
 			b.WriteString("')")
 		}
 	}
-	switch e.Code {
-	case ErrCallbackFn:
-		b.WriteString(": callback function returned error")
-	case ErrUnexpToken:
-		b.WriteString(": unexpected token")
-	case ErrIllegalFragName:
-		b.WriteString(": illegal fragment name")
-	case ErrInvalNum:
-		b.WriteString(": invalid number value")
-	case ErrInvalType:
-		b.WriteString(": invalid type")
-	case ErrUnexpEOF:
-		b.WriteString(": unexpected end of file")
+	if c := e.Code.String(); c != "" {
+		b.WriteString(": ")
+		b.WriteString(c)
 	}
 	if e.Expectation != 0 {
 		b.WriteString("; expected ")
 		b.WriteString(e.Expectation.String())
 	}
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the error returned by the function passed to ScanErr
+// or passed to Iterator.Abort.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// Span is a range of the source between the tail (inclusive)
+// and the head (exclusive) index.
+// The zero value represents an empty span.
+type Span struct {
+	Tail, Head int
+}
+
+// IsEmpty returns true if the span is empty, otherwise returns false.
+func (s Span) IsEmpty() bool {
+	return s.Head <= s.Tail
+}
+
+// Scope defines the kind of construct an error occurred in.
+type Scope int
+
+// Scopes
+const (
+	_ Scope = iota
+	ScopeDefinition
+	ScopeVarList
+	ScopeSelectionSet
+	ScopeArgList
+	ScopeDirective
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeDefinition:
+		return "definition"
+	case ScopeVarList:
+		return "variable list"
+	case ScopeSelectionSet:
+		return "selection set"
+	case ScopeArgList:
+		return "argument list"
+	case ScopeDirective:
+		return "directive"
+	}
+	return ""
+}
+
+// Trail is a small trail of what was being scanned
+// when an error occurred, such as the enclosing definition
+// and the field or directive the error is attributed to.
+// Names are referred to by spans of the source.
+// The zero value represents an error outside of any definition.
+type Trail struct {
+	Scope      Scope
+	Definition Token
+
+	// DefinitionName is empty for anonymous operations.
+	DefinitionName Span
+
+	// Field is empty if the error isn't attributed to a field.
+	Field Span
+
+	// Directive is empty if the error isn't attributed to a directive.
+	Directive Span
+}
+
+// Describe returns a human-readable description of the trail
+// such as "in arguments of field 'user' in query 'GetUser'".
+// src must be the source the error was returned for.
+// Returns an empty string for the zero value.
+func (t Trail) Describe(src []byte) string {
+	if t.Definition == 0 {
+		return ""
+	}
+	var b strings.Builder
+	write := func(s Span) {
+		if s.Tail >= 0 && s.Head <= len(src) && !s.IsEmpty() {
+			b.Write(src[s.Tail:s.Head])
+		}
+	}
+	quoted := func(s Span) {
+		b.WriteByte('\'')
+		write(s)
+		b.WriteByte('\'')
+	}
+
+	b.WriteString("in ")
+	switch t.Scope {
+	case ScopeVarList:
+		b.WriteString("variable list of ")
+	case ScopeSelectionSet:
+		b.WriteString("selection set of ")
+		if !t.Field.IsEmpty() {
+			b.WriteString("field ")
+			quoted(t.Field)
+			b.WriteString(" in ")
+		}
+	case ScopeArgList:
+		b.WriteString("arguments of field ")
+		quoted(t.Field)
+		b.WriteString(" in ")
+	case ScopeDirective:
+		b.WriteString("directive")
+		if !t.Directive.IsEmpty() {
+			b.WriteString(" @")
+			write(t.Directive)
+		}
+		if !t.Field.IsEmpty() {
+			b.WriteString(" on field ")
+			quoted(t.Field)
+		}
+		b.WriteString(" in ")
+	}
+
+	if t.DefinitionName.IsEmpty() && t.Definition != TokenDefFrag {
+		b.WriteString("anonymous ")
+	}
+	switch t.Definition {
+	case TokenDefQry:
+		b.WriteString("query")
+	case TokenDefMut:
+		b.WriteString("mutation")
+	case TokenDefSub:
+		b.WriteString("subscription")
+	case TokenDefFrag:
+		b.WriteString("fragment")
+	}
+	if !t.DefinitionName.IsEmpty() {
+		b.WriteByte(' ')
+		quoted(t.DefinitionName)
+	}
 	return b.String()
 }
 
+// trail returns the trail for the current state of the iterator.
+func (i *Iterator) trail(dirOn dirTarget, inDefVal bool) Trail {
+	if i.def == 0 || i.expect == ExpectDef {
+		return Trail{}
+	}
+	t := Trail{
+		Definition:     i.def,
+		DefinitionName: i.defName,
+	}
+	switch i.expect {
+	case ExpectArgName,
+		ExpectColumnAfterArg,
+		ExpectVal,
+		ExpectValEnum,
+		ExpectDefaultVarVal,
+		ExpectAfterValueInner,
+		ExpectAfterValueOuter,
+		ExpectObjFieldName,
+		ExpectColObjFieldName,
+		ExpectVarRefName,
+		ExpectEscapedSequence,
+		ExpectEscapedUnicodeSequence,
+		ExpectEndOfString,
+		ExpectEndOfBlockString:
+		if inDefVal || i.expect == ExpectDefaultVarVal {
+			t.Scope = ScopeVarList
+		} else if dirOn != 0 {
+			t.Scope, t.Directive = ScopeDirective, i.dir
+			if dirOn == dirField {
+				t.Field = i.field
+			}
+		} else {
+			t.Scope, t.Field = ScopeArgList, i.field
+		}
+	case ExpectVar,
+		ExpectVarName,
+		ExpectVarType,
+		ExpectColumnAfterVar,
+		ExpectAfterVarType,
+		ExpectAfterVarTypeName:
+		t.Scope = ScopeVarList
+	case ExpectDir, ExpectDirName:
+		t.Scope = ScopeDirective
+		if dirOn == dirField {
+			t.Field = i.field
+		}
+	case ExpectSelSet,
+		ExpectSel,
+		ExpectFieldNameOrAlias,
+		ExpectFieldName,
+		ExpectAfterFieldName,
+		ExpectAfterSelection,
+		ExpectAfterArgList,
+		ExpectFrag,
+		ExpectSpreadName,
+		ExpectFragInlined:
+		if i.levelSel < 1 {
+			t.Scope = ScopeDefinition
+			break
+		}
+		t.Scope = ScopeSelectionSet
+		// Inline fragments don't have a parent field,
+		// find the closest enclosing field.
+		for l := i.levelSel - 1; l >= 0 && l < len(i.parents); l-- {
+			if !i.parents[l].IsEmpty() {
+				t.Field = i.parents[l]
+				break
+			}
+		}
+	default:
+		t.Scope = ScopeDefinition
+	}
+	return t
+}
+
 type dirTarget int
 
 const (
@@ -31,12 +31,14 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "foo"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query {foo}`,
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "foo"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: {foo: false})}`,
 		Token(gqlscan.TokenDefQry),
@@ -50,6 +52,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenObjEnd),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: false)}`,
 		Token(gqlscan.TokenDefQry),
@@ -60,6 +63,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenFalse),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: true)}`,
 		Token(gqlscan.TokenDefQry),
@@ -70,6 +74,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenTrue),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: null)}`,
 		Token(gqlscan.TokenDefQry),
@@ -80,6 +85,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenNull),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(
 		e1: ENUM_VAL
@@ -131,6 +137,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenEnumVal, "false1"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: [])}`,
 		Token(gqlscan.TokenDefQry),
@@ -142,6 +149,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenArrEnd),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: [[]])}`,
 		Token(gqlscan.TokenDefQry),
@@ -155,6 +163,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenArrEnd),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: 0)}`,
 		Token(gqlscan.TokenDefQry),
@@ -165,6 +174,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenInt, "0"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: 0.0)}`,
 		Token(gqlscan.TokenDefQry),
@@ -175,6 +185,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenFloat, "0.0"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: 42)}`,
 		Token(gqlscan.TokenDefQry),
@@ -185,6 +196,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenInt, "42"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: -42)}`,
 		Token(gqlscan.TokenDefQry),
@@ -195,6 +207,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenInt, "-42"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: -42.5678)}`,
 		Token(gqlscan.TokenDefQry),
@@ -205,6 +218,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenFloat, "-42.5678"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(f: -42.5678e2)}`,
 		Token(gqlscan.TokenDefQry),
@@ -215,6 +229,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenFloat, "-42.5678e2"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{ f (f: {x: 2}) }`,
 		Token(gqlscan.TokenDefQry),
@@ -228,6 +243,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenObjEnd),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`fragment f1 on Query { todos { ...f2 } }
 	query Todos { ...f1 }
@@ -242,6 +258,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenNamedSpread, "f2"),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Query Todos
 		Token(gqlscan.TokenDefQry),
@@ -249,6 +266,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenNamedSpread, "f1"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Fragment f2
 		Token(gqlscan.TokenDefFrag),
@@ -259,6 +277,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenField, "text"),
 		Token(gqlscan.TokenField, "done"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query Q(
 		$variable: Foo,
@@ -451,6 +470,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Mutation M
 		Token(gqlscan.TokenDefMut),
@@ -552,6 +572,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Fragment f1
 		Token(gqlscan.TokenDefFrag),
@@ -563,6 +584,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenNamedSpread, "f2"),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Query Todos
 		Token(gqlscan.TokenDefQry),
@@ -570,6 +592,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenNamedSpread, "f1"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Fragment f2
 		Token(gqlscan.TokenDefFrag),
@@ -588,6 +611,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenField, "done"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Subscription S
 		Token(gqlscan.TokenDefSub),
@@ -607,6 +631,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 
 	Input(`query,Q($variable:Foo,$v:[[Bar]]=[[{f:0}]null[null]]){__schema{`+
@@ -742,6 +767,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Mutation M
 		Token(gqlscan.TokenDefMut),
@@ -843,6 +869,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Fragment f1
 		Token(gqlscan.TokenDefFrag),
@@ -854,6 +881,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenNamedSpread, "f2"),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Query Todos
 		Token(gqlscan.TokenDefQry),
@@ -861,6 +889,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenNamedSpread, "f1"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Fragment f2
 		Token(gqlscan.TokenDefFrag),
@@ -879,6 +908,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenField, "done"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		// Subscription S
 		Token(gqlscan.TokenDefSub),
@@ -898,6 +928,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 
 	// Comments
@@ -906,24 +937,28 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{  #comment1\n  #comment2\n  x}",
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{x  #comment1\n  #comment2\n  }",
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{x}  #comment1\n  #comment2\n",
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{x(  #comment1\n  #comment2\n  y:0)}",
 		Token(gqlscan.TokenDefQry),
@@ -934,6 +969,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenInt, "0"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{x(y  #comment1\n  #comment2\n  :0)}",
 		Token(gqlscan.TokenDefQry),
@@ -944,6 +980,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenInt, "0"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{x(y:  #comment1\n  #comment2\n  0)}",
 		Token(gqlscan.TokenDefQry),
@@ -954,6 +991,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenInt, "0"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{x(y:0  #comment1\n  #comment2\n  )}",
 		Token(gqlscan.TokenDefQry),
@@ -964,6 +1002,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenInt, "0"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{x(y:0)  #comment1\n  #comment2\n  }",
 		Token(gqlscan.TokenDefQry),
@@ -974,18 +1013,21 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenInt, "0"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("query  #comment1\n  #comment2\n  {x}",
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("mutation  #comment1\n  #comment2\n  {x}",
 		Token(gqlscan.TokenDefMut),
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("fragment  #comment1\n  #comment2\n  f on X{x}",
 		Token(gqlscan.TokenDefFrag),
@@ -994,6 +1036,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("fragment f  #comment1\n  #comment2\n  on X{x}",
 		Token(gqlscan.TokenDefFrag),
@@ -1002,6 +1045,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("fragment f on  #comment1\n  #comment2\n  X{x}",
 		Token(gqlscan.TokenDefFrag),
@@ -1010,6 +1054,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("fragment f on X  #comment1\n  #comment2\n  {x}",
 		Token(gqlscan.TokenDefFrag),
@@ -1018,18 +1063,29 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{  ...  #comment1\n  #comment2\n  f  }",
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenNamedSpread, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{  ...  f  #comment1\n  #comment2\n  }",
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenNamedSpread, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input("{...onX ...one}",
+		Token(gqlscan.TokenDefQry),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenNamedSpread, "onX"),
+		Token(gqlscan.TokenNamedSpread, "one"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("query(  #comment1\n  #comment2\n  $x: T){x}",
 		Token(gqlscan.TokenDefQry),
@@ -1040,6 +1096,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("query($x  #comment1\n  #comment2\n  : T){x}",
 		Token(gqlscan.TokenDefQry),
@@ -1050,6 +1107,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("query($x:  #comment1\n  #comment2\n  T){x}",
 		Token(gqlscan.TokenDefQry),
@@ -1060,6 +1118,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("query($x:[  #comment1\n  #comment2\n  T]){x}",
 		Token(gqlscan.TokenDefQry),
@@ -1072,6 +1131,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("query($x:[T  #comment1\n  #comment2\n  ]){x}",
 		Token(gqlscan.TokenDefQry),
@@ -1084,6 +1144,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("query($x:[T]  #comment1\n  #comment2\n  ){x}",
 		Token(gqlscan.TokenDefQry),
@@ -1096,6 +1157,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("query($x:[T])  #comment1\n  #comment2\n  {x}",
 		Token(gqlscan.TokenDefQry),
@@ -1108,6 +1170,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{f#comment\n{f2}}",
 		Token(gqlscan.TokenDefQry),
@@ -1117,6 +1180,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenField, "f2"),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 
 	// String escape
@@ -1129,6 +1193,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenStr, `\"`),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{x(s:"\\")}`,
 		Token(gqlscan.TokenDefQry),
@@ -1139,6 +1204,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenStr, `\\`),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{x(s:"\\\"")}`,
 		Token(gqlscan.TokenDefQry),
@@ -1149,6 +1215,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenStr, `\\\"`),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 
 	Input(`{x(y:1e8)}`,
@@ -1160,6 +1227,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenFloat, `1e8`),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{x(y:0e8)}`,
 		Token(gqlscan.TokenDefQry),
@@ -1170,6 +1238,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenFloat, `0e8`),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{x(y:0e+8)}`,
 		Token(gqlscan.TokenDefQry),
@@ -1180,6 +1249,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenFloat, `0e+8`),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{x(y:0e-8)}`,
 		Token(gqlscan.TokenDefQry),
@@ -1190,12 +1260,14 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenFloat, `0e-8`),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`mutation{x}`,
 		Token(gqlscan.TokenDefMut),
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`mutation($x:T){x}`,
 		Token(gqlscan.TokenDefMut),
@@ -1206,6 +1278,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`mutation M{x}`,
 		Token(gqlscan.TokenDefMut),
@@ -1213,6 +1286,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(o:{o2:{x:[]}})}`,
 		Token(gqlscan.TokenDefQry),
@@ -1230,6 +1304,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenObjEnd),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(a:[0])}`,
 		Token(gqlscan.TokenDefQry),
@@ -1242,6 +1317,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenArrEnd),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query($v:T ! ){x(a:$v)}`,
 		Token(gqlscan.TokenDefQry),
@@ -1257,6 +1333,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query ($v: [ [ T ! ] ! ] ! ) {x(a:$v)}`,
 		Token(gqlscan.TokenDefQry),
@@ -1278,6 +1355,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{ bob : alice }`,
 		Token(gqlscan.TokenDefQry),
@@ -1285,6 +1363,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenFieldAlias, "bob"),
 		Token(gqlscan.TokenField, "alice"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query # This is a test with many comments
 	# sample comment text line
@@ -1520,6 +1599,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenVarList),
@@ -1572,6 +1652,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "Q2"),
 		Token(gqlscan.TokenVarList),
@@ -1590,6 +1671,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f}
 		#0
@@ -1611,9 +1693,10 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(a:
-		"\b\t\r\n\f\/\"\u1234\u5678\u9abc\udefA\uBCDE\uF000"
+		"\b\t\r\n\f\/\"\u1234\u5678\u9abc\ud83d\udefA\uBCDE\uF000"
 		b:123456789
 	)}`,
 		Token(gqlscan.TokenDefQry),
@@ -1622,11 +1705,12 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenArgList),
 		Token(gqlscan.TokenArgName, "a"),
 		Token(gqlscan.TokenStr,
-			`\b\t\r\n\f\/\"\u1234\u5678\u9abc\udefA\uBCDE\uF000`),
+			`\b\t\r\n\f\/\"\u1234\u5678\u9abc\ud83d\udefA\uBCDE\uF000`),
 		Token(gqlscan.TokenArgName, "b"),
 		Token(gqlscan.TokenInt, "123456789"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input("{f(a:"+string_2695b+")}",
 		Token(gqlscan.TokenDefQry),
@@ -1637,6 +1721,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenStr, string_2695b[1:len(string_2695b)-1]),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{f(
 		a:""""""
@@ -1662,6 +1747,7 @@ var testdata = []TestInput{
 			"\n\t\t\tfoo\n\t\t\t\tbar\n\t\t"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`subscription S{f}`,
 		Token(gqlscan.TokenDefSub),
@@ -1669,6 +1755,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`mutation @d1 @d2 (a:0) @d3 {f}`,
 		Token(gqlscan.TokenDefMut),
@@ -1682,6 +1769,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`subscription @d1 @d2 (a:0) @d3 {f}`,
 		Token(gqlscan.TokenDefSub),
@@ -1695,6 +1783,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query @d1 @d2 (a:0) @d3 {f}`,
 		Token(gqlscan.TokenDefQry),
@@ -1708,6 +1797,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query Q @d1 @d2 (a:0) @d3 {f}`,
 		Token(gqlscan.TokenDefQry),
@@ -1722,6 +1812,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query ($v: String) @d1 @d2 (a:$v) @d3 {f}`,
 		Token(gqlscan.TokenDefQry),
@@ -1739,6 +1830,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query @d1 @d2 (a:$v) {f}`,
 		Token(gqlscan.TokenDefQry),
@@ -1751,6 +1843,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query (
 		$v: String @d1 @d2 (a:$v) @d3
@@ -1770,6 +1863,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query (
 		$v1: String @d1 @d2 (a:0)
@@ -1804,6 +1898,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "f"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{
 		a (a: 0) @d1 @d2 (a:$v) @d3 {
@@ -1864,6 +1959,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`{
 		...f @d1 @d2 (a:$v) @d3
@@ -1935,6 +2031,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "X"),
@@ -1946,6 +2043,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefFrag),
 		Token(gqlscan.TokenFragName, "f"),
@@ -1960,6 +2058,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefFrag),
 		Token(gqlscan.TokenFragName, "f2"),
@@ -1973,6 +2072,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenSet),
 		Token(gqlscan.TokenField, "x"),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query($v: Int = 12 @ok $v2: String) {x(a:$v)}`,
 		Token(gqlscan.TokenDefQry),
@@ -1991,6 +2091,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query BoolFalse($v: Boolean = false) {x(a:$v)}
 		query BoolTrue($v: Boolean = true) {x(a:$v)}
@@ -2020,6 +2121,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "BoolTrue"),
@@ -2035,6 +2137,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "Int"),
@@ -2050,6 +2153,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "Float"),
@@ -2065,6 +2169,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "String"),
@@ -2080,6 +2185,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "StringEmpty"),
@@ -2095,6 +2201,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "Null"),
@@ -2110,6 +2217,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "ArrayNull"),
@@ -2131,6 +2239,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "ArrayEmpty"),
@@ -2149,6 +2258,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "Input"),
@@ -2167,6 +2277,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "Input2"),
@@ -2187,6 +2298,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "BlockStringNotNull"),
@@ -2203,6 +2315,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 
 		Token(gqlscan.TokenDefQry),
 		Token(gqlscan.TokenOprName, "BlockStringEmpty"),
@@ -2219,6 +2332,7 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 	Input(`query(
 		$v1: Boolean = false
@@ -2355,6 +2469,278 @@ var testdata = []TestInput{
 		Token(gqlscan.TokenVarRef, "v13"),
 		Token(gqlscan.TokenArgListEnd),
 		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	// Relay @argumentDefinitions and @arguments.
+	Input(`fragment UserFields on User @argumentDefinitions(
+		count: {type: "Int", defaultValue: 10}
+		scale: {type: "[Float!]"}
+	) {
+		name(short: true)
+		friends(first: $count) @connection(key: "F_friends") {
+			...Friend @arguments(scale: $scale, opts: {a: [1, {b: null}]})
+		}
+	}`,
+		Token(gqlscan.TokenDefFrag),
+		Token(gqlscan.TokenFragName, "UserFields"),
+		Token(gqlscan.TokenFragTypeCond, "User"),
+		Token(gqlscan.TokenDirName, "argumentDefinitions"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "count"),
+		Token(gqlscan.TokenObj),
+		Token(gqlscan.TokenObjField, "type"),
+		Token(gqlscan.TokenStr, "Int"),
+		Token(gqlscan.TokenObjField, "defaultValue"),
+		Token(gqlscan.TokenInt, "10"),
+		Token(gqlscan.TokenObjEnd),
+		Token(gqlscan.TokenArgName, "scale"),
+		Token(gqlscan.TokenObj),
+		Token(gqlscan.TokenObjField, "type"),
+		Token(gqlscan.TokenStr, "[Float!]"),
+		Token(gqlscan.TokenObjEnd),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "name"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "short"),
+		Token(gqlscan.TokenTrue),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenField, "friends"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "first"),
+		Token(gqlscan.TokenVarRef, "count"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenDirName, "connection"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "key"),
+		Token(gqlscan.TokenStr, "F_friends"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenNamedSpread, "Friend"),
+		Token(gqlscan.TokenDirName, "arguments"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "scale"),
+		Token(gqlscan.TokenVarRef, "scale"),
+		Token(gqlscan.TokenArgName, "opts"),
+		Token(gqlscan.TokenObj),
+		Token(gqlscan.TokenObjField, "a"),
+		Token(gqlscan.TokenArr),
+		Token(gqlscan.TokenInt, "1"),
+		Token(gqlscan.TokenObj),
+		Token(gqlscan.TokenObjField, "b"),
+		Token(gqlscan.TokenNull),
+		Token(gqlscan.TokenObjEnd),
+		Token(gqlscan.TokenArrEnd),
+		Token(gqlscan.TokenObjEnd),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`{... @argumentDefinitions(a: {type: "Int"}) { f(x: 1) g }}`,
+		Token(gqlscan.TokenDefQry),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenFragInline),
+		Token(gqlscan.TokenDirName, "argumentDefinitions"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "a"),
+		Token(gqlscan.TokenObj),
+		Token(gqlscan.TokenObjField, "type"),
+		Token(gqlscan.TokenStr, "Int"),
+		Token(gqlscan.TokenObjEnd),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "f"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "x"),
+		Token(gqlscan.TokenInt, "1"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenField, "g"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`query($x: Boolean, $y: Boolean) {
+		a: f @include(if: $x) @skip(if: $y) { b @c, d: e(f: 1) @g(h: [$x]) }
+		i @j
+	}`,
+		Token(gqlscan.TokenDefQry),
+		Token(gqlscan.TokenVarList),
+		Token(gqlscan.TokenVarName, "x"),
+		Token(gqlscan.TokenVarTypeName, "Boolean"),
+		Token(gqlscan.TokenVarName, "y"),
+		Token(gqlscan.TokenVarTypeName, "Boolean"),
+		Token(gqlscan.TokenVarListEnd),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenFieldAlias, "a"),
+		Token(gqlscan.TokenField, "f"),
+		Token(gqlscan.TokenDirName, "include"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "if"),
+		Token(gqlscan.TokenVarRef, "x"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenDirName, "skip"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "if"),
+		Token(gqlscan.TokenVarRef, "y"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "b"),
+		Token(gqlscan.TokenDirName, "c"),
+		Token(gqlscan.TokenFieldAlias, "d"),
+		Token(gqlscan.TokenField, "e"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "f"),
+		Token(gqlscan.TokenInt, "1"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenDirName, "g"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "h"),
+		Token(gqlscan.TokenArr),
+		Token(gqlscan.TokenVarRef, "x"),
+		Token(gqlscan.TokenArrEnd),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenField, "i"),
+		Token(gqlscan.TokenDirName, "j"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`query ($id: ID! @depr(r:"x")) {a}`,
+		Token(gqlscan.TokenDefQry),
+		Token(gqlscan.TokenVarList),
+		Token(gqlscan.TokenVarName, "id"),
+		Token(gqlscan.TokenVarTypeName, "ID"),
+		Token(gqlscan.TokenVarTypeNotNull),
+		Token(gqlscan.TokenDirName, "depr"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "r"),
+		Token(gqlscan.TokenStr, "x"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenVarListEnd),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "a"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`query Q($a: Int @d(x: 1), $b: Int = 2 @e(y: [1]) @f) `+
+		`@g(z: $a) @h {a}`,
+		Token(gqlscan.TokenDefQry),
+		Token(gqlscan.TokenOprName, "Q"),
+		Token(gqlscan.TokenVarList),
+		Token(gqlscan.TokenVarName, "a"),
+		Token(gqlscan.TokenVarTypeName, "Int"),
+		Token(gqlscan.TokenDirName, "d"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "x"),
+		Token(gqlscan.TokenInt, "1"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenVarName, "b"),
+		Token(gqlscan.TokenVarTypeName, "Int"),
+		Token(gqlscan.TokenInt, "2"),
+		Token(gqlscan.TokenDirName, "e"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "y"),
+		Token(gqlscan.TokenArr),
+		Token(gqlscan.TokenInt, "1"),
+		Token(gqlscan.TokenArrEnd),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenDirName, "f"),
+		Token(gqlscan.TokenVarListEnd),
+		Token(gqlscan.TokenDirName, "g"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "z"),
+		Token(gqlscan.TokenVarRef, "a"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenDirName, "h"),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "a"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`query($x: Boolean) {
+		...F @include(if: $x)
+		... on T @defer { a }
+		... @defer(label: "l") { b }
+	}
+	fragment F on T @dir { c }`,
+		Token(gqlscan.TokenDefQry),
+		Token(gqlscan.TokenVarList),
+		Token(gqlscan.TokenVarName, "x"),
+		Token(gqlscan.TokenVarTypeName, "Boolean"),
+		Token(gqlscan.TokenVarListEnd),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenNamedSpread, "F"),
+		Token(gqlscan.TokenDirName, "include"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "if"),
+		Token(gqlscan.TokenVarRef, "x"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenFragInline, "T"),
+		Token(gqlscan.TokenDirName, "defer"),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "a"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenFragInline),
+		Token(gqlscan.TokenDirName, "defer"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "label"),
+		Token(gqlscan.TokenStr, "l"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "b"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+
+		Token(gqlscan.TokenDefFrag),
+		Token(gqlscan.TokenFragName, "F"),
+		Token(gqlscan.TokenFragTypeCond, "T"),
+		Token(gqlscan.TokenDirName, "dir"),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "c"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`query($o: Order = CREATED_AT, $l: [E] = [A, trueish]) {
+		a(orderBy: CREATED_AT, l: [A B], o: {f: nullable, g: [on]})
+	}`,
+		Token(gqlscan.TokenDefQry),
+		Token(gqlscan.TokenVarList),
+		Token(gqlscan.TokenVarName, "o"),
+		Token(gqlscan.TokenVarTypeName, "Order"),
+		Token(gqlscan.TokenEnumVal, "CREATED_AT"),
+		Token(gqlscan.TokenVarName, "l"),
+		Token(gqlscan.TokenVarTypeArr),
+		Token(gqlscan.TokenVarTypeName, "E"),
+		Token(gqlscan.TokenVarTypeArrEnd),
+		Token(gqlscan.TokenArr),
+		Token(gqlscan.TokenEnumVal, "A"),
+		Token(gqlscan.TokenEnumVal, "trueish"),
+		Token(gqlscan.TokenArrEnd),
+		Token(gqlscan.TokenVarListEnd),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "a"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "orderBy"),
+		Token(gqlscan.TokenEnumVal, "CREATED_AT"),
+		Token(gqlscan.TokenArgName, "l"),
+		Token(gqlscan.TokenArr),
+		Token(gqlscan.TokenEnumVal, "A"),
+		Token(gqlscan.TokenEnumVal, "B"),
+		Token(gqlscan.TokenArrEnd),
+		Token(gqlscan.TokenArgName, "o"),
+		Token(gqlscan.TokenObj),
+		Token(gqlscan.TokenObjField, "f"),
+		Token(gqlscan.TokenEnumVal, "nullable"),
+		Token(gqlscan.TokenObjField, "g"),
+		Token(gqlscan.TokenArr),
+		Token(gqlscan.TokenEnumVal, "on"),
+		Token(gqlscan.TokenArrEnd),
+		Token(gqlscan.TokenObjEnd),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
 	),
 }
 
@@ -2444,6 +2830,12 @@ func TestScan(t *testing.T) {
 					)
 				}
 			})
+
+			t.Run("Validate", func(t *testing.T) {
+				err := gqlscan.Validate([]byte(td.input))
+				require.Zero(t, err.Error())
+				require.False(t, err.IsErr())
+			})
 		})
 	}
 }
@@ -2531,6 +2923,18 @@ var testdataErr = []TestInputErr{
 		`{f(x:1.2e))}`,
 		"error at index 9 (')'): invalid number value; expected value",
 	),
+	InputErr( // Number with leading plus sign.
+		`{f(x:+1)}`,
+		"error at index 5 ('+'): invalid number value; expected value",
+	),
+	InputErr( // Float with leading plus sign.
+		`{f(x:[+1.5])}`,
+		"error at index 6 ('+'): invalid number value; expected value",
+	),
+	InputErr( // Default value with leading plus sign.
+		`query($a: Int = +1) {a}`,
+		"error at index 16 ('+'): invalid number value; expected value",
+	),
 	InputErr( // Number with leading zero.
 		`{f(x:0123))}`,
 		"error at index 6 ('1'): invalid number value; expected value",
@@ -2586,6 +2990,11 @@ var testdataErr = []TestInputErr{
 		"error at index 7: unexpected end of file; "+
 			"expected variable",
 	),
+	InputErr( // Unexpected token.
+		"query($a: Int @d(x: 1) {a}",
+		"error at index 23 ('{'): unexpected token; "+
+			"expected variable",
+	),
 	InputErr( // Unexpected EOF.
 		"query($",
 		"error at index 7: unexpected end of file; "+
@@ -2856,13 +3265,11 @@ var testdataErr = []TestInputErr{
 	),
 	InputErr( // Unexpected token.
 		"{x(y:12x)}",
-		"error at index 7 ('x'): invalid number value; "+
-			"expected value",
+		"error at index 7 ('x'): name immediately following number",
 	),
 	InputErr( // Unexpected token.
 		"{x(y:12.12x)}",
-		"error at index 10 ('x'): invalid number value; "+
-			"expected value",
+		"error at index 10 ('x'): name immediately following number",
 	),
 	InputErr( // Unexpected EOF.
 		"{x(y:12.12",
@@ -2876,8 +3283,23 @@ var testdataErr = []TestInputErr{
 	),
 	InputErr( // Unexpected token.
 		"{x(y:12e111x",
-		"error at index 11 ('x'): invalid number value; "+
-			"expected value",
+		"error at index 11 ('x'): name immediately following number",
+	),
+	InputErr( // Name immediately following number.
+		"{x(y:123abc)}",
+		"error at index 8 ('a'): name immediately following number",
+	),
+	InputErr( // Name immediately following float.
+		"{x(y:1.0e5x)}",
+		"error at index 10 ('x'): name immediately following number",
+	),
+	InputErr( // Name immediately following zero.
+		"{x(y:[0x1])}",
+		"error at index 7 ('x'): name immediately following number",
+	),
+	InputErr( // Underscore immediately following number.
+		"query($a: Int = 1_) {a}",
+		"error at index 17 ('_'): name immediately following number",
 	),
 	InputErr( // Unexpected token.
 		"{x(y:12ex",
@@ -3059,6 +3481,31 @@ var testdataErr = []TestInputErr{
 		"error at index 11 ('\"'): unexpected token; "+
 			"expected escaped unicode sequence",
 	),
+	InputErr( // Orphan low surrogate.
+		`{f(a:"\uDE00")}`,
+		"error at index 8 ('D'): unexpected token; "+
+			"expected escaped unicode sequence",
+	),
+	InputErr( // High surrogate without low surrogate.
+		`{f(a:"\uD83D")}`,
+		"error at index 12 ('\"'): unexpected token; "+
+			"expected escaped low surrogate",
+	),
+	InputErr( // High surrogate followed by another escape.
+		`{f(a:"\uD83D\n")}`,
+		"error at index 13 ('n'): unexpected token; "+
+			"expected escaped low surrogate",
+	),
+	InputErr( // High surrogate followed by a high surrogate.
+		`{f(a:"\uD83D\uD83D")}`,
+		"error at index 14 ('D'): unexpected token; "+
+			"expected escaped low surrogate",
+	),
+	InputErr( // Unexpected EOF.
+		`{f(a:"\uD83D\uDE0`,
+		"error at index 17: unexpected end of file; "+
+			"expected escaped low surrogate",
+	),
 	InputErr( // Unexpected EOF.
 		`{f(a:"""`,
 		`error at index 8: unexpected end of file; `+
@@ -3069,6 +3516,26 @@ var testdataErr = []TestInputErr{
 		"error at index 9: unexpected end of file; "+
 			"expected end of block string",
 	),
+	InputErr( // Unexpected EOF after escaped quotes.
+		`{f(a:"""\"`,
+		"error at index 10: unexpected end of file; "+
+			"expected end of block string",
+	),
+	InputErr( // Unexpected EOF after backslash.
+		`{f(a:"""\`,
+		"error at index 9: unexpected end of file; "+
+			"expected end of block string",
+	),
+	InputErr( // Unexpected EOF after a quote.
+		`{f(a:""""`,
+		"error at index 9: unexpected end of file; "+
+			"expected end of block string",
+	),
+	InputErr( // Unexpected EOF after two quotes.
+		`{f(a:"""abc""`,
+		"error at index 13: unexpected end of file; "+
+			"expected end of block string",
+	),
 	InputErr( // Control character in string.
 		`{f(a:"0123456`+string(rune(0x00))+`")}`,
 		"error at index 13 (0x0): unexpected token; "+
@@ -3254,6 +3721,31 @@ var testdataErr = []TestInputErr{
 		"error at index 9 ('o'): illegal fragment name; "+
 			"expected fragment name",
 	),
+	InputErr( // Illegal fragment name after comment
+		"fragment #comment\non on User {x}",
+		"error at index 18 ('o'): illegal fragment name; "+
+			"expected fragment name",
+	),
+	InputErr( // Spread of illegal fragment name
+		`{...on}`,
+		"error at index 4 ('o'): illegal fragment name; "+
+			"expected spread name",
+	),
+	InputErr( // Spread of illegal fragment name followed by selection set
+		`{...on{x}}`,
+		"error at index 4 ('o'): illegal fragment name; "+
+			"expected spread name",
+	),
+	InputErr( // Spread of illegal fragment name followed by directive
+		`{...on@d{x}}`,
+		"error at index 4 ('o'): illegal fragment name; "+
+			"expected spread name",
+	),
+	InputErr( // Unexpected EOF after comment
+		"fragment f on X #comment",
+		"error at index 24: unexpected end of file; "+
+			"expected selection set",
+	),
 }
 
 func TestScanErr(t *testing.T) {
@@ -3278,6 +3770,49 @@ func TestScanErr(t *testing.T) {
 				require.Equal(t, td.expectErr, err.Error())
 				require.True(t, err.IsErr())
 			})
+
+			t.Run("Validate", func(t *testing.T) {
+				err := gqlscan.Validate([]byte(td.input))
+				require.Equal(t, td.expectErr, err.Error())
+				require.True(t, err.IsErr())
+			})
+		})
+	}
+}
+
+func TestErrorTrail(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `query GetUser { user(id: ) { name } }`,
+			"in arguments of field 'user' in query 'GetUser'"},
+		{decl(1), `query GetUser($id: ) { user { name } }`,
+			"in variable list of query 'GetUser'"},
+		{decl(1), `query Q($v: Int = $x) { a }`,
+			"in variable list of query 'Q'"},
+		{decl(1), `{ user { friends { ... on User { ? } } } }`,
+			"in selection set of field 'friends' in anonymous query"},
+		{decl(1), `mutation M { a @include(if: ) }`,
+			"in directive @include on field 'a' in mutation 'M'"},
+		{decl(1), `query Q @d(x: ]) { a }`,
+			"in directive @d in query 'Q'"},
+		{decl(1), `query Q { a @ }`,
+			"in directive on field 'a' in query 'Q'"},
+		{decl(1), `fragment F on T { a { b(x: "\q") } }`,
+			"in arguments of field 'b' in fragment 'F'"},
+		{decl(1), `subscription { a { 1 } }`,
+			"in selection set of field 'a' in anonymous subscription"},
+		{decl(1), `query Q ( `,
+			"in variable list of query 'Q'"},
+		{decl(1), `query Q { a } ? `, ""},
+		{decl(1), `?`, ""},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanAll([]byte(td.input), func(*gqlscan.Iterator) {})
+			require.True(t, err.IsErr())
+			require.Equal(t, td.expect, err.Trail.Describe([]byte(td.input)))
 		})
 	}
 }
@@ -3598,6 +4133,7 @@ func TestLevel(t *testing.T) {
 		TokenLevel(gqlscan.TokenSetEnd, 3),
 		TokenLevel(gqlscan.TokenSetEnd, 2),
 		TokenLevel(gqlscan.TokenSetEnd, 1),
+		TokenLevel(gqlscan.TokenDefEnd, 0),
 
 		// Mutation M
 		TokenLevel(gqlscan.TokenDefMut, 0),
@@ -3700,6 +4236,7 @@ func TestLevel(t *testing.T) {
 		TokenLevel(gqlscan.TokenSetEnd, 3),
 		TokenLevel(gqlscan.TokenSetEnd, 2),
 		TokenLevel(gqlscan.TokenSetEnd, 1),
+		TokenLevel(gqlscan.TokenDefEnd, 0),
 
 		// Fragment f1
 		TokenLevel(gqlscan.TokenDefFrag, 0),
@@ -3711,6 +4248,7 @@ func TestLevel(t *testing.T) {
 		TokenLevel(gqlscan.TokenNamedSpread, 2, "f2"),
 		TokenLevel(gqlscan.TokenSetEnd, 2),
 		TokenLevel(gqlscan.TokenSetEnd, 1),
+		TokenLevel(gqlscan.TokenDefEnd, 0),
 
 		// Query Todos
 		TokenLevel(gqlscan.TokenDefQry, 0),
@@ -3718,6 +4256,7 @@ func TestLevel(t *testing.T) {
 		TokenLevel(gqlscan.TokenSet, 0),
 		TokenLevel(gqlscan.TokenNamedSpread, 1, "f1"),
 		TokenLevel(gqlscan.TokenSetEnd, 1),
+		TokenLevel(gqlscan.TokenDefEnd, 0),
 
 		// Fragment f2
 		TokenLevel(gqlscan.TokenDefFrag, 0),
@@ -3736,6 +4275,7 @@ func TestLevel(t *testing.T) {
 		TokenLevel(gqlscan.TokenArgListEnd, 1),
 		TokenLevel(gqlscan.TokenField, 1, "done"),
 		TokenLevel(gqlscan.TokenSetEnd, 1),
+		TokenLevel(gqlscan.TokenDefEnd, 0),
 	}
 
 	t.Run("Scan", func(t *testing.T) {
@@ -3921,6 +4461,72 @@ var testdataBlockStrings = []ExpectBlockStr{
 		make([]byte, 8),
 		"a\n\n  \n\nb",
 	),
+	TokenBlockStr(
+		// Empty line between indented lines.
+		`{f(a:"""`+"\n   a\n\n   b\n"+`""")}`,
+		make([]byte, 8),
+		"a\n\nb",
+	),
+	TokenBlockStr(
+		// Line without indentation.
+		`{f(a:"""`+"\n    a\n  b\nc\n"+`""")}`,
+		make([]byte, 16),
+		"    a\n  b\nc",
+	),
+	TokenBlockStr(
+		// The first line doesn't count towards the common indentation.
+		`{f(a:"""  first`+"\n    second\n      third"+`""")}`,
+		make([]byte, 32),
+		"  first\nsecond\n  third",
+	),
+	TokenBlockStr(
+		// Trailing whitespace of the last line is preserved.
+		`{f(a:"""`+"\n  a  \n  "+`""")}`,
+		make([]byte, 8),
+		"a  ",
+	),
+	TokenBlockStr(
+		// Only whitespace.
+		`{f(a:"""`+" \t \n  \n"+`""")}`,
+		make([]byte, 8),
+		// No writes
+	),
+	TokenBlockStr(
+		// Whitespace-only line shorter than the common indentation.
+		`{f(a:"""`+"\n\t\ta\n\t\n\t\tb\n\t"+`""")}`,
+		make([]byte, 8),
+		"a\n\nb",
+	),
+	TokenBlockStr(
+		// Escaped triple quotes on an indented line.
+		`{f(a:"""`+"\n  a\n  \\\"\"\"b\\\"\"\"\n"+`""")}`,
+		make([]byte, 16),
+		`a`+"\n"+`"""b"""`,
+	),
+	TokenBlockStr(
+		// Lines terminated by "\r\n".
+		`{f(a:"""`+
+			"\r\n   a\r\n    b\r\n   \r\n   c\r\n"+
+			`""")}`,
+		make([]byte, 8),
+		"a\n b\n\nc",
+	),
+	TokenBlockStr(
+		// Lines terminated by a lone "\r".
+		`{f(a:"""`+
+			"\r   a\r    b\r   \r   c\r"+
+			`""")}`,
+		make([]byte, 8),
+		"a\n b\n\nc",
+	),
+	TokenBlockStr(
+		// Lines terminated by "\n", "\r\n" and "\r".
+		`{f(a:"""`+
+			"first\r\n  second\r  third\n  fourth\r\n"+
+			`""")}`,
+		make([]byte, 8),
+		"first\nse", "cond\nthi", "rd\nfourt", "h",
+	),
 	TokenBlockStr(
 		blockstring_2747b,
 		make([]byte, 4096), // 4 KiB buffer
@@ -0,0 +1,258 @@
+package gqlscan
+
+// HTTPRequest describes a GraphQL-over-HTTP request body
+// of media type application/json.
+// The zero value is ready to use, its buffer is reused
+// by consecutive calls to ScanHTTPRequestBody.
+type HTTPRequest struct {
+	// QueryIndex is the index of the JSON string literal
+	// of "query" in the body.
+	QueryIndex int
+
+	// OperationName is the decoded value of "operationName"
+	// and is nil if it's absent or null.
+	OperationName []byte
+
+	// Variables and Extensions are the spans of the raw JSON objects
+	// of "variables" and "extensions" in the body and are empty
+	// if they're absent or null.
+	Variables, Extensions Span
+
+	// IndexMap records the positions of the escape sequences of
+	// the query literal if it's not nil, see ScanJSONIndexed.
+	IndexMap *JSONIndexMap
+
+	buffer []byte
+}
+
+// ScanHTTPRequestBody is similar to ScanJSON but scans the document
+// in "query" of the JSON request body and describes the request in r.
+// The operation name is decoded into the buffer of r and the query
+// into a pooled buffer before it's scanned like by ScanJSON,
+// body isn't modified and remains owned by the caller.
+// If body isn't a JSON object holding a string "query", a string or null
+// "operationName", and objects or null "variables" and "extensions"
+// then an error with code ErrInvalRequest is returned.
+// The indexes of errors with codes ErrInvalRequest and ErrInvalJSON
+// refer to body, the indexes of the iterator and of other errors
+// refer to the decoded query.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanHTTPRequestBody returns!
+func ScanHTTPRequestBody(
+	body []byte,
+	r *HTTPRequest,
+	fn func(*Iterator) (err bool),
+) Error {
+	return scanHTTPRequest(body, 0, r, fn)
+}
+
+// scanHTTPRequest scans the request body[start:],
+// the indexes of r and of errors of the body refer to body.
+func scanHTTPRequest(
+	body []byte,
+	start int,
+	r *HTTPRequest,
+	fn func(*Iterator) (err bool),
+) Error {
+	if err := parseHTTPRequest(body, start, r); err.IsErr() {
+		return err
+	}
+	return scanHTTPQuery(body, r, nil, fn)
+}
+
+// parseHTTPRequest describes the request body[start:] in r
+// without decoding and scanning the query.
+func parseHTTPRequest(body []byte, start int, r *HTTPRequest) Error {
+	r.QueryIndex, r.OperationName = -1, nil
+	r.Variables, r.Extensions = Span{}, Span{}
+	var operationName Span
+
+	x := skipJSONSpace(body, start)
+	if x >= len(body) || body[x] != '{' {
+		return errorAt(body, x, ErrInvalRequest)
+	}
+	object := x
+	x = skipJSONSpace(body, x+1)
+	if x < len(body) && body[x] == '}' {
+		x++
+	} else {
+		for {
+			key := x
+			if x = skipJSONString(body, x); x < 0 {
+				return errorAt(body, key, ErrInvalRequest)
+			}
+			k := body[key+1 : x-1]
+			if x = skipJSONSpace(body, x); x >= len(body) || body[x] != ':' {
+				return errorAt(body, x, ErrInvalRequest)
+			}
+			v := skipJSONSpace(body, x+1)
+			if x = skipJSONValue(body, v); x < 0 {
+				return errorAt(body, v, ErrInvalRequest)
+			}
+			isNull := body[v] == 'n'
+			switch string(k) {
+			case "query":
+				if body[v] != '"' {
+					return errorAt(body, v, ErrInvalRequest)
+				}
+				r.QueryIndex = v
+			case "operationName":
+				if body[v] != '"' && !isNull {
+					return errorAt(body, v, ErrInvalRequest)
+				}
+				operationName = Span{Tail: v, Head: x}
+				if isNull {
+					operationName = Span{}
+				}
+			case "variables", "extensions":
+				if body[v] != '{' && !isNull {
+					return errorAt(body, v, ErrInvalRequest)
+				}
+				s := Span{Tail: v, Head: x}
+				if isNull {
+					s = Span{}
+				}
+				if k[0] == 'v' {
+					r.Variables = s
+				} else {
+					r.Extensions = s
+				}
+			}
+			if x = skipJSONSpace(body, x); x >= len(body) {
+				return errorAt(body, x, ErrInvalRequest)
+			}
+			if body[x] == '}' {
+				x++
+				break
+			}
+			if body[x] != ',' {
+				return errorAt(body, x, ErrInvalRequest)
+			}
+			x = skipJSONSpace(body, x+1)
+		}
+	}
+	if x = skipJSONSpace(body, x); x < len(body) {
+		return errorAt(body, x, ErrInvalRequest)
+	}
+	if r.QueryIndex < 0 {
+		return errorAt(body, object, ErrInvalRequest)
+	}
+
+	if !operationName.IsEmpty() {
+		l := body[operationName.Tail:operationName.Head]
+		var err Error
+		r.buffer, err = appendUnescapedJSON(r.buffer[:0], l, nil)
+		if err.IsErr() {
+			err.Index += operationName.Tail
+			err.Line, err.Column = LineColumn(body, err.Index)
+			return err
+		}
+		r.OperationName = r.buffer
+	}
+	return Error{}
+}
+
+// scanHTTPQuery decodes and scans the query of the request in body
+// described by r, check is set as the check of the iterator.
+func scanHTTPQuery(
+	body []byte,
+	r *HTTPRequest,
+	check, fn func(*Iterator) (err bool),
+) Error {
+	query := body[r.QueryIndex:skipJSONString(body, r.QueryIndex)]
+	err := scanSource(jsonSource{str: query, m: r.IndexMap}, check, fn)
+	if err.Code == ErrInvalJSON {
+		err.Index += r.QueryIndex
+		err.Line, err.Column = LineColumn(body, err.Index)
+	}
+	return err
+}
+
+// skipJSONSpace returns the index of the first
+// non-whitespace character at or after index x.
+func skipJSONSpace(b []byte, x int) int {
+	for x < len(b) {
+		switch b[x] {
+		case ' ', '\t', '\n', '\r':
+			x++
+			continue
+		}
+		break
+	}
+	return x
+}
+
+// skipJSONString returns the index following the JSON string literal
+// beginning at index x, or -1 if there's no string literal at x.
+// The escape sequences aren't validated.
+func skipJSONString(b []byte, x int) int {
+	if x >= len(b) || b[x] != '"' {
+		return -1
+	}
+	for x++; x < len(b); x++ {
+		switch b[x] {
+		case '\\':
+			x++
+		case '"':
+			return x + 1
+		}
+	}
+	return -1
+}
+
+// skipJSONValue returns the index following the JSON value
+// beginning at index x, or -1 if there's no value at x.
+// Strings and numbers aren't validated.
+func skipJSONValue(b []byte, x int) int {
+	if x >= len(b) {
+		return -1
+	}
+	switch c := b[x]; {
+	case c == '"':
+		return skipJSONString(b, x)
+	case c == '{' || c == '[':
+		depth := 0
+		for x < len(b) {
+			switch b[x] {
+			case '"':
+				if x = skipJSONString(b, x); x < 0 {
+					return -1
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth < 1 {
+					return x + 1
+				}
+			}
+			x++
+		}
+		return -1
+	case c == 't':
+		return skipJSONLiteral(b, x, "true")
+	case c == 'f':
+		return skipJSONLiteral(b, x, "false")
+	case c == 'n':
+		return skipJSONLiteral(b, x, "null")
+	case c == '-' || (c >= '0' && c <= '9'):
+		for x++; x < len(b); x++ {
+			switch c := b[x]; {
+			case c >= '0' && c <= '9',
+				c == '.', c == 'e', c == 'E', c == '+', c == '-':
+				continue
+			}
+			break
+		}
+		return x
+	}
+	return -1
+}
+
+func skipJSONLiteral(b []byte, x int, literal string) int {
+	if len(b)-x < len(literal) || string(b[x:x+len(literal)]) != literal {
+		return -1
+	}
+	return x + len(literal)
+}
@@ -0,0 +1,126 @@
+package gqlscan_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanHTTPRequestBody(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			body, err := json.Marshal(map[string]any{
+				"query":     td.input,
+				"variables": map[string]any{"a": []any{1, "}"}},
+			})
+			require.NoError(t, err)
+
+			var r gqlscan.HTTPRequest
+			j := 0
+			e := gqlscan.ScanHTTPRequestBody(body, &r, func(i *gqlscan.Iterator) bool {
+				require.Equal(t, td.expect[j].Type, i.Token())
+				require.Equal(t, td.expect[j].Value, string(i.Value()))
+				j++
+				return false
+			})
+			require.False(t, e.IsErr(), "unexpected error: %s", e)
+			require.Len(t, td.expect, j)
+			require.Nil(t, r.OperationName)
+			require.Equal(t, `{"a":[1,"}"]}`,
+				string(body[r.Variables.Tail:r.Variables.Head]))
+			require.True(t, r.Extensions.IsEmpty())
+		})
+	}
+}
+
+func TestScanHTTPRequestBodyFields(t *testing.T) {
+	body := []byte(` {
+		"extensions": {"persistedQuery": {"version": 1}},
+		"operationName": "Q\u0031",
+		"variables": null,
+		"unknown": [true, false, null, -1.5e+3, {"x": "\"]"}],
+		"query": "query Q1 {\n  a(b: \"\\u00e4\") c(}"
+	} `)
+	var m gqlscan.JSONIndexMap
+	r := gqlscan.HTTPRequest{IndexMap: &m}
+	var fields []string
+	err := gqlscan.ScanHTTPRequestBody(body, &r, func(i *gqlscan.Iterator) bool {
+		if i.Token() == gqlscan.TokenField {
+			fields = append(fields, string(i.Value()))
+		}
+		return false
+	})
+	require.Equal(t,
+		"error at index 30 ('}'): unexpected token; expected argument name",
+		err.Error())
+	require.Equal(t, []string{"a", "c"}, fields)
+	require.Equal(t, "Q1", string(r.OperationName))
+	require.True(t, r.Variables.IsEmpty())
+	require.Equal(t, `{"persistedQuery": {"version": 1}}`,
+		string(body[r.Extensions.Tail:r.Extensions.Head]))
+	require.Equal(t, byte('}'), body[r.QueryIndex+m.LiteralIndex(err.Index)])
+}
+
+func TestScanHTTPRequestBodyErr(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), ``,
+			"error at index 0 (0x0): invalid request"},
+		{decl(1), `[]`,
+			"error at index 0 ('['): invalid request"},
+		{decl(1), `{}`,
+			"error at index 0 ('{'): invalid request"},
+		{decl(1), ` {"variables": {}} `,
+			"error at index 1 ('{'): invalid request"},
+		{decl(1), `{"query": 42}`,
+			"error at index 10 ('4'): invalid request"},
+		{decl(1), `{"query": "{a}", "operationName": 1}`,
+			"error at index 34 ('1'): invalid request"},
+		{decl(1), `{"query": "{a}", "variables": []}`,
+			"error at index 30 ('['): invalid request"},
+		{decl(1), `{"query": "{a}" "variables": {}}`,
+			"error at index 16 ('\"'): invalid request"},
+		{decl(1), `{"query": "{a}",}`,
+			"error at index 16 ('}'): invalid request"},
+		{decl(1), `{"query": "{a}"`,
+			"error at index 15 (0x0): invalid request"},
+		{decl(1), `{"query": "{a}"} x`,
+			"error at index 17 ('x'): invalid request"},
+		{decl(1), `{"query": "{a}", "x": nul}`,
+			"error at index 22 ('n'): invalid request"},
+		{decl(1), `{"query": "{a\x}"}`,
+			"error at index 13 ('\\'): invalid JSON string"},
+		{decl(1), `{"query": "{a}", "operationName": "\u12"}`,
+			"error at index 35 ('\\'): invalid JSON string"},
+		{decl(1), `{"query": "{a("}`,
+			"error at index 3: unexpected end of file; expected argument name"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var r gqlscan.HTTPRequest
+			err := gqlscan.ScanHTTPRequestBody([]byte(td.input), &r, noop)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
+
+func TestScanHTTPRequestBodyUnmodified(t *testing.T) {
+	const input = `{"query":"{a(b:\"\\u00e4\")}","operationName":"Q\u0031"}`
+	body := []byte(input)
+	var r gqlscan.HTTPRequest
+	var values []string
+	err := gqlscan.ScanHTTPRequestBody(body, &r, func(i *gqlscan.Iterator) bool {
+		values = append(values, string(i.Value()))
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, input, string(body))
+	require.Equal(t, "Q1", string(r.OperationName))
+	require.Equal(t, []string{"", "", "a", "", "b", `\u00e4`, "", "", ""}, values)
+}
@@ -0,0 +1,115 @@
+package gqlscan
+
+// HTTPGetRequest describes a GraphQL-over-HTTP GET request.
+// The zero value is ready to use, its buffer is reused
+// by consecutive calls to ScanHTTPGetQuery.
+type HTTPGetRequest struct {
+	// Query, OperationName, Variables and Extensions are
+	// the decoded parameters "query", "operationName", "variables"
+	// and "extensions" and are nil if absent.
+	Query, OperationName, Variables, Extensions []byte
+
+	buffer []byte
+}
+
+// ScanHTTPGetQuery is similar to Scan but scans the document
+// in parameter "query" of the URL query string rawQuery
+// and describes the request in r.
+// The parameters are percent-decoded into the buffer of r.
+// If rawQuery contains an invalid percent-encoding or no "query"
+// then an error with code ErrInvalRequest is returned whose index
+// refers to rawQuery, the indexes of the iterator and of other errors
+// refer to r.Query.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanHTTPGetQuery returns!
+func ScanHTTPGetQuery(
+	rawQuery []byte,
+	r *HTTPGetRequest,
+	fn func(*Iterator) (err bool),
+) Error {
+	r.Query, r.OperationName, r.Variables, r.Extensions = nil, nil, nil, nil
+
+	// A decoded parameter is never longer than its encoding,
+	// appending to the buffer never moves the decoded parameters.
+	if cap(r.buffer) < len(rawQuery) {
+		r.buffer = make([]byte, 0, len(rawQuery))
+	}
+	r.buffer = r.buffer[:0]
+
+	for x := 0; x <= len(rawQuery); {
+		end := x
+		for end < len(rawQuery) && rawQuery[end] != '&' {
+			end++
+		}
+		key, value := rawQuery[x:end], []byte(nil)
+		for k, c := range key {
+			if c == '=' {
+				key, value = key[:k], key[k+1:]
+				break
+			}
+		}
+		var p *[]byte
+		switch string(key) {
+		case "query":
+			p = &r.Query
+		case "operationName":
+			p = &r.OperationName
+		case "variables":
+			p = &r.Variables
+		case "extensions":
+			p = &r.Extensions
+		}
+		if p != nil {
+			start := len(r.buffer)
+			var err int
+			if r.buffer, err = appendPercentDecoded(r.buffer, value); err > -1 {
+				return errorAt(rawQuery, end-len(value)+err, ErrInvalRequest)
+			}
+			*p = r.buffer[start:len(r.buffer):len(r.buffer)]
+		}
+		x = end + 1
+	}
+	if r.Query == nil {
+		return errorAt(rawQuery, 0, ErrInvalRequest)
+	}
+	return Scan(r.Query, fn)
+}
+
+// appendPercentDecoded appends the percent-decoded s to dst decoding '+'
+// as space and returns the extended buffer and -1, or the index of
+// the first invalid percent-encoding in s.
+func appendPercentDecoded(dst, s []byte) ([]byte, int) {
+	for x := 0; x < len(s); x++ {
+		switch c := s[x]; c {
+		case '+':
+			dst = append(dst, ' ')
+		case '%':
+			if x+2 >= len(s) {
+				return dst, x
+			}
+			h, ok1 := unhex(s[x+1])
+			l, ok2 := unhex(s[x+2])
+			if !ok1 || !ok2 {
+				return dst, x
+			}
+			dst = append(dst, h<<4|l)
+			x += 2
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst, -1
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
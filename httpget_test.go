@@ -0,0 +1,92 @@
+package gqlscan_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanHTTPGetQuery(t *testing.T) {
+	var r gqlscan.HTTPGetRequest
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			q := url.Values{
+				"query":     {td.input},
+				"variables": {`{"a": "&=+%"}`},
+			}.Encode()
+
+			j := 0
+			err := gqlscan.ScanHTTPGetQuery([]byte(q), &r, func(i *gqlscan.Iterator) bool {
+				require.Equal(t, td.expect[j].Type, i.Token())
+				require.Equal(t, td.expect[j].Value, string(i.Value()))
+				j++
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, j)
+			require.Equal(t, td.input, string(r.Query))
+			require.Equal(t, `{"a": "&=+%"}`, string(r.Variables))
+			require.Nil(t, r.OperationName)
+			require.Nil(t, r.Extensions)
+		})
+	}
+}
+
+func TestScanHTTPGetQueryParams(t *testing.T) {
+	var r gqlscan.HTTPGetRequest
+	var fields []string
+	err := gqlscan.ScanHTTPGetQuery(
+		[]byte("x=1&operationName=Q&query=query+Q+%7Ba+b%7D&extensions=&y"),
+		&r,
+		func(i *gqlscan.Iterator) bool {
+			if i.Token() == gqlscan.TokenField {
+				fields = append(fields, string(i.Value()))
+			}
+			return false
+		},
+	)
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, []string{"a", "b"}, fields)
+	require.Equal(t, "query Q {a b}", string(r.Query))
+	require.Equal(t, "Q", string(r.OperationName))
+	require.Equal(t, []byte{}, r.Extensions)
+	require.Nil(t, r.Variables)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		gqlscan.ScanHTTPGetQuery(
+			[]byte("query=%7Ba%7D&variables=%7B%7D"), &r,
+			func(*gqlscan.Iterator) bool { return false },
+		)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestScanHTTPGetQueryErr(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), ``, "error at index 0 (0x0): invalid request"},
+		{decl(1), `variables=%7B%7D`,
+			"error at index 0 ('v'): invalid request"},
+		{decl(1), `query=%7Ba%7`,
+			"error at index 10 ('%'): invalid request"},
+		{decl(1), `operationName=%zz&query=%7Ba%7D`,
+			"error at index 14 ('%'): invalid request"},
+		{decl(1), `query=%7Ba(%7D`,
+			"error at index 3 ('}'): unexpected token; expected argument name"},
+		{decl(1), `query`,
+			"error at index 0: unexpected end of file; expected definition"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var r gqlscan.HTTPGetRequest
+			err := gqlscan.ScanHTTPGetQuery([]byte(td.input), &r, noop)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package gqlscan
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+)
+
+// Loader loads the document at the given path.
+type Loader func(path string) ([]byte, error)
+
+// ImportError is an error that occurred while resolving
+// the document at Path.
+type ImportError struct {
+	Path string
+	Err  error
+}
+
+func (e *ImportError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *ImportError) Unwrap() error { return e.Err }
+
+// ResolveImports resolves the `#import "path"` comments of document src
+// located at p loading the imported documents using load.
+// Paths starting with "./" or "../" are relative to the importing
+// document. Every document is loaded at most once.
+// Returns the minified combined document containing all definitions of
+// src followed by the definitions of the imported documents where each
+// fragment is defined only once. Returns *ImportError if a document
+// can't be loaded, is invalid or defines a fragment that
+// is defined differently elsewhere.
+func ResolveImports(src []byte, p string, load Loader) ([]byte, error) {
+	r := resolver{
+		load:      load,
+		seen:      map[string]bool{},
+		fragments: map[string][]byte{},
+	}
+	if err := r.resolve(p, src); err != nil {
+		return nil, err
+	}
+	return r.out, nil
+}
+
+type resolver struct {
+	load      Loader
+	seen      map[string]bool
+	fragments map[string][]byte
+	out       []byte
+	w         writer
+}
+
+func (r *resolver) resolve(p string, src []byte) error {
+	r.seen[p] = true
+
+	// Write the definitions and remember where each one starts.
+	var (
+		starts []int
+		names  []string
+	)
+	r.w.reset(nil)
+	if err := ScanAll(src, func(i *Iterator) {
+		switch t := i.Token(); t {
+		case TokenDefQry, TokenDefMut, TokenDefSub, TokenDefFrag:
+			starts, names = append(starts, len(r.w.dst)), append(names, "")
+		case TokenFragName:
+			names[len(names)-1] = string(i.Value())
+		}
+		r.w.write(i.Token(), i.Value())
+	}); err.IsErr() {
+		return &ImportError{Path: p, Err: err}
+	}
+	defs := r.w.dst
+	for x, s := range starts {
+		e := len(defs)
+		if x+1 < len(starts) {
+			e = starts[x+1]
+		}
+		d := defs[s:e]
+		if n := names[x]; n != "" {
+			if f, ok := r.fragments[n]; ok {
+				if !bytes.Equal(f, d) {
+					return &ImportError{Path: p, Err: fmt.Errorf(
+						"conflicting definitions of fragment %q", n,
+					)}
+				}
+				continue
+			}
+			r.fragments[n] = d
+		}
+		r.out = append(r.out, d...)
+	}
+
+	for _, imp := range importPaths(src) {
+		if hasDotPrefix(imp) {
+			imp = path.Join(path.Dir(p), imp)
+		}
+		if r.seen[imp] {
+			continue
+		}
+		b, err := r.load(imp)
+		if err != nil {
+			return &ImportError{Path: imp, Err: err}
+		}
+		if err := r.resolve(imp, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasDotPrefix(p string) bool {
+	return len(p) > 1 && p[0] == '.' &&
+		(p[1] == '/' || (len(p) > 2 && p[1] == '.' && p[2] == '/'))
+}
+
+// importPaths returns the paths of all `#import "path"` comments
+// in src skipping string values.
+func importPaths(src []byte) (paths []string) {
+	for i := 0; i < len(src); {
+		switch src[i] {
+		case '"':
+			i = skipLiteral(src, i)
+		case '#':
+			e := skipLiteral(src, i)
+			if p, ok := importPath(src[i:e]); ok {
+				paths = append(paths, p)
+			}
+			i = e
+		default:
+			i++
+		}
+	}
+	return paths
+}
+
+// importPath returns the path of comment c if c is an import comment.
+func importPath(c []byte) (string, bool) {
+	if !bytes.HasPrefix(c, []byte("#import")) {
+		return "", false
+	}
+	c = bytes.TrimSpace(c[len("#import"):])
+	if len(c) < 2 || c[0] != '"' || c[len(c)-1] != '"' {
+		return "", false
+	}
+	return string(c[1 : len(c)-1]), true
+}
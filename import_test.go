@@ -0,0 +1,113 @@
+package gqlscan_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveImports(t *testing.T) {
+	files := map[string]string{
+		"app/UserFields.graphql": `#import "./Avatar.graphql"
+			fragment UserFields on User { name ...Avatar }`,
+		"app/Avatar.graphql": `# Avatar
+			fragment Avatar on User { avatar(size: "#import \"x\"") }`,
+		"app/Friends.graphql": `#import "./UserFields.graphql"
+			#import "../lib/Avatar.graphql"
+			fragment Friends on User { friends { ...UserFields } }`,
+		"lib/Avatar.graphql": `
+			fragment Avatar on User { avatar(size: "#import \"x\"") }`,
+		"lib/Conflict.graphql": `fragment Avatar on User { avatar }`,
+	}
+	load := func(p string) ([]byte, error) {
+		if f, ok := files[p]; ok {
+			return []byte(f), nil
+		}
+		return nil, fs.ErrNotExist
+	}
+
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `{ a }`, `{a}`},
+		{decl(1), `#import "./UserFields.graphql"
+			#import "./UserFields.graphql"
+			query Q { me { ...UserFields } }`,
+			`query Q{me{...UserFields}}` +
+				`fragment UserFields on User{name...Avatar}` +
+				`fragment Avatar on User{avatar(size:"#import \"x\"")}`},
+		{decl(1), `#import "./Friends.graphql"
+			# import "./Ignored.graphql"
+			{ f(s: "#import \"./Ignored.graphql\"", b: """
+			#import "./Ignored.graphql"
+			""") ...Friends }`,
+			`{f(s:"#import \"./Ignored.graphql\""b:"""
+			#import "./Ignored.graphql"
+			""")...Friends}` +
+				`fragment Friends on User{friends{...UserFields}}` +
+				`fragment UserFields on User{name...Avatar}` +
+				`fragment Avatar on User{avatar(size:"#import \"x\"")}`},
+		{decl(1), `{ f(b: """\\"""
+			#import "./Ignored.graphql"
+			""") }`,
+			`{f(b:"""\\"""
+			#import "./Ignored.graphql"
+			""")}`},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			a, err := gqlscan.ResolveImports([]byte(td.input), "app/Q.graphql", load)
+			require.NoError(t, err)
+			require.Equal(t, td.expect, string(a))
+		})
+	}
+}
+
+func TestResolveImportsErr(t *testing.T) {
+	files := map[string]string{
+		"A.graphql":         `fragment A on T { a }`,
+		"AConflict.graphql": `fragment A on T { b }`,
+		"Invalid.graphql":   `fragment A on T {`,
+	}
+	load := func(p string) ([]byte, error) {
+		if f, ok := files[p]; ok {
+			return []byte(f), nil
+		}
+		return nil, fs.ErrNotExist
+	}
+
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `#import "./Missing.graphql"
+			{ a }`,
+			"Missing.graphql: file does not exist"},
+		{decl(1), `#import "./Invalid.graphql"
+			{ a }`,
+			"Invalid.graphql: error at index 17: unexpected end of file; " +
+				"expected selection"},
+		{decl(1), `#import "./A.graphql"
+			#import "./AConflict.graphql"
+			{ ...A }`,
+			`AConflict.graphql: conflicting definitions of fragment "A"`},
+		{decl(1), `{ a `,
+			"Q.graphql: error at index 4: unexpected end of file; " +
+				"expected field name or alias"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			a, err := gqlscan.ResolveImports([]byte(td.input), "Q.graphql", load)
+			require.Nil(t, a)
+			require.Equal(t, td.expect, err.Error())
+			var ie *gqlscan.ImportError
+			require.True(t, errors.As(err, &ie), fmt.Sprintf("%T", err))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package gqlscan
+
+// Report describes a document inspected by Inspect.
+type Report struct {
+	// Depth is the greatest selection set nesting depth and
+	// ValueDepth the greatest list and object value nesting depth.
+	Depth      int
+	ValueDepth int
+
+	Tokens          int
+	Operations      int
+	Fragments       int
+	FragmentSpreads int
+	Aliases         int
+
+	// RootFields is the greatest number of root selections
+	// of an operation including fragment spreads and inline fragments.
+	RootFields int
+
+	// Arguments is the greatest number of arguments of an argument
+	// list and Directives of directives of a location.
+	Arguments  int
+	Directives int
+
+	// NameLength is the length of the longest name and
+	// StringValueBytes of the longest raw string value.
+	NameLength       int
+	StringValueBytes int
+
+	// Bytes is the size of the document.
+	Bytes int
+
+	// Complexity is the score computed by Complexity
+	// with the options of the limits.
+	Complexity int
+
+	// Introspection is true if the document selects
+	// __schema or __type.
+	Introspection bool
+
+	// Typenames is the number of __typename selections and
+	// SetTypenames the greatest number of a single selection set.
+	Typenames    int
+	SetTypenames int
+
+	// Exceeded holds the codes of the exceeded limits in order of
+	// the fields of Limits: ErrSelTooDeep, ErrValTooDeep,
+	// ErrTooManyTokens, ErrTooManyOprs, ErrTooManyFrags,
+	// ErrTooManySpreads, ErrTooManyAliases, ErrTooManyRootFields,
+	// ErrTooManyArgs, ErrTooManyDirs, ErrNameTooLong, ErrStrTooLong,
+	// ErrDocTooLarge, ErrTooComplex and ErrTooManyTypenames for both
+	// MaxTypenames and MaxSetTypenames.
+	Exceeded []ErrorCode
+}
+
+// Inspect scans document src once and returns a report describing it
+// including the limits it exceeds. Unlike ScanWithOptions it doesn't
+// stop at exceeded limits except for limits.MaxDuration, Inspect
+// fails with ErrTimeout if it's exceeded.
+// Returns an error if src is invalid.
+func Inspect(src []byte, limits Limits) (Report, Error) {
+	l := newLimiter(Limits{
+		Complexity:  limits.Complexity,
+		MaxDuration: limits.MaxDuration,
+	}, true)
+	i := iteratorPool.Get().(*Iterator)
+	defer iteratorPool.Put(i)
+	i.str, i.check = src, l.check
+	defer func() { i.check = nil }()
+	if err := i.scan(0, func(*Iterator) bool { return false }); err.IsErr() {
+		if l.code != 0 {
+			return Report{}, l.error(src, err)
+		}
+		return Report{}, err
+	}
+	r := l.report
+	r.Bytes = len(src)
+
+	for _, l := range [...]struct {
+		actual, limit int
+		code          ErrorCode
+	}{
+		{r.Depth, limits.MaxDepth, ErrSelTooDeep},
+		{r.ValueDepth, limits.MaxValueDepth, ErrValTooDeep},
+		{r.Tokens, limits.MaxTokens, ErrTooManyTokens},
+		{r.Operations, limits.MaxOperations, ErrTooManyOprs},
+		{r.Fragments, limits.MaxFragments, ErrTooManyFrags},
+		{r.FragmentSpreads, limits.MaxFragmentSpreads, ErrTooManySpreads},
+		{r.Aliases, limits.MaxAliases, ErrTooManyAliases},
+		{r.RootFields, limits.MaxRootFields, ErrTooManyRootFields},
+		{r.Arguments, limits.MaxArguments, ErrTooManyArgs},
+		{r.Directives, limits.MaxDirectives, ErrTooManyDirs},
+		{r.NameLength, limits.MaxNameLength, ErrNameTooLong},
+		{r.StringValueBytes, limits.MaxStringValueBytes, ErrStrTooLong},
+		{r.Bytes, limits.MaxInputBytes, ErrDocTooLarge},
+		{r.Complexity, limits.MaxComplexity, ErrTooComplex},
+		{r.Typenames, limits.MaxTypenames, ErrTooManyTypenames},
+		{r.SetTypenames, limits.MaxSetTypenames, ErrTooManyTypenames},
+	} {
+		if l.limit > 0 && l.actual > l.limit {
+			r.Exceeded = append(r.Exceeded, l.code)
+		}
+	}
+	return r, Error{}
+}
@@ -0,0 +1,175 @@
+package gqlscan_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	src := []byte(`
+		query A($v: [Int] = [[1]]) {
+			x: a(first: 2) { b { c } }
+			y: a { __type(name: "T") { name } }
+			...F
+			... on Query { d }
+		}
+		mutation { e(o: {p: {q: 1}}) }
+		fragment F on Query { f { ...G } }
+		fragment G on F { g }
+	`)
+	for _, td := range []struct {
+		decl   string
+		limits gqlscan.Limits
+		expect gqlscan.Report
+	}{
+		{decl(1), gqlscan.Limits{}, gqlscan.Report{
+			Depth:            3,
+			ValueDepth:       2,
+			Tokens:           77,
+			Operations:       2,
+			Fragments:        2,
+			FragmentSpreads:  2,
+			Aliases:          2,
+			RootFields:       4,
+			Arguments:        1,
+			NameLength:       6,
+			StringValueBytes: 1,
+			Bytes:            len(src),
+			Complexity:       10,
+			Introspection:    true,
+		}},
+		{decl(1), gqlscan.Limits{
+			MaxDepth:           3,
+			MaxValueDepth:      2,
+			MaxTokens:          77,
+			MaxOperations:      2,
+			MaxFragments:       2,
+			MaxFragmentSpreads: 2,
+			MaxAliases:         2,
+			MaxRootFields:      4,
+			MaxArguments:       1,
+			MaxNameLength:      6,
+			MaxInputBytes:      len(src),
+			MaxComplexity:      12,
+			Complexity: gqlscan.ComplexityOptions{
+				ListArguments: []string{"first"},
+			},
+		}, gqlscan.Report{
+			Depth:            3,
+			ValueDepth:       2,
+			Tokens:           77,
+			Operations:       2,
+			Fragments:        2,
+			FragmentSpreads:  2,
+			Aliases:          2,
+			RootFields:       4,
+			Arguments:        1,
+			NameLength:       6,
+			StringValueBytes: 1,
+			Bytes:            len(src),
+			Complexity:       12,
+			Introspection:    true,
+		}},
+		{decl(1), gqlscan.Limits{
+			MaxDepth:           2,
+			MaxValueDepth:      1,
+			MaxTokens:          76,
+			MaxOperations:      1,
+			MaxFragments:       1,
+			MaxFragmentSpreads: 1,
+			MaxAliases:         1,
+			MaxRootFields:      3,
+			MaxNameLength:      5,
+			MaxInputBytes:      len(src) - 1,
+			MaxComplexity:      9,
+		}, gqlscan.Report{
+			Depth:            3,
+			ValueDepth:       2,
+			Tokens:           77,
+			Operations:       2,
+			Fragments:        2,
+			FragmentSpreads:  2,
+			Aliases:          2,
+			RootFields:       4,
+			Arguments:        1,
+			NameLength:       6,
+			StringValueBytes: 1,
+			Bytes:            len(src),
+			Complexity:       10,
+			Introspection:    true,
+			Exceeded: []gqlscan.ErrorCode{
+				gqlscan.ErrSelTooDeep,
+				gqlscan.ErrValTooDeep,
+				gqlscan.ErrTooManyTokens,
+				gqlscan.ErrTooManyOprs,
+				gqlscan.ErrTooManyFrags,
+				gqlscan.ErrTooManySpreads,
+				gqlscan.ErrTooManyAliases,
+				gqlscan.ErrTooManyRootFields,
+				gqlscan.ErrNameTooLong,
+				gqlscan.ErrDocTooLarge,
+				gqlscan.ErrTooComplex,
+			},
+		}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			r, err := gqlscan.Inspect(src, td.limits)
+			require.False(t, err.IsErr())
+			require.Equal(t, td.expect, r)
+		})
+	}
+}
+
+func TestInspectErr(t *testing.T) {
+	r, err := gqlscan.Inspect([]byte(`{a`), gqlscan.Limits{MaxTokens: 1})
+	require.Equal(t,
+		"error at index 2: unexpected end of file; expected field name or alias",
+		err.Error())
+	require.Zero(t, r)
+}
+
+func TestInspectMaxDuration(t *testing.T) {
+	src := []byte("{" + strings.Repeat("a ", 300) + "}")
+	r, err := gqlscan.Inspect(src, gqlscan.Limits{MaxDuration: time.Hour})
+	require.False(t, err.IsErr())
+	require.Equal(t, 304, r.Tokens)
+
+	r, err = gqlscan.Inspect(src, gqlscan.Limits{MaxDuration: time.Nanosecond})
+	require.Equal(t, gqlscan.ErrTimeout, err.Code)
+	require.Equal(t, 1+253*2, err.Index)
+	require.Zero(t, r)
+}
+
+func TestInspectTypenames(t *testing.T) {
+	r, err := gqlscan.Inspect(
+		[]byte(`{__typename a {__typename __typename} __typename}`),
+		gqlscan.Limits{MaxTypenames: 3, MaxSetTypenames: 1},
+	)
+	require.False(t, err.IsErr())
+	require.Equal(t, 4, r.Typenames)
+	require.Equal(t, 2, r.SetTypenames)
+	require.Equal(t, []gqlscan.ErrorCode{
+		gqlscan.ErrTooManyTypenames,
+		gqlscan.ErrTooManyTypenames,
+	}, r.Exceeded)
+}
+
+func TestInspectArgumentsDirectives(t *testing.T) {
+	r, err := gqlscan.Inspect(
+		[]byte(`{a(x:1 y:2) @d @e(z:"abc") b(x:1)}`),
+		gqlscan.Limits{MaxArguments: 1, MaxDirectives: 1},
+	)
+	require.False(t, err.IsErr())
+	require.Equal(t, 2, r.Arguments)
+	require.Equal(t, 2, r.Directives)
+	require.Equal(t, 3, r.StringValueBytes)
+	require.Equal(t, []gqlscan.ErrorCode{
+		gqlscan.ErrTooManyArgs,
+		gqlscan.ErrTooManyDirs,
+	}, r.Exceeded)
+}
@@ -0,0 +1,65 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIteratorScan(t *testing.T) {
+	i := gqlscan.NewIterator()
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			i.Reset([]byte(td.input))
+			require.Zero(t, i.Token())
+			require.Nil(t, i.Value())
+			j := 0
+			err := i.Scan(func(it *gqlscan.Iterator) bool {
+				require.True(t, it == i)
+				require.Equal(t, td.expect[j].Type, it.Token())
+				require.Equal(t, td.expect[j].Value, string(it.Value()))
+				j++
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestIteratorScanErr(t *testing.T) {
+	i := gqlscan.NewIterator()
+	for _, td := range testdataErr {
+		t.Run(td.decl, func(t *testing.T) {
+			i.Reset([]byte(td.input))
+			err := i.Scan(func(*gqlscan.Iterator) bool { return false })
+			require.Equal(t, td.expectErr, err.Error())
+		})
+	}
+}
+
+func TestIteratorScanAllocs(t *testing.T) {
+	i := gqlscan.NewIterator()
+	src := []byte(`query Q($a: [[ID!]!]!) { f(a: $a) { g h } }`)
+	fn := func(*gqlscan.Iterator) bool { return false }
+	allocs := testing.AllocsPerRun(100, func() {
+		i.Reset(src)
+		i.Scan(fn)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestIteratorIsShorthand(t *testing.T) {
+	var actual []bool
+	err := gqlscan.ScanAll([]byte(
+		`{a} query {b} query Q {c} mutation {d} fragment F on T {e} { f }`,
+	), func(i *gqlscan.Iterator) {
+		if i.Token() == gqlscan.TokenDefEnd {
+			actual = append(actual, i.IsShorthand())
+		}
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, []bool{true, false, false, false, false, true}, actual)
+}
@@ -0,0 +1,185 @@
+package gqlscan
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ScanJSON is similar to Scan but str is a JSON string literal
+// including the enclosing quotes holding the document, for example
+// the value of "query" in a GraphQL-over-HTTP request body.
+// ScanJSON decodes the escape sequences of the whole literal into
+// a pooled buffer first and then scans the decoded document,
+// which saves allocating a decoded copy but not copying it.
+// str isn't modified and remains owned by the caller.
+// The indexes of the iterator and of the returned error refer to
+// the decoded document, except for errors with code ErrInvalJSON
+// which refer to str.
+//
+// WARNING: The values returned by the iterator refer to the pooled
+// buffer, copy them if they're used after ScanJSON returns.
+// *Iterator passed to fn should never be aliased and
+// used after ScanJSON returns!
+func ScanJSON(str []byte, fn func(*Iterator) (err bool)) Error {
+	return ScanSource(JSONSource(str), fn)
+}
+
+// ScanJSONIndexed is similar to ScanJSON but also records the
+// positions of the escape sequences in m if m != nil, which allows
+// mapping indexes of the decoded document, such as the index of
+// the returned error, back to indexes of the JSON string literal.
+// m is reset before the scan and its memory is reused.
+//
+// WARNING: The values returned by the iterator refer to the pooled
+// buffer, copy them if they're used after ScanJSONIndexed returns.
+// *Iterator passed to fn should never be aliased and
+// used after ScanJSONIndexed returns!
+func ScanJSONIndexed(
+	str []byte,
+	m *JSONIndexMap,
+	fn func(*Iterator) (err bool),
+) Error {
+	return ScanSource(jsonSource{str: str, m: m}, fn)
+}
+
+// JSONIndexMap maps indexes of a decoded JSON string
+// to indexes of its JSON string literal.
+// The zero value is an empty map ready to use.
+type JSONIndexMap struct {
+	// escapes holds the decoded and literal index following
+	// each escape sequence in order of appearance.
+	escapes []jsonEscape
+}
+
+type jsonEscape struct{ decoded, literal int }
+
+// LiteralIndex returns the index in the JSON string literal
+// corresponding to index decoded of the decoded string.
+// To get the index in a request body add the index
+// the literal begins at in the body.
+func (m *JSONIndexMap) LiteralIndex(decoded int) int {
+	// Find the last escape sequence preceding decoded.
+	l, h := 0, len(m.escapes)
+	for l < h {
+		x := int(uint(l+h) >> 1)
+		if m.escapes[x].decoded <= decoded {
+			l = x + 1
+		} else {
+			h = x
+		}
+	}
+	if l < 1 {
+		// Skip the opening quote.
+		return decoded + 1
+	}
+	e := m.escapes[l-1]
+	return e.literal + decoded - e.decoded
+}
+
+func (m *JSONIndexMap) add(decoded, literal int) {
+	if m != nil {
+		m.escapes = append(m.escapes, jsonEscape{decoded, literal})
+	}
+}
+
+// appendUnescapedJSON appends the decoded JSON string literal str
+// to dst and returns the extended buffer, str isn't modified.
+// The escape sequences are recorded in m if m != nil.
+// If str is invalid then dst is returned unchanged together
+// with the error.
+func appendUnescapedJSON(
+	dst, str []byte,
+	m *JSONIndexMap,
+) ([]byte, Error) {
+	if m != nil {
+		m.escapes = m.escapes[:0]
+	}
+	if len(str) < 2 || str[0] != '"' {
+		return dst, errorAt(str, 0, ErrInvalJSON)
+	}
+	start := len(dst)
+	for r := 1; r < len(str); {
+		switch c := str[r]; {
+		case c == '"':
+			if r != len(str)-1 {
+				// Trailing bytes after the closing quote.
+				return dst[:start], errorAt(str, r+1, ErrInvalJSON)
+			}
+			return dst, Error{}
+		case c < 0x20:
+			return dst[:start], errorAt(str, r, ErrInvalJSON)
+		case c != '\\':
+			dst = append(dst, c)
+			r++
+			continue
+		}
+
+		// Escape sequence
+		if r+1 >= len(str) {
+			return dst[:start], errorAt(str, r, ErrInvalJSON)
+		}
+		var b byte
+		switch str[r+1] {
+		case '"', '\\', '/':
+			b = str[r+1]
+		case 'b':
+			b = '\b'
+		case 'f':
+			b = '\f'
+		case 'n':
+			b = '\n'
+		case 'r':
+			b = '\r'
+		case 't':
+			b = '\t'
+		case 'u':
+			c, ok := hex4(str, r+2)
+			if !ok {
+				return dst[:start], errorAt(str, r, ErrInvalJSON)
+			}
+			r += 6
+			if utf16.IsSurrogate(c) {
+				c2, ok := rune(0), false
+				if r+1 < len(str) && str[r] == '\\' && str[r+1] == 'u' {
+					c2, ok = hex4(str, r+2)
+				}
+				if d := utf16.DecodeRune(c, c2); ok && d != utf8.RuneError {
+					c, r = d, r+6
+				} else {
+					c = utf8.RuneError
+				}
+			}
+			dst = utf8.AppendRune(dst, c)
+			m.add(len(dst)-start, r)
+			continue
+		default:
+			return dst[:start], errorAt(str, r, ErrInvalJSON)
+		}
+		dst = append(dst, b)
+		r += 2
+		m.add(len(dst)-start, r)
+	}
+	// Missing closing quote.
+	return dst[:start], errorAt(str, len(str), ErrInvalJSON)
+}
+
+// hex4 decodes the 4 hexadecimal digits at str[i:].
+func hex4(str []byte, i int) (r rune, ok bool) {
+	if i+4 > len(str) {
+		return 0, false
+	}
+	for _, c := range str[i : i+4] {
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c -= 'a' - 10
+		case c >= 'A' && c <= 'F':
+			c -= 'A' - 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(c)
+	}
+	return r, true
+}
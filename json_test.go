@@ -0,0 +1,131 @@
+package gqlscan_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanJSON(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			str, err := json.Marshal(td.input)
+			require.NoError(t, err)
+
+			j := 0
+			e := gqlscan.ScanJSON(str, func(i *gqlscan.Iterator) bool {
+				require.Equal(t, td.expect[j].Type, i.Token())
+				require.Equal(t, td.expect[j].Value, string(i.Value()))
+				j++
+				return false
+			})
+			require.False(t, e.IsErr(), "unexpected error: %s", e)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanJSONEscapes(t *testing.T) {
+	var values []string
+	err := gqlscan.ScanJSON(
+		[]byte(`"{f(a:\"\\\"\\u00e4\\n\", b:\"\u00e4\ud83d\ude00\/\", `+
+			`c:\"\ud83d\", d:\"\ud83d\u0041\")}"`),
+		func(i *gqlscan.Iterator) bool {
+			if i.Token() == gqlscan.TokenStr {
+				values = append(values, string(i.Value()))
+			}
+			return false
+		},
+	)
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, []string{
+		`\"\u00e4\n`, "ä😀/", "\uFFFD", "\uFFFDA",
+	}, values)
+}
+
+func TestScanJSONErr(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), ``, "error at index 0 (0x0): invalid JSON string"},
+		{decl(1), `{a}`, "error at index 0 ('{'): invalid JSON string"},
+		{decl(1), `"{a}`, "error at index 4 (0x0): invalid JSON string"},
+		{decl(1), `"{a}" `, "error at index 5 (' '): invalid JSON string"},
+		{decl(1), `"{a\x}"`, "error at index 3 ('\\'): invalid JSON string"},
+		{decl(1), `"{a\u12x4}"`, "error at index 3 ('\\'): invalid JSON string"},
+		{decl(1), `"{a\u12"`, "error at index 3 ('\\'): invalid JSON string"},
+		{decl(1), `"{a\"`, "error at index 5 (0x0): invalid JSON string"},
+		{decl(1), "\"{a\n}\"", "error at index 3 (0xa): invalid JSON string"},
+		{decl(1), `"{\n  a(\n}"`,
+			"error at index 7 ('}'): unexpected token; expected argument name"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanJSON([]byte(td.input), noop)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
+
+func TestScanJSONIndexed(t *testing.T) {
+	var m gqlscan.JSONIndexMap
+	for _, td := range []struct {
+		decl          string
+		input         string
+		expectErr     string
+		expectLiteral int
+	}{
+		{decl(1), `"{\n  a(\n}"`,
+			"error at index 7 ('}'): unexpected token; " +
+				"expected argument name", 10},
+		{decl(1), `"{a(b:\"\u00e4\\\"\\n\") c(}"`,
+			"error at index 17 ('}'): unexpected token; " +
+				"expected argument name", 27},
+		{decl(1), `"{a(b:\"\ud83d\ude00\") c"`,
+			"error at index 14: unexpected end of file; " +
+				"expected field name or alias", 25},
+		{decl(1), `"{a("`,
+			"error at index 3: unexpected end of file; " +
+				"expected argument name", 4},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			str := []byte(td.input)
+			err := gqlscan.ScanJSONIndexed(str, &m,
+				func(*gqlscan.Iterator) bool { return false })
+			require.Equal(t, td.expectErr, err.Error())
+			l := m.LiteralIndex(err.Index)
+			require.Equal(t, td.expectLiteral, l)
+			if l < len(td.input)-1 {
+				require.Equal(t, string(err.AtIndex), td.input[l:l+1])
+			}
+			require.Equal(t, td.input, string(str), "str was modified")
+		})
+	}
+}
+
+func TestJSONIndexMap(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			literal, err := json.Marshal(td.input)
+			require.NoError(t, err)
+			str := append([]byte(nil), literal...)
+
+			var m gqlscan.JSONIndexMap
+			e := gqlscan.ScanJSONIndexed(str, &m, func(i *gqlscan.Iterator) bool {
+				if v := i.Value(); len(v) > 0 {
+					l := m.LiteralIndex(i.IndexTail())
+					if v[0] != '\\' && v[0] != '"' && v[0] >= 0x20 {
+						require.Equal(t, v[0], literal[l], "at %d", l)
+					}
+				}
+				return false
+			})
+			require.False(t, e.IsErr(), "unexpected error: %s", e)
+		})
+	}
+}
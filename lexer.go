@@ -0,0 +1,145 @@
+package gqlscan
+
+// Lexer is a pull-based alternative to Scan returning
+// one token per call to Next.
+// The document is scanned incrementally one definition at a time,
+// the tokens of a definition are scanned and buffered once Next
+// reaches it, a consumer may stop calling Next at any time
+// without the remaining definitions being scanned.
+type Lexer struct {
+	str    []byte
+	tokens []lexerToken
+	x      int
+
+	// next is the index the next definition is scanned from,
+	// defs and ordinal are the numbers of definitions and tokens
+	// scanned before it.
+	next, defs, ordinal int
+
+	err  Error
+	done bool
+}
+
+type lexerToken struct {
+	token      Token
+	tail, head int
+	levelSel   int
+}
+
+// NewLexer creates a new lexer for str.
+func NewLexer(str []byte) *Lexer {
+	l := &Lexer{}
+	l.Reset(str)
+	return l
+}
+
+// Reset resets the lexer to str reusing its buffer.
+func (l *Lexer) Reset(str []byte) {
+	l.str, l.tokens, l.x = str, l.tokens[:0], -1
+	l.next, l.defs, l.ordinal = 0, 0, 0
+	l.err, l.done = Error{}, false
+}
+
+// Next advances the lexer to the next token and returns it.
+// Returns a zero Token and a zero Error once all tokens were returned.
+// If the document is invalid then all tokens preceding the error
+// are returned before the error.
+func (l *Lexer) Next() (Token, Error) {
+	if l.x < len(l.tokens) {
+		l.x++
+	}
+	if l.x >= len(l.tokens) && !l.done {
+		l.scanDefinition()
+	}
+	if l.x < len(l.tokens) {
+		return l.tokens[l.x].token, Error{}
+	}
+	return 0, l.err
+}
+
+// scanDefinition replaces the buffered tokens with the tokens
+// of the definition beginning at l.next.
+func (l *Lexer) scanDefinition() {
+	l.ordinal += len(l.tokens)
+	l.tokens, l.x = l.tokens[:0], 0
+	end := -1
+	err := scan(l.str, l.next, func(i *Iterator) bool {
+		l.tokens = append(l.tokens, lexerToken{
+			token:    i.token,
+			tail:     i.tail,
+			head:     i.head,
+			levelSel: i.levelSel,
+		})
+		if i.token == TokenDefEnd {
+			end = i.head + 1
+			return true
+		}
+		return false
+	})
+	if end > -1 {
+		l.next, l.defs = end, l.defs+1
+		return
+	}
+	l.done = true
+	if l.next > 0 &&
+		err.Code == ErrUnexpEOF &&
+		err.Expectation == ExpectDef {
+		// Only ignored characters follow the last definition.
+		return
+	}
+	err.DefinitionIndex += l.defs
+	err.TokenOrdinal += l.ordinal
+	l.err = err
+}
+
+// Token returns the current token type.
+func (l *Lexer) Token() Token {
+	if t, ok := l.current(); ok {
+		return t.token
+	}
+	return 0
+}
+
+// Value returns the raw value of the current token.
+//
+// WARNING: The returned byte slice refers to the same underlying memory
+// as the byte slice passed to NewLexer and Reset,
+// copy it or use with caution!
+func (l *Lexer) Value() []byte {
+	if t, ok := l.current(); ok && t.tail > -1 {
+		return l.str[t.tail:t.head]
+	}
+	return nil
+}
+
+// LevelSelect returns the selector level of the current token.
+func (l *Lexer) LevelSelect() int {
+	if t, ok := l.current(); ok {
+		return t.levelSel
+	}
+	return 0
+}
+
+// IndexHead returns the head index of the current token.
+func (l *Lexer) IndexHead() int {
+	if t, ok := l.current(); ok {
+		return t.head
+	}
+	return 0
+}
+
+// IndexTail returns the tail index of the current token.
+// Returns -1 if the current token doesn't reflect a dynamic value.
+func (l *Lexer) IndexTail() int {
+	if t, ok := l.current(); ok {
+		return t.tail
+	}
+	return -1
+}
+
+func (l *Lexer) current() (lexerToken, bool) {
+	if l.x < 0 || l.x >= len(l.tokens) {
+		return lexerToken{}, false
+	}
+	return l.tokens[l.x], true
+}
@@ -0,0 +1,99 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLexer(t *testing.T) {
+	l := gqlscan.NewLexer(nil)
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			l.Reset([]byte(td.input))
+			j := 0
+			for {
+				tok, err := l.Next()
+				require.False(t, err.IsErr(), "unexpected error: %s", err)
+				if tok == 0 {
+					break
+				}
+				require.Equal(t, td.expect[j].Type, tok)
+				require.Equal(t, td.expect[j].Type, l.Token())
+				require.Equal(t, td.expect[j].Value, string(l.Value()))
+				j++
+			}
+			require.Len(t, td.expect, j)
+
+			// Next keeps returning the end.
+			tok, err := l.Next()
+			require.Zero(t, tok)
+			require.False(t, err.IsErr())
+		})
+	}
+}
+
+func TestLexerErr(t *testing.T) {
+	l := gqlscan.NewLexer([]byte(`{a(b:1) c(}`))
+	var tokens []gqlscan.Token
+	var err gqlscan.Error
+	for {
+		var tok gqlscan.Token
+		if tok, err = l.Next(); tok == 0 {
+			break
+		}
+		tokens = append(tokens, tok)
+	}
+	require.Equal(t, []gqlscan.Token{
+		gqlscan.TokenDefQry,
+		gqlscan.TokenSet,
+		gqlscan.TokenField,
+		gqlscan.TokenArgList,
+		gqlscan.TokenArgName,
+		gqlscan.TokenInt,
+		gqlscan.TokenArgListEnd,
+		gqlscan.TokenField,
+		gqlscan.TokenArgList,
+	}, tokens)
+	require.Equal(t,
+		"error at index 10 ('}'): unexpected token; expected argument name",
+		err.Error())
+	require.Zero(t, l.Token())
+	require.Nil(t, l.Value())
+
+	// The error is returned repeatedly.
+	tok, err2 := l.Next()
+	require.Zero(t, tok)
+	require.Equal(t, err, err2)
+}
+
+func TestLexerIncremental(t *testing.T) {
+	// The second definition is scanned only once Next reaches it.
+	l := gqlscan.NewLexer([]byte(`{a} {b(}`))
+	for _, expect := range []gqlscan.Token{
+		gqlscan.TokenDefQry,
+		gqlscan.TokenSet,
+		gqlscan.TokenField,
+		gqlscan.TokenSetEnd,
+		gqlscan.TokenDefEnd,
+	} {
+		tok, err := l.Next()
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+		require.Equal(t, expect, tok)
+	}
+
+	for {
+		tok, err := l.Next()
+		if tok != 0 {
+			continue
+		}
+		require.Equal(t,
+			"error at index 7 ('}'): unexpected token; expected argument name",
+			err.Error())
+		require.Equal(t, 1, err.DefinitionIndex)
+		require.Equal(t, 9, err.TokenOrdinal)
+		break
+	}
+}
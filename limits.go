@@ -0,0 +1,450 @@
+package gqlscan
+
+import (
+	"strconv"
+	"time"
+)
+
+// Limits defines the limits enforced by ScanWithOptions
+// and checked by Inspect. A limit is disabled if it's < 1.
+type Limits struct {
+	// MaxDepth limits the nesting depth of selection sets
+	// and MaxValueDepth of list and object values.
+	MaxDepth      int
+	MaxValueDepth int
+
+	MaxTokens          int
+	MaxOperations      int
+	MaxFragments       int
+	MaxFragmentSpreads int
+	MaxAliases         int
+	MaxRootFields      int
+
+	// MaxArguments limits the number of arguments of a single
+	// argument list and MaxDirectives the number of directives
+	// of a single location.
+	MaxArguments  int
+	MaxDirectives int
+
+	// MaxNameLength limits the length of names and
+	// MaxStringValueBytes the raw length of string values in bytes.
+	MaxNameLength       int
+	MaxStringValueBytes int
+
+	// MaxInputBytes limits the size of the document.
+	MaxInputBytes int
+
+	// MaxComplexity limits the score computed with
+	// the options Complexity, see Complexity.
+	MaxComplexity int
+	Complexity    ComplexityOptions
+
+	// MaxTypenames limits the number of __typename selections
+	// of the document and MaxSetTypenames the number
+	// of a single selection set.
+	MaxTypenames    int
+	MaxSetTypenames int
+
+	// MaxDuration limits the duration of the scan,
+	// which is checked every 256 tokens.
+	MaxDuration time.Duration
+}
+
+// enabled returns true if any limit is enabled.
+func (l *Limits) enabled() bool {
+	return l.MaxDepth > 0 || l.MaxValueDepth > 0 ||
+		l.MaxTokens > 0 || l.MaxOperations > 0 ||
+		l.MaxFragments > 0 || l.MaxFragmentSpreads > 0 ||
+		l.MaxAliases > 0 || l.MaxRootFields > 0 ||
+		l.MaxArguments > 0 || l.MaxDirectives > 0 ||
+		l.MaxNameLength > 0 || l.MaxStringValueBytes > 0 ||
+		l.MaxInputBytes > 0 || l.MaxComplexity > 0 ||
+		l.MaxTypenames > 0 || l.MaxSetTypenames > 0 ||
+		l.MaxDuration > 0
+}
+
+// LimitError describes an exceeded limit and is held by
+// the field Err of errors returned for exceeded limits.
+type LimitError struct {
+	// Limit is the configured limit and Actual the value
+	// that exceeded it. Durations are given in nanoseconds.
+	Limit, Actual int
+}
+
+func (e *LimitError) Error() string {
+	return "got " + strconv.Itoa(e.Actual) + ", limit " + strconv.Itoa(e.Limit)
+}
+
+// WithMaxSelectionDepth makes the scan fail with ErrSelTooDeep
+// at the selection set nested deeper than max levels.
+// There's no limit if max < 1.
+func WithMaxSelectionDepth(max int) Option {
+	return func(o *options) { o.limits.MaxDepth = positive(max) }
+}
+
+// WithMaxTokens makes the scan fail with ErrTooManyTokens
+// at the token following the first max tokens.
+// There's no limit if max < 1.
+func WithMaxTokens(max int) Option {
+	return func(o *options) { o.limits.MaxTokens = positive(max) }
+}
+
+// WithMaxValueNesting makes the scan fail with ErrValTooDeep
+// at the list or input object nested deeper than max levels
+// in an argument or default value.
+// There's no limit if max < 1.
+func WithMaxValueNesting(max int) Option {
+	return func(o *options) { o.limits.MaxValueDepth = positive(max) }
+}
+
+// WithMaxInputBytes makes the scan fail with ErrDocTooLarge
+// at index max before scanning if the document is longer
+// than max bytes.
+// There's no limit if max < 1.
+func WithMaxInputBytes(max int) Option {
+	return func(o *options) { o.limits.MaxInputBytes = positive(max) }
+}
+
+// WithMaxAliases makes the scan fail with ErrTooManyAliases
+// at the field alias following the first max aliases of the document.
+// There's no limit if max < 1.
+func WithMaxAliases(max int) Option {
+	return func(o *options) { o.limits.MaxAliases = positive(max) }
+}
+
+// WithMaxRootFields makes the scan fail with ErrTooManyRootFields
+// at the selection following the first max selections of the root
+// selection set of an operation, which includes fields,
+// fragment spreads and inline fragments.
+// There's no limit if max < 1.
+func WithMaxRootFields(max int) Option {
+	return func(o *options) { o.limits.MaxRootFields = positive(max) }
+}
+
+// WithMaxFragments makes the scan fail with ErrTooManyFrags
+// at the fragment definition following the first max
+// fragment definitions of the document.
+// There's no limit if max < 1.
+func WithMaxFragments(max int) Option {
+	return func(o *options) { o.limits.MaxFragments = positive(max) }
+}
+
+// WithMaxFragmentSpreads makes the scan fail with ErrTooManySpreads
+// at the fragment spread following the first max fragment spreads
+// of the document. Inline fragments aren't counted.
+// There's no limit if max < 1.
+func WithMaxFragmentSpreads(max int) Option {
+	return func(o *options) { o.limits.MaxFragmentSpreads = positive(max) }
+}
+
+// WithMaxNameLength makes the scan fail with ErrNameTooLong
+// at names longer than max bytes.
+// There's no limit if max < 1.
+func WithMaxNameLength(max int) Option {
+	return func(o *options) { o.limits.MaxNameLength = positive(max) }
+}
+
+// WithMaxStringValueBytes makes the scan fail with ErrStrTooLong
+// at string and block string values whose raw value
+// is longer than max bytes.
+// There's no limit if max < 1.
+func WithMaxStringValueBytes(max int) Option {
+	return func(o *options) { o.limits.MaxStringValueBytes = positive(max) }
+}
+
+// WithMaxOperations makes the scan fail with ErrTooManyOprs
+// at the operation definition following the first max operation
+// definitions of the document. Fragment definitions aren't counted.
+// There's no limit if max < 1.
+func WithMaxOperations(max int) Option {
+	return func(o *options) { o.limits.MaxOperations = positive(max) }
+}
+
+// WithMaxArguments makes the scan fail with ErrTooManyArgs
+// at the argument following the first max arguments of any argument
+// list of a field or directive. There's no limit if max < 1.
+func WithMaxArguments(max int) Option {
+	return func(o *options) { o.limits.MaxArguments = positive(max) }
+}
+
+// WithMaxDirectives makes the scan fail with ErrTooManyDirs
+// at the directive following the first max directives attached to
+// a single location such as an operation, field, fragment or
+// variable definition. There's no limit if max < 1.
+func WithMaxDirectives(max int) Option {
+	return func(o *options) { o.limits.MaxDirectives = positive(max) }
+}
+
+// WithMaxTypenames makes the scan fail with ErrTooManyTypenames
+// at the __typename field following the first max __typename fields
+// of the document. There's no limit if max < 1.
+func WithMaxTypenames(max int) Option {
+	return func(o *options) { o.limits.MaxTypenames = positive(max) }
+}
+
+// WithMaxTypenamesPerSelectionSet makes the scan fail with
+// ErrTooManyTypenames at the __typename field following the first max
+// __typename fields of a selection set. There's no limit if max < 1.
+func WithMaxTypenamesPerSelectionSet(max int) Option {
+	return func(o *options) { o.limits.MaxSetTypenames = positive(max) }
+}
+
+// WithMaxDuration makes the scan fail with ErrTimeout at the token
+// at which the scan is found to take longer than max. The duration is
+// checked every 256 tokens, hence the scan may exceed max slightly.
+// There's no limit if max < 1.
+func WithMaxDuration(max time.Duration) Option {
+	return func(o *options) {
+		if o.limits.MaxDuration = max; max < 0 {
+			o.limits.MaxDuration = 0
+		}
+	}
+}
+
+// WithLimits makes the scan enforce limits like the options
+// setting the individual limits and fail with ErrTooComplex at the
+// token the score of the document exceeds limits.MaxComplexity at.
+// It replaces all limits set by preceding options.
+func WithLimits(limits Limits) Option {
+	return func(o *options) { o.limits = limits }
+}
+
+// checkSize returns an error if str exceeds the size limit
+// after index offset.
+func (l *Limits) checkSize(str []byte, offset int) Error {
+	if n := len(str) - offset; n > l.MaxInputBytes && l.MaxInputBytes > 0 {
+		e := errorAt(str, offset+l.MaxInputBytes, ErrDocTooLarge)
+		e.Err = &LimitError{Limit: l.MaxInputBytes, Actual: n}
+		return e
+	}
+	return Error{}
+}
+
+// durationCheckInterval is the number of tokens
+// between checks of the duration limit.
+const durationCheckInterval = 256
+
+// limiter enforces limits and records the report of the document.
+type limiter struct {
+	Limits
+
+	// inspect makes the limiter record the whole report
+	// including the complexity, introspection and
+	// __typename selections of selection sets.
+	inspect bool
+	report  Report
+
+	// code is the code of the exceeded limit,
+	// index the index it was exceeded at and err describes it.
+	code  ErrorCode
+	index int
+	err   LimitError
+
+	selDepth   int
+	valDepth   int
+	rootFields int
+	args       int
+	dirs       int
+	// setTypenames holds the numbers of __typename selections
+	// of the open selection sets if they're recorded.
+	setTypenames []int
+	// complexity is nil if the complexity isn't recorded.
+	complexity *complexity
+	// start is the time the scan started at if its duration is limited.
+	start time.Time
+}
+
+// newLimiter returns a limiter enforcing limits.
+func newLimiter(limits Limits, inspect bool) *limiter {
+	l := &limiter{Limits: limits, inspect: inspect}
+	if l.MaxComplexity > 0 || inspect {
+		l.complexity = newComplexity(l.Complexity)
+	}
+	if l.MaxDuration > 0 {
+		l.start = time.Now()
+	}
+	return l
+}
+
+// check returns true if the current token of i exceeds the limits.
+func (l *limiter) check(i *Iterator) (err bool) {
+	r := &l.report
+	if r.Tokens++; r.Tokens > l.MaxTokens && l.MaxTokens > 0 {
+		return l.exceed(i, ErrTooManyTokens, l.MaxTokens, r.Tokens)
+	}
+	if l.MaxDuration > 0 && r.Tokens%durationCheckInterval == 0 {
+		if d := time.Since(l.start); d > l.MaxDuration {
+			return l.exceed(i, ErrTimeout, int(l.MaxDuration), int(d))
+		}
+	}
+	switch i.token {
+	case TokenDefQry, TokenDefMut, TokenDefSub:
+		if r.Operations++; r.Operations > l.MaxOperations && l.MaxOperations > 0 {
+			return l.exceed(i, ErrTooManyOprs, l.MaxOperations, r.Operations)
+		}
+		l.rootFields = 0
+	case TokenDefFrag:
+		if r.Fragments++; r.Fragments > l.MaxFragments && l.MaxFragments > 0 {
+			return l.exceed(i, ErrTooManyFrags, l.MaxFragments, r.Fragments)
+		}
+	case TokenSet:
+		if l.selDepth++; l.selDepth > r.Depth {
+			r.Depth = l.selDepth
+		}
+		if l.selDepth > l.MaxDepth && l.MaxDepth > 0 {
+			return l.exceed(i, ErrSelTooDeep, l.MaxDepth, l.selDepth)
+		}
+	case TokenSetEnd:
+		l.selDepth--
+	case TokenArr, TokenObj:
+		if l.valDepth++; l.valDepth > r.ValueDepth {
+			r.ValueDepth = l.valDepth
+		}
+		if l.valDepth > l.MaxValueDepth && l.MaxValueDepth > 0 {
+			return l.exceed(i, ErrValTooDeep, l.MaxValueDepth, l.valDepth)
+		}
+	case TokenArrEnd, TokenObjEnd:
+		l.valDepth--
+	case TokenFieldAlias:
+		if r.Aliases++; r.Aliases > l.MaxAliases && l.MaxAliases > 0 {
+			return l.exceed(i, ErrTooManyAliases, l.MaxAliases, r.Aliases)
+		}
+	case TokenArgList:
+		l.args = 0
+	case TokenArgName:
+		if l.args++; l.args > r.Arguments {
+			r.Arguments = l.args
+		}
+		if l.args > l.MaxArguments && l.MaxArguments > 0 {
+			return l.exceed(i, ErrTooManyArgs, l.MaxArguments, l.args)
+		}
+	case TokenNamedSpread:
+		r.FragmentSpreads++
+		if r.FragmentSpreads > l.MaxFragmentSpreads && l.MaxFragmentSpreads > 0 {
+			return l.exceed(i, ErrTooManySpreads,
+				l.MaxFragmentSpreads, r.FragmentSpreads)
+		}
+	}
+
+	switch i.token {
+	case TokenStr, TokenStrBlock:
+		n := len(i.Value())
+		if n > r.StringValueBytes {
+			r.StringValueBytes = n
+		}
+		if n > l.MaxStringValueBytes && l.MaxStringValueBytes > 0 {
+			return l.exceed(i, ErrStrTooLong, l.MaxStringValueBytes, n)
+		}
+	case TokenOprName, TokenDirName, TokenFragTypeCond, TokenFragName,
+		TokenFragInline, TokenNamedSpread, TokenFieldAlias, TokenField,
+		TokenArgName, TokenEnumVal, TokenVarName, TokenVarTypeName,
+		TokenVarRef, TokenObjField:
+		n := len(i.Value())
+		if n > r.NameLength {
+			r.NameLength = n
+		}
+		if n > l.MaxNameLength && l.MaxNameLength > 0 {
+			return l.exceed(i, ErrNameTooLong, l.MaxNameLength, n)
+		}
+	}
+
+	// __typename selections
+	recordSets := l.MaxSetTypenames > 0 || l.inspect
+	switch i.token {
+	case TokenSet:
+		if recordSets {
+			l.setTypenames = append(l.setTypenames, 0)
+		}
+	case TokenSetEnd:
+		if recordSets {
+			l.setTypenames = l.setTypenames[:len(l.setTypenames)-1]
+		}
+	case TokenField:
+		switch string(i.Value()) {
+		case "__schema", "__type":
+			r.Introspection = true
+		case "__typename":
+			if r.Typenames++; r.Typenames > l.MaxTypenames && l.MaxTypenames > 0 {
+				return l.exceed(i, ErrTooManyTypenames,
+					l.MaxTypenames, r.Typenames)
+			}
+			if !recordSets {
+				break
+			}
+			n := &l.setTypenames[len(l.setTypenames)-1]
+			if *n++; *n > r.SetTypenames {
+				r.SetTypenames = *n
+			}
+			if *n > l.MaxSetTypenames && l.MaxSetTypenames > 0 {
+				return l.exceed(i, ErrTooManyTypenames, l.MaxSetTypenames, *n)
+			}
+		}
+	}
+
+	// Directives of a location
+	switch i.token {
+	case TokenDirName:
+		if l.dirs++; l.dirs > r.Directives {
+			r.Directives = l.dirs
+		}
+		if l.dirs > l.MaxDirectives && l.MaxDirectives > 0 {
+			return l.exceed(i, ErrTooManyDirs, l.MaxDirectives, l.dirs)
+		}
+	case TokenDefQry, TokenDefMut, TokenDefSub, TokenDefFrag,
+		TokenVarName, TokenVarListEnd, TokenField,
+		TokenNamedSpread, TokenFragInline:
+		l.dirs = 0
+	}
+
+	// Root selections of operations
+	switch i.token {
+	case TokenField, TokenNamedSpread, TokenFragInline:
+		if l.selDepth != 1 || i.def == TokenDefFrag {
+			break
+		}
+		if l.rootFields++; l.rootFields > r.RootFields {
+			r.RootFields = l.rootFields
+		}
+		if l.rootFields > l.MaxRootFields && l.MaxRootFields > 0 {
+			return l.exceed(i, ErrTooManyRootFields,
+				l.MaxRootFields, l.rootFields)
+		}
+	}
+
+	if c := l.complexity; c != nil {
+		c.token(i)
+		if r.Complexity = c.score; c.score > l.MaxComplexity &&
+			l.MaxComplexity > 0 {
+			return l.exceed(i, ErrTooComplex, l.MaxComplexity, c.score)
+		}
+	}
+	return false
+}
+
+// exceed records that limit was exceeded by actual
+// at the current token.
+func (l *limiter) exceed(
+	i *Iterator,
+	code ErrorCode,
+	limit, actual int,
+) (err bool) {
+	l.code, l.index = code, i.index()
+	l.err = LimitError{Limit: limit, Actual: actual}
+	return true
+}
+
+// error returns the error of the exceeded limit
+// based on err returned by the scan of str.
+func (l *limiter) error(str []byte, err Error) Error {
+	e := errorAt(str, l.index, l.code)
+	e.DefinitionIndex, e.TokenOrdinal = err.DefinitionIndex, err.TokenOrdinal
+	e.Trail, e.Err = err.Trail, &l.err
+	return e
+}
+
+func positive(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
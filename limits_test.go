@@ -0,0 +1,336 @@
+package gqlscan_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		option gqlscan.Option
+		expect string
+	}{
+		{decl(1), `{a{b{c}}}`, gqlscan.WithMaxSelectionDepth(3), ""},
+		{decl(1), `{a{b{c}}} {d}`, gqlscan.WithMaxSelectionDepth(0), ""},
+		{decl(1), `{a{b{c}}}`, gqlscan.WithMaxSelectionDepth(-1), ""},
+		{decl(1), `{a{b{c}}}`, gqlscan.WithMaxSelectionDepth(2),
+			"error at index 4 ('{'): selection set nesting limit exceeded: " +
+				"got 3, limit 2"},
+		{decl(1), `{a{b} c{d} e{f{g}}}`, gqlscan.WithMaxSelectionDepth(2),
+			"error at index 14 ('{'): selection set nesting limit exceeded: " +
+				"got 3, limit 2"},
+		{decl(1), `fragment F on T {a{b}}`, gqlscan.WithMaxSelectionDepth(1),
+			"error at index 18 ('{'): selection set nesting limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `{...on T{a}}`, gqlscan.WithMaxSelectionDepth(1),
+			"error at index 8 ('{'): selection set nesting limit exceeded: " +
+				"got 2, limit 1"},
+
+		{decl(1), `{a b}`, gqlscan.WithMaxTokens(6), ""},
+		{decl(1), `{a b}`, gqlscan.WithMaxTokens(0), ""},
+		{decl(1), `{a b}`, gqlscan.WithMaxTokens(5),
+			"error at index 4 ('}'): token limit exceeded: " +
+				"got 6, limit 5"},
+		{decl(1), `{a(b:"c")}`, gqlscan.WithMaxTokens(5),
+			"error at index 6 ('c'): token limit exceeded: " +
+				"got 6, limit 5"},
+		{decl(1), `{a} {b}`, gqlscan.WithMaxTokens(5),
+			"error at index 4 ('{'): token limit exceeded: " +
+				"got 6, limit 5"},
+
+		{decl(1), `{a(b:[{c:[1]}])}`, gqlscan.WithMaxValueNesting(3), ""},
+		{decl(1), `{a(b:[[[[1]]]])}`, gqlscan.WithMaxValueNesting(0), ""},
+		{decl(1), `{a(b:[{c:[1]}])}`, gqlscan.WithMaxValueNesting(2),
+			"error at index 9 ('['): value nesting limit exceeded: " +
+				"got 3, limit 2"},
+		{decl(1), `{a(b:[[1] [2] [[3]]])}`, gqlscan.WithMaxValueNesting(2),
+			"error at index 15 ('['): value nesting limit exceeded: " +
+				"got 3, limit 2"},
+		{decl(1), `{a(b:{c:{d:1}})}`, gqlscan.WithMaxValueNesting(1),
+			"error at index 8 ('{'): value nesting limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `query($v:[[Int]]=[[1]]) {a}`, gqlscan.WithMaxValueNesting(1),
+			"error at index 18 ('['): value nesting limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `{a @d(b:[[1]])}`, gqlscan.WithMaxValueNesting(1),
+			"error at index 9 ('['): value nesting limit exceeded: " +
+				"got 2, limit 1"},
+
+		{decl(1), `{a}`, gqlscan.WithMaxInputBytes(3), ""},
+		{decl(1), `{a}`, gqlscan.WithMaxInputBytes(0), ""},
+		{decl(1), `{ab}`, gqlscan.WithMaxInputBytes(3),
+			"error at index 3 ('}'): document size limit exceeded: " +
+				"got 4, limit 3"},
+		{decl(1), `{a(}`, gqlscan.WithMaxInputBytes(3),
+			"error at index 3 ('}'): document size limit exceeded: " +
+				"got 4, limit 3"},
+
+		{decl(1), `{a:b c:d}`, gqlscan.WithMaxAliases(2), ""},
+		{decl(1), `{a:b c:d}`, gqlscan.WithMaxAliases(0), ""},
+		{decl(1), `{a:b c:d}`, gqlscan.WithMaxAliases(1),
+			"error at index 5 ('c'): alias limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `{a:b} query {c:d}`, gqlscan.WithMaxAliases(1),
+			"error at index 13 ('c'): alias limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `{a:b{c:d}}`, gqlscan.WithMaxAliases(1),
+			"error at index 5 ('c'): alias limit exceeded: " +
+				"got 2, limit 1"},
+
+		{decl(1), `{a b{c d e}}`, gqlscan.WithMaxRootFields(2), ""},
+		{decl(1), `{a b c}`, gqlscan.WithMaxRootFields(0), ""},
+		{decl(1), `{a b} {c d}`, gqlscan.WithMaxRootFields(2), ""},
+		{decl(1), `fragment F on T {a b c} {d ...F}`,
+			gqlscan.WithMaxRootFields(2), ""},
+		{decl(1), `{a b:c d}`, gqlscan.WithMaxRootFields(2),
+			"error at index 7 ('d'): root field limit exceeded: " +
+				"got 3, limit 2"},
+		{decl(1), `mutation {a ...F}`, gqlscan.WithMaxRootFields(1),
+			"error at index 15 ('F'): root field limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `subscription {a ...on T {b}}`, gqlscan.WithMaxRootFields(1),
+			"error at index 22 ('T'): root field limit exceeded: " +
+				"got 2, limit 1"},
+
+		{decl(1), `fragment A on T {a} fragment B on T {b} {...A ...B}`,
+			gqlscan.WithMaxFragments(2), ""},
+		{decl(1), `fragment A on T {a} fragment B on T {b}`,
+			gqlscan.WithMaxFragments(1),
+			"error at index 20 ('f'): fragment definition limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `{...A ...on T {a} b {...A}}`,
+			gqlscan.WithMaxFragmentSpreads(2), ""},
+		{decl(1), `{...A b {...A} ...B}`, gqlscan.WithMaxFragmentSpreads(2),
+			"error at index 18 ('B'): fragment spread limit exceeded: " +
+				"got 3, limit 2"},
+
+		{decl(1), `query abc($abc:abc) @abc {abc:abc(abc:abc) ...abc}`,
+			gqlscan.WithMaxNameLength(3), ""},
+		{decl(1), `{abcd}`, gqlscan.WithMaxNameLength(0), ""},
+		{decl(1), `{abc abcd}`, gqlscan.WithMaxNameLength(3),
+			"error at index 5 ('a'): name length limit exceeded: " +
+				"got 4, limit 3"},
+		{decl(1), `{a(b:{cdef:1})}`, gqlscan.WithMaxNameLength(3),
+			"error at index 6 ('c'): name length limit exceeded: " +
+				"got 4, limit 3"},
+		{decl(1), `query($a:Abcd){a}`, gqlscan.WithMaxNameLength(3),
+			"error at index 9 ('A'): name length limit exceeded: " +
+				"got 4, limit 3"},
+		{decl(1), `{...on Abcd{a}}`, gqlscan.WithMaxNameLength(3),
+			"error at index 7 ('A'): name length limit exceeded: " +
+				"got 4, limit 3"},
+		{decl(1), `{a(b:"abc" c:"""abc""")}`,
+			gqlscan.WithMaxStringValueBytes(3), ""},
+		{decl(1), `{a(b:"abcd")}`, gqlscan.WithMaxStringValueBytes(0), ""},
+		{decl(1), `{a(b:"abc" c:"a\nc")}`, gqlscan.WithMaxStringValueBytes(3),
+			"error at index 14 ('a'): string value length limit exceeded: " +
+				"got 4, limit 3"},
+		{decl(1), `{a(b:"""abcd""")}`, gqlscan.WithMaxStringValueBytes(3),
+			"error at index 8 ('a'): string value length limit exceeded: " +
+				"got 4, limit 3"},
+
+		{decl(1), `{a} fragment F on T {b}`, gqlscan.WithMaxOperations(1), ""},
+		{decl(1), `{a} {b} {c}`, gqlscan.WithMaxOperations(0), ""},
+		{decl(1), `{a} fragment F on T {b} mutation {c}`,
+			gqlscan.WithMaxOperations(1),
+			"error at index 24 ('m'): operation limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `query A {a} subscription B {b} {c}`,
+			gqlscan.WithMaxOperations(2),
+			"error at index 31 ('{'): operation limit exceeded: " +
+				"got 3, limit 2"},
+
+		{decl(1), `{a(x:1 y:2) b(x:1 y:2) @d(x:1 y:2)}`,
+			gqlscan.WithMaxArguments(2), ""},
+		{decl(1), `{a(x:1 y:2 z:3)}`, gqlscan.WithMaxArguments(0), ""},
+		{decl(1), `{a(x:1 y:{a:1 b:2 c:3}) b(x:1 y:2 z:3)}`,
+			gqlscan.WithMaxArguments(2),
+			"error at index 34 ('z'): argument limit exceeded: " +
+				"got 3, limit 2"},
+		{decl(1), `{a @d(x:1 y:2)}`,
+			gqlscan.WithMaxArguments(1),
+			"error at index 10 ('y'): argument limit exceeded: " +
+				"got 2, limit 1"},
+
+		{decl(1), "query Q($v: Int @a @b) @a @b {" +
+			"a @a @b(x:1) b @a @b ...F @a @b ... @a @b {c}" +
+			"} fragment F on T @a @b {d}",
+			gqlscan.WithMaxDirectives(2), ""},
+		{decl(1), `{a @a @b @c}`, gqlscan.WithMaxDirectives(0), ""},
+		{decl(1), `{a @a @b b @a(x:1) @b @c}`,
+			gqlscan.WithMaxDirectives(2),
+			"error at index 23 ('c'): directive limit exceeded: " +
+				"got 3, limit 2"},
+		{decl(1), `query ($v: Int @a) @a @b {a}`,
+			gqlscan.WithMaxDirectives(1),
+			"error at index 23 ('b'): directive limit exceeded: " +
+				"got 2, limit 1"},
+
+		{decl(1), `{__typename a {__typename} ... on T {__typename}}`,
+			gqlscan.WithMaxTypenames(3), ""},
+		{decl(1), `{__typename __typename}`, gqlscan.WithMaxTypenames(0), ""},
+		{decl(1), `{__typename a {__typename} b {__typename}}`,
+			gqlscan.WithMaxTypenames(2),
+			"error at index 30 ('_'): __typename limit exceeded: " +
+				"got 3, limit 2"},
+		{decl(1), `{__typename a {__typename __typename} __typename}`,
+			gqlscan.WithMaxTypenamesPerSelectionSet(2), ""},
+		{decl(1), `{__typename a {__typename} __typename __typename}`,
+			gqlscan.WithMaxTypenamesPerSelectionSet(2),
+			"error at index 38 ('_'): __typename limit exceeded: " +
+				"got 3, limit 2"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanWithOptions(
+				[]byte(td.input),
+				func(*gqlscan.Iterator) bool { return false },
+				td.option,
+			)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
+
+func TestLimitsErrorDetails(t *testing.T) {
+	src := []byte("query Q {\n  a{b{c}}\n}")
+	var tokens int
+	err := gqlscan.ScanWithOptions(
+		src,
+		func(*gqlscan.Iterator) bool { tokens++; return false },
+		gqlscan.WithMaxSelectionDepth(2),
+	)
+	require.Equal(t, gqlscan.ErrSelTooDeep, err.Code)
+	require.Equal(t, 15, err.Index)
+	line, column := err.Position(src)
+	require.Equal(t, 2, line)
+	require.Equal(t, 6, column)
+	require.Equal(t, 0, err.DefinitionIndex)
+	require.Equal(t, tokens, err.TokenOrdinal)
+	require.Equal(t, gqlscan.Expect(0), err.Expectation)
+	require.Equal(t, &gqlscan.LimitError{Limit: 2, Actual: 3}, err.Err)
+}
+
+func TestLimitsCallbackFn(t *testing.T) {
+	err := gqlscan.ScanWithOptions(
+		[]byte(`{a{b}}`),
+		func(*gqlscan.Iterator) bool { return true },
+		gqlscan.WithMaxSelectionDepth(1),
+	)
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+}
+
+func TestLimitsSkip(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		option gqlscan.Option
+		expect string
+	}{
+		{decl(1), `{a{b{c{d{e{f{g}}}}}}}`, gqlscan.WithMaxSelectionDepth(3),
+			"error at index 6 ('{'): selection set nesting limit exceeded: " +
+				"got 4, limit 3"},
+		{decl(1), `{a{b c d}}`, gqlscan.WithMaxTokens(5),
+			"error at index 5 ('c'): token limit exceeded: " +
+				"got 6, limit 5"},
+		{decl(1), `{a{x:b y:c}}`, gqlscan.WithMaxAliases(1),
+			"error at index 7 ('y'): alias limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `{a{b(c:[[1]])}}`, gqlscan.WithMaxValueNesting(1),
+			"error at index 8 ('['): value nesting limit exceeded: " +
+				"got 2, limit 1"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var skipped []gqlscan.Token
+			err := gqlscan.ScanWithOptions(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) bool {
+					skipped = append(skipped, i.Token())
+					i.SkipSelectionSet()
+					return false
+				},
+				td.option,
+			)
+			require.Equal(t, td.expect, err.Error())
+			// Only the tokens preceding the skipped
+			// selection set must reach fn.
+			require.Equal(t, []gqlscan.Token{
+				gqlscan.TokenDefQry, gqlscan.TokenSet,
+			}, skipped)
+		})
+	}
+}
+
+func TestWithLimits(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	err := gqlscan.ScanWithOptions([]byte(`{a b{c}}`), noop,
+		gqlscan.WithMaxTokens(1),
+		gqlscan.WithLimits(gqlscan.Limits{
+			MaxDepth:      2,
+			MaxComplexity: 2,
+		}),
+	)
+	require.Equal(t, "error at index 5 ('c'): complexity limit exceeded: "+
+		"got 3, limit 2", err.Error())
+
+	err = gqlscan.ScanWithOptions([]byte(`{a{b{c}}}`), noop,
+		gqlscan.WithLimits(gqlscan.Limits{MaxDepth: 2}),
+	)
+	require.Equal(t, gqlscan.ErrSelTooDeep, err.Code)
+}
+
+func TestLimitsMaxInputBytes(t *testing.T) {
+	called := false
+	err := gqlscan.ScanWithOptions(
+		[]byte("prefix {a}"),
+		func(*gqlscan.Iterator) bool { called = true; return false },
+		gqlscan.WithOffset(7),
+		gqlscan.WithMaxInputBytes(3),
+	)
+	require.False(t, err.IsErr())
+	require.True(t, called)
+
+	called = false
+	src := []byte("{\n\tab}")
+	err = gqlscan.ScanWithOptions(
+		src,
+		func(*gqlscan.Iterator) bool { called = true; return false },
+		gqlscan.WithMaxInputBytes(3),
+		gqlscan.WithUTF8Validation(),
+	)
+	require.False(t, called)
+	require.Equal(t, gqlscan.ErrDocTooLarge, err.Code)
+	line, column := err.Position(src)
+	require.Equal(t, 2, line)
+	require.Equal(t, 2, column)
+	var limitErr *gqlscan.LimitError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, gqlscan.LimitError{Limit: 3, Actual: 6}, *limitErr)
+}
+
+func TestLimitsMaxDuration(t *testing.T) {
+	src := []byte("{" + strings.Repeat("a ", 300) + "}")
+	noop := func(*gqlscan.Iterator) bool { return false }
+
+	err := gqlscan.ScanWithOptions(src, noop, gqlscan.WithMaxDuration(time.Hour))
+	require.False(t, err.IsErr())
+	err = gqlscan.ScanWithOptions(src, noop, gqlscan.WithMaxDuration(-1))
+	require.False(t, err.IsErr())
+
+	// The duration is first checked at the 256th token.
+	err = gqlscan.ScanWithOptions(src, noop,
+		gqlscan.WithMaxDuration(time.Nanosecond))
+	require.Equal(t, gqlscan.ErrTimeout, err.Code)
+	require.Equal(t, 1+253*2, err.Index)
+	require.Equal(t, 255, err.TokenOrdinal)
+	var limitErr *gqlscan.LimitError
+	require.ErrorAs(t, err, &limitErr)
+	require.Equal(t, int(time.Nanosecond), limitErr.Limit)
+	require.Greater(t, limitErr.Actual, limitErr.Limit)
+}
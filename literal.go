@@ -0,0 +1,103 @@
+package gqlscan
+
+// literal is the kind of a string, block string or comment.
+// Literals are skipped by the code locating definitions and
+// comments in a document without scanning it.
+type literal uint8
+
+const (
+	_ literal = iota
+	literalStr
+	literalBlockStr
+	literalComment
+)
+
+// openLiteral returns the kind of the literal beginning at index x
+// of str and the index following its opening quotes or number sign.
+// Returns 0 and x if there's no literal at x.
+// more is true if the kind can't be determined before
+// more bytes are appended to str.
+func openLiteral(str []byte, x int) (k literal, body int, more bool) {
+	switch str[x] {
+	case '#':
+		return literalComment, x + 1, false
+	case '"':
+		if x+3 > len(str) {
+			return 0, x, true
+		}
+		if string(str[x:x+3]) == `"""` {
+			return literalBlockStr, x + 3, false
+		}
+		return literalStr, x + 1, false
+	}
+	return 0, x, false
+}
+
+// end returns the index following the literal of kind k
+// the body of which continues at index x of str.
+// A comment and an unterminated string end before the line terminator.
+// If str ends before the literal or before a byte that can't be
+// classified before the following bytes are appended to str then
+// ok is false and end is the index to continue at.
+func (k literal) end(str []byte, x int) (end int, ok bool) {
+	switch k {
+	case literalComment:
+		for ; x < len(str); x++ {
+			if str[x] == '\n' || str[x] == '\r' {
+				return x, true
+			}
+		}
+	case literalStr:
+		for ; x < len(str); x++ {
+			switch str[x] {
+			case '\\':
+				if x+1 >= len(str) {
+					return x, false
+				}
+				x++
+			case '"':
+				return x + 1, true
+			case '\n', '\r':
+				return x, true
+			}
+		}
+	case literalBlockStr:
+		for ; x < len(str); x++ {
+			switch str[x] {
+			case '\\':
+				if x+4 > len(str) {
+					return x, false
+				}
+				if string(str[x+1:x+4]) == `"""` {
+					x += 3
+				}
+			case '"':
+				if x+3 > len(str) {
+					return x, false
+				}
+				if string(str[x:x+3]) == `"""` {
+					return x + 3, true
+				}
+			}
+		}
+	}
+	return x, false
+}
+
+// skipLiteral returns the index following the literal beginning
+// at index x of the complete document str, or x if there's none.
+// An unterminated literal extends to the end of str.
+func skipLiteral(str []byte, x int) int {
+	k, body, more := openLiteral(str, x)
+	if more {
+		k, body = literalStr, x+1
+	}
+	if k == 0 {
+		return x
+	}
+	end, ok := k.end(str, body)
+	if !ok {
+		return len(str)
+	}
+	return end
+}
@@ -0,0 +1,43 @@
+package gqlscan
+
+// ScanLoneAnonymousOperation is similar to Scan but returns an error
+// with code ErrAnonOprNotAlone at the index of the first anonymous
+// operation if the document contains other operations along with it.
+// fn isn't called for the token at which the violation is detected.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanLoneAnonymousOperation returns!
+func ScanLoneAnonymousOperation(
+	str []byte,
+	fn func(*Iterator) (err bool),
+) Error {
+	var (
+		operations int
+		// anonymous is the index of the first anonymous operation.
+		anonymous = -1
+		// last is the index of the last operation definition
+		// until its name is scanned.
+		last = -1
+	)
+	err := Scan(str, func(i *Iterator) bool {
+		if last > -1 {
+			if i.token != TokenOprName && anonymous < 0 {
+				anonymous = last
+			}
+			last = -1
+		}
+		switch i.token {
+		case TokenDefQry, TokenDefMut, TokenDefSub:
+			operations++
+			last = i.defHead
+		}
+		if operations > 1 && anonymous > -1 {
+			return true
+		}
+		return fn(i)
+	})
+	if operations > 1 && anonymous > -1 {
+		return errorAt(str, anonymous, ErrAnonOprNotAlone)
+	}
+	return err
+}
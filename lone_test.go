@@ -0,0 +1,43 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanLoneAnonymousOperation(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `{a}`, ""},
+		{decl(1), `query {a} fragment F on T {b}`, ""},
+		{decl(1), `query A {a} mutation B {b} subscription C {c}`, ""},
+		{decl(1), `{a} {b}`,
+			"error at index 0 ('{'): anonymous operation not alone"},
+		{decl(1), `query A {a} {b}`,
+			"error at index 12 ('{'): anonymous operation not alone"},
+		{decl(1), `fragment F on T {a} mutation {b} query A {c}`,
+			"error at index 20 ('m'): anonymous operation not alone"},
+		{decl(1), `query A {a} subscription ($v: Int) {b}`,
+			"error at index 12 ('s'): anonymous operation not alone"},
+		{decl(1), `{a} query A {`,
+			"error at index 0 ('{'): anonymous operation not alone"},
+		{decl(1), `query A {a} query {`,
+			"error at index 12 ('q'): anonymous operation not alone"},
+		{decl(1), `{a} fragment F on T {`,
+			"error at index 21: unexpected end of file; expected selection"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanLoneAnonymousOperation(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) bool { return false },
+			)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
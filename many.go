@@ -0,0 +1,87 @@
+package gqlscan
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ScanMany calls fn for every token of every document scanning
+// the documents in parallel using the given number of workers.
+// If workers < 1 then runtime.GOMAXPROCS(0) workers are used.
+// fn receives the index of the document the token belongs to and
+// may be nil if only the errors are of interest.
+// Returns the errors of all documents that failed to scan keyed by
+// the index of the document, or nil if all documents are valid.
+// The options are applied to the scan of every document
+// like by ScanWithOptions.
+//
+// WARNING: fn is called concurrently and must be safe for concurrent use,
+// so must be the observers, loggers and stores passed in opts.
+// *Iterator passed to fn should never be aliased and used after fn returns!
+func ScanMany(
+	documents [][]byte,
+	workers int,
+	fn func(document int, i *Iterator),
+	opts ...Option,
+) map[int]Error {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(documents) {
+		workers = len(documents)
+	}
+
+	var (
+		next int64 = -1
+		lock sync.Mutex
+		errs map[int]Error
+		wg   sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				d := int(atomic.AddInt64(&next, 1))
+				if d >= len(documents) {
+					return
+				}
+				err := scanDocument(documents[d], d, fn, opts)
+				if !err.IsErr() {
+					continue
+				}
+				lock.Lock()
+				if errs == nil {
+					errs = make(map[int]Error)
+				}
+				errs[d] = err
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// scanDocument scans document d calling fn if it's not nil.
+func scanDocument(
+	str []byte,
+	d int,
+	fn func(document int, i *Iterator),
+	opts []Option,
+) Error {
+	if len(opts) < 1 {
+		return ScanAll(str, func(i *Iterator) {
+			if fn != nil {
+				fn(d, i)
+			}
+		})
+	}
+	return ScanWithOptions(str, func(i *Iterator) (err bool) {
+		if fn != nil {
+			fn(d, i)
+		}
+		return false
+	}, opts...)
+}
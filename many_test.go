@@ -0,0 +1,72 @@
+package gqlscan_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanMany(t *testing.T) {
+	var documents [][]byte
+	var expectTokens int64
+	expectErrs := map[int]string{}
+	for _, td := range testdata {
+		documents = append(documents, []byte(td.input))
+		expectTokens += int64(len(td.expect))
+	}
+	for _, td := range testdataErr {
+		expectErrs[len(documents)] = td.expectErr
+		documents = append(documents, []byte(td.input))
+	}
+
+	for _, workers := range []int{0, 1, 4, len(documents) + 1} {
+		var tokens int64
+		errs := gqlscan.ScanMany(
+			documents, workers,
+			func(document int, i *gqlscan.Iterator) {
+				if _, ok := expectErrs[document]; !ok {
+					atomic.AddInt64(&tokens, 1)
+				}
+			},
+		)
+		require.Equal(t, expectTokens, tokens, "workers: %d", workers)
+		require.Len(t, errs, len(expectErrs), "workers: %d", workers)
+		for d, e := range expectErrs {
+			require.Equal(t, e, errs[d].Error(), "document %d", d)
+		}
+	}
+}
+
+func TestScanManyValid(t *testing.T) {
+	errs := gqlscan.ScanMany([][]byte{[]byte(`{a}`), []byte(`{b}`)}, 2, nil)
+	require.Nil(t, errs)
+
+	require.Nil(t, gqlscan.ScanMany(nil, 2, nil))
+}
+
+func TestScanManyOptions(t *testing.T) {
+	documents := [][]byte{
+		[]byte(`{a{b}}`),
+		[]byte(`{a{b{c}}}`),
+		[]byte(`{a}`),
+		[]byte(`{a{b{c{d}}}}`),
+	}
+	for _, workers := range []int{1, 4} {
+		errs := gqlscan.ScanMany(
+			documents, workers, nil,
+			gqlscan.WithMaxSelectionDepth(2),
+		)
+		require.Len(t, errs, 2, "workers: %d", workers)
+		require.Equal(t,
+			"error at index 4 ('{'): selection set nesting limit exceeded: "+
+				"got 3, limit 2",
+			errs[1].Error(), "workers: %d", workers)
+		require.Equal(t,
+			"error at index 4 ('{'): selection set nesting limit exceeded: "+
+				"got 3, limit 2",
+			errs[3].Error(), "workers: %d", workers)
+	}
+}
@@ -0,0 +1,133 @@
+package gqlscan
+
+// MergeSelections returns a transform merging identical sibling
+// selections. Fields with identical alias, name, arguments and
+// directives as well as inline fragments with identical type conditions
+// and directives are merged combining their selection sets,
+// identical fragment spreads are removed.
+// MergeSelections buffers the selection set of each definition.
+func MergeSelections() func() Transform {
+	return func() Transform {
+		return &mergeSelections{
+			buf:   make([]TokenValue, 0, 256),
+			nodes: make([]mergeNode, 0, 64),
+		}
+	}
+}
+
+type mergeSelections struct {
+	// buf holds the tokens of the selection set
+	// of the current definition.
+	buf   []TokenValue
+	level int
+
+	nodes []mergeNode
+	key   []byte
+}
+
+// mergeNode is a selection.
+type mergeNode struct {
+	// header is the range of the tokens of the selection in buf
+	// excluding the selection set.
+	header [2]int
+
+	// children holds the nodes of the selection set.
+	children []int
+}
+
+func (s *mergeSelections) Reset() {
+	s.buf, s.level, s.nodes = s.buf[:0], 0, s.nodes[:0]
+}
+
+func (s *mergeSelections) Token(t Token, value []byte, emit Emit) {
+	if s.level < 1 && t != TokenSet {
+		emit(t, value)
+		return
+	}
+	s.buf = append(s.buf, TokenValue{Token: t, Value: value})
+	switch t {
+	case TokenSet:
+		s.level++
+		return
+	case TokenSetEnd:
+		s.level--
+	}
+	if s.level > 0 {
+		return
+	}
+
+	// The selection set of the definition is complete.
+	s.nodes = s.nodes[:0]
+	root, _ := s.parseSet(1)
+	emit(TokenSet, nil)
+	s.emitMerged(root, emit)
+	emit(TokenSetEnd, nil)
+	s.buf = s.buf[:0]
+}
+
+// parseSet parses the selections starting at buf[x] until
+// the end of the selection set and returns their nodes
+// and the index following the end of the selection set.
+func (s *mergeSelections) parseSet(x int) (nodes []int, end int) {
+	for s.buf[x].Token != TokenSetEnd {
+		n := mergeNode{header: [2]int{x, x + 1}}
+		if s.buf[x].Token == TokenFieldAlias {
+			// Include the field name.
+			n.header[1]++
+		}
+		// Include arguments and directives.
+		for inArgs := false; ; n.header[1]++ {
+			switch t := s.buf[n.header[1]].Token; {
+			case t == TokenArgList:
+				inArgs = true
+				continue
+			case t == TokenArgListEnd:
+				inArgs = false
+				continue
+			case inArgs || t == TokenDirName:
+				continue
+			}
+			break
+		}
+		x = n.header[1]
+		if s.buf[x].Token == TokenSet {
+			n.children, x = s.parseSet(x + 1)
+		}
+		nodes = append(nodes, len(s.nodes))
+		s.nodes = append(s.nodes, n)
+	}
+	return nodes, x + 1
+}
+
+// emitMerged emits the merged selections nodes.
+func (s *mergeSelections) emitMerged(nodes []int, emit Emit) {
+	// Merge the children of siblings into the first sibling.
+	var merged []int
+	keys := make(map[string]int, len(nodes))
+	for _, x := range nodes {
+		s.key = s.key[:0]
+		for _, t := range s.buf[s.nodes[x].header[0]:s.nodes[x].header[1]] {
+			s.key = append(s.key, byte(t.Token))
+			s.key = append(s.key, t.Value...)
+			s.key = append(s.key, 0)
+		}
+		if m, ok := keys[string(s.key)]; ok {
+			s.nodes[m].children = append(s.nodes[m].children, s.nodes[x].children...)
+			continue
+		}
+		keys[string(s.key)] = x
+		merged = append(merged, x)
+	}
+
+	for _, x := range merged {
+		n := s.nodes[x]
+		for _, t := range s.buf[n.header[0]:n.header[1]] {
+			emit(t.Token, t.Value)
+		}
+		if len(n.children) > 0 {
+			emit(TokenSet, nil)
+			s.emitMerged(n.children, emit)
+			emit(TokenSetEnd, nil)
+		}
+	}
+}
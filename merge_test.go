@@ -0,0 +1,59 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSelections(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `{a}`, `{a}`},
+		{decl(1), `{a a b a}`, `{a b}`},
+		{decl(1), `query Q($v: Int) @d {
+			user(id: 1) { name }
+			user(id: 2) { name }
+			user(id: 1) { email friends { id } }
+			u: user(id: 1) { id }
+			user(id: 1) @include(if: $v) { id }
+			user(id: 1) { friends { name } }
+		}`,
+			`query Q($v:Int)@d{` +
+				`user(id:1){name email friends{id name}}` +
+				`user(id:2){name}` +
+				`u:user(id:1){id}` +
+				`user(id:1)@include(if:$v){id}}`},
+		{decl(1), `{ ...F ...F @d ...F ... on T { a } ... on T { b } ... { c } }
+			fragment F on T { a { b } a { c } }`,
+			`{...F...F@d...on T{a b}...{c}}` +
+				`fragment F on T{a{b c}}`},
+		{decl(1), `{ f(o: {a: [1, {b: "x"}]}) { a } f(o: {a: [1, {b: "x"}]}) { b } }`,
+			`{f(o:{a:[1 {b:"x"}]}){a b}}`},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			p := gqlscan.NewPipeline(gqlscan.MergeSelections())
+			for x := 0; x < 2; x++ {
+				a, err := p.Apply(nil, []byte(td.input))
+				require.False(t, err.IsErr(), "unexpected error: %s", err)
+				require.Equal(t, td.expect, string(a))
+			}
+		})
+	}
+}
+
+func TestMergeSelectionsPipeline(t *testing.T) {
+	p := gqlscan.NewPipeline(
+		gqlscan.StripDirectives("client"),
+		gqlscan.MergeSelections(),
+		gqlscan.InjectTypename(),
+	)
+	a, err := p.Apply(nil, []byte(`{ a { b } a @client { c } a { b } }`))
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, `{a{b c __typename}}`, string(a))
+}
@@ -0,0 +1,18 @@
+package gqlscan
+
+// Minify appends the minified document src to dst and returns
+// the extended buffer. The minified document has no comments and
+// no more separators between the tokens than necessary, the query
+// keyword of shorthand queries is omitted and string values are
+// preserved unchanged.
+// If src is invalid then dst is returned unchanged together
+// with the error.
+func Minify(dst, src []byte) ([]byte, Error) {
+	w := writer{dst: dst}
+	if err := ScanAll(src, func(i *Iterator) {
+		w.write(i.Token(), i.Value())
+	}); err.IsErr() {
+		return dst, err
+	}
+	return w.dst, Error{}
+}
@@ -0,0 +1,51 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinify(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), "{ a }", "{a}"},
+		{decl(1), "query {\n  a\n}", "{a}"},
+		{decl(1), "query Q { a }", "query Q{a}"},
+		{decl(1), "# comment\n{ a, b # comment\n c }", "{a b c}"},
+		{decl(1), "query Q ($v: [Int!]! = [1, 2], $w: E = A) @d { a(x: $v, y: 1.5, z: true) }",
+			"query Q($v:[Int!]!=[1 2]$w:E=A)@d{a(x:$v y:1.5 z:true)}"},
+		{decl(1), `{ a(s: "  x,  # y ", b: """ block "" ,  """) }`,
+			`{a(s:"  x,  # y "b:""" block "" ,  """)}`},
+		{decl(1), "{ x: a ... on T { b } ...F ... @d { c } }\nfragment F on T { d }",
+			"{x:a...on T{b}...F...@d{c}}fragment F on T{d}"},
+		{decl(1), "mutation { a(o: { p: null, q: [ ENUM ] }) }",
+			"mutation{a(o:{p:null q:[ENUM]})}"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			dst := []byte("prefix:")
+			actual, err := gqlscan.Minify(dst, []byte(td.input))
+			require.False(t, err.IsErr(), err.Error())
+			require.Equal(t, "prefix:"+td.expect, string(actual))
+
+			// The minified document is equivalent.
+			again, err := gqlscan.Minify(nil, actual[len(dst):])
+			require.False(t, err.IsErr(), err.Error())
+			require.Equal(t, td.expect, string(again))
+		})
+	}
+}
+
+func TestMinifyErr(t *testing.T) {
+	dst := []byte("prefix:")
+	actual, err := gqlscan.Minify(dst, []byte("{ a"))
+	require.Equal(t,
+		"error at index 3: unexpected end of file; expected field name or alias",
+		err.Error())
+	require.Equal(t, "prefix:", string(actual))
+}
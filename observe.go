@@ -0,0 +1,47 @@
+package gqlscan
+
+import "time"
+
+// Observer is notified about scans performed by ScanObserved.
+type Observer interface {
+	// ObserveScan is called once after every scan.
+	ObserveScan(s ScanStats)
+}
+
+// ScanStats describes a finished scan.
+type ScanStats struct {
+	// Bytes is the length of the scanned document.
+	Bytes int
+
+	// Duration is the time the scan took including
+	// the time spent in the callback function.
+	Duration time.Duration
+
+	// Err is the error the scan returned if any.
+	Err Error
+}
+
+// ObserverFunc is an Observer function.
+type ObserverFunc func(s ScanStats)
+
+// ObserveScan implements Observer.
+func (f ObserverFunc) ObserveScan(s ScanStats) { f(s) }
+
+// ScanObserved is similar to Scan but notifies o after the scan.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanObserved returns!
+func ScanObserved(
+	str []byte,
+	o Observer,
+	fn func(*Iterator) (err bool),
+) Error {
+	start := time.Now()
+	err := Scan(str, fn)
+	o.ObserveScan(ScanStats{
+		Bytes:    len(str),
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	return err
+}
@@ -0,0 +1,208 @@
+package gqlscan
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// Option configures a scan performed by ScanWithOptions.
+type Option func(*options)
+
+type options struct {
+	offset       int
+	observer     Observer
+	validateUTF8 bool
+	formatter    ErrorFormatter
+	lenientNums  bool
+	warn         func(Warning)
+	limits       Limits
+	noReserved   bool
+	logger       scanLogger
+	trusted      DocumentStore
+}
+
+// scanLogger logs a scan, see WithLogger.
+type scanLogger interface {
+	// trace returns fn wrapped to trace the tokens of the scan,
+	// or fn if the scan isn't traced.
+	trace(fn func(*Iterator) (err bool)) func(*Iterator) (err bool)
+
+	// log logs the error err of the scan of str.
+	log(str []byte, err Error)
+}
+
+// WithOffset makes the scan start at index offset of the document
+// similar to ScanAt. Unlike ScanAt the scan doesn't panic but fails
+// with ErrInvalOffset if offset is out of range.
+func WithOffset(offset int) Option {
+	return func(o *options) { o.offset = offset }
+}
+
+// WithObserver makes the scan notify ob after the scan
+// similar to ScanObserved.
+func WithObserver(ob Observer) Option {
+	return func(o *options) { o.observer = ob }
+}
+
+// WithUTF8Validation makes the scan verify that the document is valid UTF-8
+// and contains no control characters other than tab, line feed and
+// carriage return while scanning it. The scan stops at the first violation
+// returning ErrInvalSourceChar at its index.
+func WithUTF8Validation() Option {
+	return func(o *options) { o.validateUTF8 = true }
+}
+
+// ScanWithOptions is similar to Scan but applies opts to the scan.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanWithOptions returns!
+func ScanWithOptions(
+	str []byte,
+	fn func(*Iterator) (err bool),
+	opts ...Option,
+) Error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger != nil {
+		fn = o.logger.trace(fn)
+	}
+	var start time.Time
+	if o.observer != nil {
+		start = time.Now()
+	}
+	err := o.checkOffset(str)
+	if !err.IsErr() {
+		err = o.limits.checkSize(str, o.offset)
+	}
+	if !err.IsErr() {
+		if o.validateUTF8 {
+			err = o.scanValidatingUTF8(str, fn)
+		} else {
+			err = o.scan(str, fn)
+		}
+	}
+	if err.IsErr() {
+		err.Formatter = o.formatter
+		if o.logger != nil {
+			o.logger.log(str, err)
+		}
+	}
+	if o.observer != nil {
+		o.observer.ObserveScan(ScanStats{
+			Bytes:    len(str) - o.offset,
+			Duration: time.Since(start),
+			Err:      err,
+		})
+	}
+	return err
+}
+
+// checkOffset returns an error with code ErrInvalOffset
+// at the nearest index of str if the offset is out of range.
+func (o *options) checkOffset(str []byte) Error {
+	switch {
+	case o.offset < 0:
+		return errorAt(str, 0, ErrInvalOffset)
+	case o.offset > len(str):
+		return errorAt(str, len(str), ErrInvalOffset)
+	}
+	return Error{}
+}
+
+// scan is similar to ScanAt but configures the iterator
+// according to the options.
+func (o *options) scan(str []byte, fn func(*Iterator) (err bool)) Error {
+	if o.trusted != nil {
+		return o.scanTrusted(str, fn)
+	}
+	return o.scanLimited(str, fn)
+}
+
+// scanLimited is similar to ScanAt but configures the iterator
+// according to the options, enforces the limits and rejects
+// reserved names if enabled. Both are checked for every token
+// including the skipped ones.
+func (o *options) scanLimited(str []byte, fn func(*Iterator) (err bool)) Error {
+	i := iteratorPool.Get().(*Iterator)
+	defer iteratorPool.Put(i)
+	i.str, i.lenientNums, i.warn = str, o.lenientNums, o.warn
+	defer func() { i.lenientNums, i.warn = false, nil }()
+	limited := o.limits.enabled()
+	if !limited && !o.noReserved {
+		return i.scan(o.offset, fn)
+	}
+	l, reserved := newLimiter(o.limits, false), -1
+	i.check = func(i *Iterator) bool {
+		if o.noReserved && isReservedName(i.token, i.Value()) {
+			reserved = i.tail
+			return true
+		}
+		return limited && l.check(i)
+	}
+	defer func() { i.check = nil }()
+	err := i.scan(o.offset, fn)
+	switch {
+	case l.code != 0:
+		return l.error(str, err)
+	case reserved > -1 && err.Code == ErrCallbackFn:
+		e := errorAt(str, reserved, ErrReservedName)
+		e.DefinitionIndex, e.TokenOrdinal = err.DefinitionIndex, err.TokenOrdinal
+		e.Trail = err.Trail
+		return e
+	}
+	return err
+}
+
+// scanValidatingUTF8 is similar to scan but validates the source
+// preceding the head before every call to fn.
+func (o *options) scanValidatingUTF8(
+	str []byte,
+	fn func(*Iterator) (err bool),
+) Error {
+	checked, invalid := o.offset, -1
+	err := o.scan(str, func(i *Iterator) bool {
+		if invalid = invalidSourceChar(str, checked, i.head); invalid >= 0 {
+			return true
+		}
+		if i.head > checked {
+			checked = i.head
+		}
+		return fn(i)
+	})
+	if invalid < 0 {
+		// Include the character at the index of the error
+		// since it may be the reason of the error.
+		end := len(str)
+		if err.IsErr() && err.Index < end {
+			end = err.Index + 1
+		}
+		invalid = invalidSourceChar(str, checked, end)
+	}
+	if invalid >= 0 {
+		return errorAt(str, invalid, ErrInvalSourceChar)
+	}
+	return err
+}
+
+// invalidSourceChar returns the index of the first invalid UTF-8 sequence
+// or disallowed control character in str[x:end].
+// Returns -1 if there is none.
+func invalidSourceChar(str []byte, x, end int) int {
+	for x < end {
+		if c := str[x]; c < utf8.RuneSelf {
+			if c < 0x20 && c != '\t' && c != '\n' && c != '\r' {
+				return x
+			}
+			x++
+			continue
+		}
+		r, size := utf8.DecodeRune(str[x:])
+		if r == utf8.RuneError && size == 1 {
+			return x
+		}
+		x += size
+	}
+	return -1
+}
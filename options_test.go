@@ -0,0 +1,154 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanWithOptions(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			err := gqlscan.ScanWithOptions(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) bool {
+					require.True(t, j < len(td.expect))
+					require.Equal(t, td.expect[j].Type, i.Token())
+					require.Equal(t, td.expect[j].Value, string(i.Value()))
+					j++
+					return false
+				},
+			)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanWithOptionsOffsetObserver(t *testing.T) {
+	var stats []gqlscan.ScanStats
+	var fields []string
+	err := gqlscan.ScanWithOptions(
+		[]byte(`prefix {a b(}`),
+		func(i *gqlscan.Iterator) bool {
+			if i.Token() == gqlscan.TokenField {
+				fields = append(fields, string(i.Value()))
+			}
+			return false
+		},
+		gqlscan.WithOffset(len("prefix")),
+		gqlscan.WithObserver(gqlscan.ObserverFunc(func(s gqlscan.ScanStats) {
+			stats = append(stats, s)
+		})),
+	)
+	require.Equal(t,
+		"error at index 12 ('}'): unexpected token; expected argument name",
+		err.Error())
+	require.Equal(t, []string{"a", "b"}, fields)
+	require.Len(t, stats, 1)
+	require.Equal(t, 7, stats[0].Bytes)
+	require.Equal(t, err, stats[0].Err)
+}
+
+func TestScanWithOptionsInvalidOffset(t *testing.T) {
+	m := gqlscan.DocumentMap{}
+	require.False(t, m.Add([]byte(`{a}`)).IsErr())
+	for _, td := range []struct {
+		decl   string
+		offset int
+		opts   []gqlscan.Option
+		expect string
+	}{
+		{decl(1), -1, nil,
+			"error at index 0 ('{'): offset out of range"},
+		{decl(1), 4, nil,
+			"error at index 3 (0x0): offset out of range"},
+		{decl(1), 4, []gqlscan.Option{gqlscan.WithTrustedStore(m)},
+			"error at index 3 (0x0): offset out of range"},
+		{decl(1), -1, []gqlscan.Option{gqlscan.WithUTF8Validation()},
+			"error at index 0 ('{'): offset out of range"},
+		{decl(1), 5, []gqlscan.Option{gqlscan.WithMaxInputBytes(1)},
+			"error at index 3 (0x0): offset out of range"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			called := false
+			err := gqlscan.ScanWithOptions(
+				[]byte(`{a}`),
+				func(*gqlscan.Iterator) bool { called = true; return false },
+				append(td.opts, gqlscan.WithOffset(td.offset))...,
+			)
+			require.Equal(t, gqlscan.ErrInvalOffset, err.Code)
+			require.Equal(t, td.expect, err.Error())
+			require.False(t, called)
+		})
+	}
+}
+
+func TestScanWithOptionsUTF8Validation(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			err := gqlscan.ScanWithOptions(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) bool {
+					require.True(t, j < len(td.expect))
+					require.Equal(t, td.expect[j].Type, i.Token())
+					require.Equal(t, td.expect[j].Value, string(i.Value()))
+					j++
+					return false
+				},
+				gqlscan.WithUTF8Validation(),
+			)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanWithOptionsUTF8ValidationErr(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+		values []string
+	}{
+		{decl(1), "{a(b:\"x\xffy\") c}",
+			"error at index 7 ('\uFFFD'): invalid source character",
+			[]string{"a"}},
+		{decl(1), "{a(b:\"\"\"x\xc3\"\"\") c}",
+			"error at index 9 ('\uFFFD'): invalid source character",
+			[]string{"a"}},
+		{decl(1), "{a # \xed\xa0\x80\n b}",
+			"error at index 5 ('\uFFFD'): invalid source character",
+			[]string{"a"}},
+		{decl(1), "{a # \x00\n b}",
+			"error at index 5 (0x0): invalid source character",
+			[]string{"a"}},
+		{decl(1), "{a} # \xff",
+			"error at index 6 ('\uFFFD'): invalid source character",
+			[]string{"a"}},
+		{decl(1), "{a \xff}",
+			"error at index 3 ('\uFFFD'): invalid source character",
+			[]string{"a"}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var values []string
+			err := gqlscan.ScanWithOptions(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) bool {
+					if i.Token() == gqlscan.TokenField {
+						values = append(values, string(i.Value()))
+					}
+					return false
+				},
+				gqlscan.WithUTF8Validation(),
+			)
+			require.Equal(t, gqlscan.ErrInvalSourceChar, err.Code)
+			require.Equal(t, td.expect, err.Error())
+			require.Equal(t, td.values, values)
+		})
+	}
+}
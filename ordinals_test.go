@@ -0,0 +1,72 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorOrdinals(t *testing.T) {
+	for _, td := range []struct {
+		decl       string
+		input      string
+		definition int
+		token      int
+	}{
+		{decl(1), "", 0, 0},
+		{decl(1), "}", 0, 0},
+		{decl(1), "{a(}", 0, 4},
+		{decl(1), "{a} {b", 1, 7},
+		{decl(1), "{a} }", 1, 5},
+		{decl(1), "query A {a} query B {b} query C {c(}", 2, 17},
+		{decl(1), "fragment F on T {a} {b", 1, 9},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanAll([]byte(td.input), func(*gqlscan.Iterator) {})
+			require.True(t, err.IsErr())
+			require.Equal(t, td.definition, err.DefinitionIndex, "definition")
+			require.Equal(t, td.token, err.TokenOrdinal, "token")
+
+			err = gqlscan.Validate([]byte(td.input))
+			require.Equal(t, td.definition, err.DefinitionIndex, "definition")
+			require.Equal(t, td.token, err.TokenOrdinal, "token")
+		})
+	}
+}
+
+func TestErrorOrdinalsCallbackFn(t *testing.T) {
+	src := []byte("{a} {b c}")
+	n := 0
+	err := gqlscan.Scan(src, func(i *gqlscan.Iterator) bool {
+		n++
+		return i.Token() == gqlscan.TokenField && string(i.Value()) == "c"
+	})
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+	require.Equal(t, 1, err.DefinitionIndex)
+	require.Equal(t, n-1, err.TokenOrdinal)
+}
+
+func TestErrorOrdinalsScanDocument(t *testing.T) {
+	for _, td := range []struct {
+		decl       string
+		input      string
+		definition int
+		token      int
+	}{
+		{decl(1), "scalar S type T {a:", 1, 7},
+		{decl(1), "scalar S {a} type T {a:", 2, 12},
+		{decl(1), "scalar S {a} type T {a:S} {b(}", 3, 19},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanDocument(
+				[]byte(td.input),
+				func(*gqlscan.Iterator) bool { return false },
+			)
+			require.True(t, err.IsErr())
+			require.Equal(t, td.definition, err.DefinitionIndex, "definition")
+			require.Equal(t, td.token, err.TokenOrdinal, "token")
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package gqlscan
+
+import (
+	"bytes"
+	"sync"
+)
+
+// Emit passes a token on to the next stage of a Pipeline.
+// value must stay valid until the pipeline returns.
+type Emit func(t Token, value []byte)
+
+// Transform is a stage of a Pipeline transforming the token stream.
+// A Transform may keep state between tokens and is therefore
+// never used by more than one Pipeline.Apply at a time.
+type Transform interface {
+	// Reset is called before a new document is transformed.
+	Reset()
+
+	// Token is called for every token of the input stream
+	// and calls emit for every token of the output stream.
+	Token(t Token, value []byte, emit Emit)
+}
+
+// Pipeline applies a sequence of transforms to the token stream
+// of a document in a single scan and writes the result minified.
+// A Pipeline is safe for concurrent use.
+type Pipeline struct {
+	transforms []func() Transform
+	pool       sync.Pool
+}
+
+// pipelineState is the pooled state of a single application
+// of a pipeline.
+type pipelineState struct {
+	transforms []Transform
+
+	// emit holds the Emit of each stage where emit[0]
+	// feeds the first transform and the last one feeds w.
+	emit []Emit
+	w    writer
+}
+
+// NewPipeline creates a new pipeline applying the transforms
+// created by the given constructors in the given order.
+// A pipeline without transforms only minifies.
+func NewPipeline(transforms ...func() Transform) *Pipeline {
+	p := &Pipeline{transforms: transforms}
+	p.pool.New = func() any { return p.newState() }
+	return p
+}
+
+func (p *Pipeline) newState() *pipelineState {
+	s := &pipelineState{
+		transforms: make([]Transform, len(p.transforms)),
+		emit:       make([]Emit, len(p.transforms)+1),
+	}
+	for x, t := range p.transforms {
+		s.transforms[x] = t()
+	}
+	s.emit[len(s.transforms)] = s.w.write
+	for x := len(s.transforms) - 1; x >= 0; x-- {
+		t, next := s.transforms[x], s.emit[x+1]
+		s.emit[x] = func(tk Token, value []byte) {
+			t.Token(tk, value, next)
+		}
+	}
+	return s
+}
+
+// Apply appends the transformed document src to dst and
+// returns the extended buffer. If src is invalid then dst
+// is returned unchanged together with the error.
+func (p *Pipeline) Apply(dst, src []byte) ([]byte, Error) {
+	s := p.pool.Get().(*pipelineState)
+	defer p.pool.Put(s)
+
+	for _, t := range s.transforms {
+		t.Reset()
+	}
+	s.w.reset(dst)
+	emit := s.emit[0]
+	if err := ScanAll(src, func(i *Iterator) {
+		emit(i.Token(), i.Value())
+	}); err.IsErr() {
+		s.w.dst = nil
+		return dst, err
+	}
+	dst, s.w.dst = s.w.dst, nil
+	return dst, Error{}
+}
+
+// StripDirectives returns a transform removing all directives
+// with the given names including their arguments.
+// If no names are given then all directives are removed.
+func StripDirectives(names ...string) func() Transform {
+	return func() Transform {
+		return &stripDirectives{names: names}
+	}
+}
+
+type stripDirectives struct {
+	names []string
+
+	// dir is true after the name of a removed directive
+	// and inArgs is true inside of its arguments.
+	dir, inArgs bool
+}
+
+func (s *stripDirectives) Reset() { s.dir, s.inArgs = false, false }
+
+func (s *stripDirectives) Token(t Token, value []byte, emit Emit) {
+	switch {
+	case s.inArgs:
+		s.inArgs = t != TokenArgListEnd
+		return
+	case s.dir:
+		s.dir = false
+		if t == TokenArgList {
+			s.inArgs = true
+			return
+		}
+	}
+	if t == TokenDirName && s.match(value) {
+		s.dir = true
+		return
+	}
+	emit(t, value)
+}
+
+func (s *stripDirectives) match(name []byte) bool {
+	if len(s.names) < 1 {
+		return true
+	}
+	for _, n := range s.names {
+		if string(name) == n {
+			return true
+		}
+	}
+	return false
+}
+
+// InjectTypename returns a transform adding the __typename field
+// to every selection set that doesn't already select it
+// except the selection sets of operations.
+func InjectTypename() func() Transform {
+	return func() Transform {
+		return &injectTypename{hasTypename: make([]bool, 0, 16)}
+	}
+}
+
+type injectTypename struct {
+	// opr is true inside of operations where the root
+	// selection set is left unchanged.
+	opr bool
+
+	// hasTypename holds whether __typename is selected
+	// for each selection set entered.
+	hasTypename []bool
+}
+
+var typename = []byte("__typename")
+
+func (s *injectTypename) Reset() {
+	s.opr, s.hasTypename = false, s.hasTypename[:0]
+}
+
+func (s *injectTypename) Token(t Token, value []byte, emit Emit) {
+	switch t {
+	case TokenDefQry, TokenDefMut, TokenDefSub:
+		s.opr = true
+	case TokenDefFrag:
+		s.opr = false
+	case TokenSet:
+		s.hasTypename = append(s.hasTypename, false)
+	case TokenField:
+		if bytes.Equal(value, typename) {
+			s.hasTypename[len(s.hasTypename)-1] = true
+		}
+	case TokenSetEnd:
+		l := len(s.hasTypename) - 1
+		if !s.hasTypename[l] && (l > 0 || !s.opr) {
+			emit(TokenField, typename)
+		}
+		s.hasTypename = s.hasTypename[:l]
+	}
+	emit(t, value)
+}
@@ -0,0 +1,109 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline(t *testing.T) {
+	for _, td := range []struct {
+		decl       string
+		transforms []func() gqlscan.Transform
+		input      string
+		expect     string
+	}{
+		{decl(1), nil,
+			"query { a b }",
+			"{a b}"},
+		{decl(1), nil,
+			"query Q ($a: [Int!]! = [1, 2], $b: String = \"x\" @d) {\n" +
+				"  # comment\n" +
+				"  x: f(a: $a, b: {c: true, d: [null, ENUM]}) @g(h: 1.5)\n" +
+				"  ... F\n" +
+				"  ... on T { y }\n" +
+				"  ... @skip(if: false) { z }\n" +
+				"}",
+			`query Q($a:[Int!]!=[1 2]$b:String="x"@d){` +
+				`x:f(a:$a b:{c:true d:[null ENUM]})@g(h:1.5)` +
+				`...F...on T{y}...@skip(if:false){z}}`},
+		{decl(1), nil,
+			`{f(a: ["", "b", """c""", 1, -2, true, "d"])}`,
+			`{f(a:["" "b" """c"""1 -2 true "d"])}`},
+		{decl(1), nil,
+			"mutation M { a } subscription S { b } fragment F on T { c }",
+			"mutation M{a}subscription S{b}fragment F on T{c}"},
+		{decl(1),
+			[]func() gqlscan.Transform{gqlscan.StripDirectives()},
+			"query Q($v: Int) @b { f @c(y: 2) @d { g } ...F @e }",
+			"query Q($v:Int){f{g}...F}"},
+		{decl(1),
+			[]func() gqlscan.Transform{
+				gqlscan.StripDirectives("client", "export"),
+			},
+			"{ a @client b @include(if: $c) c @export(as: \"x\") }",
+			"{a b@include(if:$c)c}"},
+		{decl(1),
+			[]func() gqlscan.Transform{gqlscan.InjectTypename()},
+			"{ a { b { __typename c } d: __typename } ...F }" +
+				"fragment F on T { e }",
+			"{a{b{__typename c}d:__typename}...F}" +
+				"fragment F on T{e __typename}"},
+		{decl(1),
+			[]func() gqlscan.Transform{
+				gqlscan.StripDirectives("client"),
+				gqlscan.InjectTypename(),
+			},
+			"query { a { b @client { c } d } }",
+			"{a{b{c __typename}d __typename}}"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			p := gqlscan.NewPipeline(td.transforms...)
+			// Apply twice to make sure the pooled state is reset.
+			for x := 0; x < 2; x++ {
+				a, err := p.Apply([]byte("prefix:"), []byte(td.input))
+				require.False(t, err.IsErr(), "unexpected error: %s", err)
+				require.Equal(t, "prefix:"+td.expect, string(a))
+				err = gqlscan.ScanAll(a[len("prefix:"):], func(*gqlscan.Iterator) {})
+				require.False(t, err.IsErr(), "invalid output: %s", err)
+			}
+		})
+	}
+}
+
+func TestPipelineErr(t *testing.T) {
+	p := gqlscan.NewPipeline(gqlscan.InjectTypename())
+	dst := []byte("prefix")
+	a, err := p.Apply(dst, []byte(`{a{b}`))
+	require.True(t, err.IsErr())
+	require.Equal(t, "error at index 5: unexpected end of file; "+
+		"expected selection or end of selection set", err.Error())
+	require.Equal(t, "prefix", string(a))
+}
+
+// TestPipelineRoundtrip makes sure the pipeline without transforms
+// preserves the token stream of all valid test inputs.
+func TestPipelineRoundtrip(t *testing.T) {
+	p := gqlscan.NewPipeline()
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			a, err := p.Apply(nil, []byte(td.input))
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+
+			j := 0
+			err = gqlscan.ScanAll(a, func(i *gqlscan.Iterator) {
+				require.Less(t, j, len(td.expect), "output: %s", a)
+				require.Equal(t, td.expect[j].Type, i.Token(),
+					"token %d of output: %s", j, a)
+				require.Equal(t, td.expect[j].Value, string(i.Value()),
+					"token %d of output: %s", j, a)
+				j++
+			})
+			require.False(t, err.IsErr(),
+				"unexpected error: %s; output: %s", err, a)
+			require.Len(t, td.expect, j, "output: %s", a)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package gqlscan
+
+import "unicode/utf8"
+
+// LineColumn returns the 1-based line and column of index in str.
+// Columns are counted in runes. "\n", "\r" and "\r\n" are each
+// treated as a single line terminator.
+// An index beyond the end of str is clamped to len(str).
+func LineColumn(str []byte, index int) (line, column int) {
+	line, column = 1, 1
+	for i := 0; i < index && i < len(str); {
+		r, s := utf8.DecodeRune(str[i:])
+		i += s
+		switch r {
+		case '\r':
+			if i < len(str) && i < index && str[i] == '\n' {
+				i++
+			}
+			fallthrough
+		case '\n':
+			line, column = line+1, 1
+			continue
+		}
+		column++
+	}
+	return line, column
+}
+
+// lineCounter computes the lines and columns of increasing indexes
+// of str advancing from the previous index instead of
+// counting from the start of str every time.
+type lineCounter struct {
+	str                 []byte
+	index, line, column int
+}
+
+// lineColumn is similar to LineColumn but only counts from the
+// previous index unless index precedes it.
+func (c *lineCounter) lineColumn(index int) (line, column int) {
+	if index > len(c.str) {
+		index = len(c.str)
+	}
+	if c.line < 1 || index < c.index {
+		c.index, c.line, c.column = 0, 1, 1
+	}
+	from := c.index
+	if from > 0 && from < index &&
+		c.str[from-1] == '\r' && c.str[from] == '\n' {
+		// The line terminator "\r\n" was counted already.
+		from++
+	}
+	l, col := LineColumn(c.str[from:], index-from)
+	if l > 1 {
+		c.line, c.column = c.line+l-1, col
+	} else {
+		c.column += col - 1
+	}
+	c.index = index
+	return c.line, c.column
+}
+
+// Position returns the 1-based line and column of the error
+// in str, which must be the source the error was returned for.
+func (e Error) Position(str []byte) (line, column int) {
+	return LineColumn(str, e.Index)
+}
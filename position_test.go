@@ -0,0 +1,46 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineColumn(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		index  int
+		line   int
+		column int
+	}{
+		{decl(1), "", 0, 1, 1},
+		{decl(1), "abc", 0, 1, 1},
+		{decl(1), "abc", 2, 1, 3},
+		{decl(1), "abc", 3, 1, 4},
+		{decl(1), "abc", 42, 1, 4},
+		{decl(1), "a\nb", 2, 2, 1},
+		{decl(1), "a\rb", 2, 2, 1},
+		{decl(1), "a\r\nb", 3, 2, 1},
+		{decl(1), "a\r\n\r\nb", 5, 3, 1},
+		{decl(1), "a\n\rb", 3, 3, 1},
+		{decl(1), "\"ä\"x", 4, 1, 4},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			l, c := gqlscan.LineColumn([]byte(td.input), td.index)
+			require.Equal(t, td.line, l, "line")
+			require.Equal(t, td.column, c, "column")
+		})
+	}
+}
+
+func TestErrorPosition(t *testing.T) {
+	src := []byte("query Q {\r\n  a(\r\n  b: \"ä\" c)\n}")
+	err := gqlscan.ScanAll(src, func(*gqlscan.Iterator) {})
+	require.True(t, err.IsErr())
+	l, c := err.Position(src)
+	require.Equal(t, 3, l)
+	require.Equal(t, 11, c)
+}
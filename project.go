@@ -0,0 +1,235 @@
+package gqlscan
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+// Project is an index of the definitions of all documents
+// of a directory.
+type Project struct {
+	// Operations holds all operations in order of the files
+	// and their appearance.
+	Operations []ProjectDefinition
+
+	// Fragments holds the first definition of each fragment by name.
+	Fragments map[string]ProjectDefinition
+
+	// Problems holds the scan errors, duplicate definitions and
+	// spreads of undefined fragments in order of the files.
+	Problems []ProjectProblem
+}
+
+// ProjectDefinition is a definition in a project.
+type ProjectDefinition struct {
+	// Token is the token of the definition, for example TokenDefQry.
+	Token Token
+
+	// Name is the name of the definition and is empty
+	// for anonymous operations.
+	Name string
+
+	// Location is where the definition begins.
+	Location ProjectLocation
+
+	// Span is the span of the definition in its file.
+	Span Span
+
+	// Spreads holds the names of the fragments spread
+	// in the definition in order of appearance.
+	Spreads []string
+}
+
+// ProjectLocation is a location in a file of a project.
+type ProjectLocation struct {
+	File string
+
+	// Index is the byte index while Line and Column are 1-based
+	// and Column is counted in runes.
+	Index, Line, Column int
+}
+
+func (l ProjectLocation) String() string {
+	return fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+}
+
+// ProjectProblem is a problem found in a project.
+type ProjectProblem struct {
+	Location ProjectLocation
+	Message  string
+}
+
+func (p ProjectProblem) String() string {
+	return p.Location.String() + ": " + p.Message
+}
+
+// defaultProjectExtensions are the extensions of the files
+// ScanProject scans if no extensions are passed.
+var defaultProjectExtensions = []string{".graphql", ".gql"}
+
+// ScanProject scans all documents in fsys with a name ending
+// with one of extensions and indexes their definitions.
+// If no extensions are passed then the documents ending
+// with ".graphql" or ".gql" are scanned.
+// Fragment spreads are resolved across all documents.
+// Returns an error only if fsys can't be read.
+func ScanProject(fsys fs.FS, extensions ...string) (*Project, error) {
+	if len(extensions) < 1 {
+		extensions = defaultProjectExtensions
+	}
+	p := &Project{Fragments: map[string]ProjectDefinition{}}
+	operations := map[string]ProjectDefinition{}
+	err := fs.WalkDir(fsys, ".", func(
+		name string, d fs.DirEntry, err error,
+	) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !hasExtension(name, extensions) {
+			return nil
+		}
+		src, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		p.scanFile(name, src, operations)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Report spreads of undefined fragments.
+	check := func(def ProjectDefinition) {
+		for _, s := range def.Spreads {
+			if _, ok := p.Fragments[s]; !ok {
+				p.Problems = append(p.Problems, ProjectProblem{
+					Location: def.Location,
+					Message: fmt.Sprintf(
+						"fragment %q spread in %s is undefined",
+						s, describeDefinition(def),
+					),
+				})
+			}
+		}
+	}
+	for _, o := range p.Operations {
+		check(o)
+	}
+	fragments := make([]ProjectDefinition, 0, len(p.Fragments))
+	for _, f := range p.Fragments {
+		fragments = append(fragments, f)
+	}
+	sort.Slice(fragments, func(i, j int) bool {
+		a, b := fragments[i].Location, fragments[j].Location
+		return a.File < b.File || (a.File == b.File && a.Index < b.Index)
+	})
+	for _, f := range fragments {
+		check(f)
+	}
+	return p, nil
+}
+
+func (p *Project) scanFile(
+	name string,
+	src []byte,
+	operations map[string]ProjectDefinition,
+) {
+	lines := lineCounter{str: src}
+	location := func(index int) ProjectLocation {
+		l, c := lines.lineColumn(index)
+		return ProjectLocation{File: name, Index: index, Line: l, Column: c}
+	}
+	var defs []ProjectDefinition
+	err := ScanAll(src, func(i *Iterator) {
+		switch t := i.Token(); t {
+		case TokenDefQry, TokenDefMut, TokenDefSub, TokenDefFrag:
+			defs = append(defs, ProjectDefinition{
+				Token:    t,
+				Location: location(i.defHead),
+			})
+		case TokenOprName, TokenFragName:
+			defs[len(defs)-1].Name = string(i.Value())
+		case TokenNamedSpread:
+			d := &defs[len(defs)-1]
+			d.Spreads = append(d.Spreads, string(i.Value()))
+		case TokenDefEnd:
+			d := &defs[len(defs)-1]
+			d.Span = Span{Tail: d.Location.Index, Head: i.head + 1}
+		}
+	})
+	if err.IsErr() {
+		p.Problems = append(p.Problems, ProjectProblem{
+			Location: location(err.Index),
+			Message:  err.Error(),
+		})
+		return
+	}
+
+	for _, d := range defs {
+		if d.Token == TokenDefFrag {
+			if f, ok := p.Fragments[d.Name]; ok {
+				p.Problems = append(p.Problems, ProjectProblem{
+					Location: d.Location,
+					Message: fmt.Sprintf(
+						"fragment %q is already defined at %s",
+						d.Name, f.Location,
+					),
+				})
+				continue
+			}
+			p.Fragments[d.Name] = d
+			continue
+		}
+		if d.Name != "" {
+			if o, ok := operations[d.Name]; ok {
+				p.Problems = append(p.Problems, ProjectProblem{
+					Location: d.Location,
+					Message: fmt.Sprintf(
+						"operation %q is already defined at %s",
+						d.Name, o.Location,
+					),
+				})
+				continue
+			}
+			operations[d.Name] = d
+		}
+		p.Operations = append(p.Operations, d)
+	}
+}
+
+// hasExtension returns true if name ends with one of extensions.
+func hasExtension(name string, extensions []string) bool {
+	ext := path.Ext(name)
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+func describeDefinition(d ProjectDefinition) string {
+	kind := definitionKeyword(d.Token)
+	if d.Name == "" {
+		return "anonymous " + kind
+	}
+	return fmt.Sprintf("%s %q", kind, d.Name)
+}
+
+// definitionKeyword returns the keyword of definition token t.
+func definitionKeyword(t Token) string {
+	switch t {
+	case TokenDefQry:
+		return "query"
+	case TokenDefMut:
+		return "mutation"
+	case TokenDefSub:
+		return "subscription"
+	case TokenDefFrag:
+		return "fragment"
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package gqlscan_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanProject(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.graphql": {Data: []byte("query A {\n  ...F\n  ...G\n}\n" +
+			"fragment F on T { b ...H }")},
+		"b/c.gql": {Data: []byte("\nmutation { x }\n" +
+			"  fragment F on T { c }\nquery A { y }")},
+		"b/d.graphql": {Data: []byte("{ a(}")},
+		"readme.md":   {Data: []byte("{ invalid")},
+	}
+	p, err := gqlscan.ScanProject(fsys)
+	require.NoError(t, err)
+
+	require.Len(t, p.Operations, 2)
+	require.Equal(t, gqlscan.ProjectDefinition{
+		Token: gqlscan.TokenDefQry,
+		Name:  "A",
+		Location: gqlscan.ProjectLocation{
+			File: "a.graphql", Index: 0, Line: 1, Column: 1,
+		},
+		Span:    gqlscan.Span{Tail: 0, Head: 25},
+		Spreads: []string{"F", "G"},
+	}, p.Operations[0])
+	require.Equal(t, gqlscan.ProjectDefinition{
+		Token: gqlscan.TokenDefMut,
+		Location: gqlscan.ProjectLocation{
+			File: "b/c.gql", Index: 1, Line: 2, Column: 1,
+		},
+		Span: gqlscan.Span{Tail: 1, Head: 15},
+	}, p.Operations[1])
+
+	require.Len(t, p.Fragments, 1)
+	require.Equal(t, "a.graphql", p.Fragments["F"].Location.File)
+	require.Equal(t, []string{"H"}, p.Fragments["F"].Spreads)
+
+	var problems []string
+	for _, p := range p.Problems {
+		problems = append(problems, p.String())
+	}
+	require.Equal(t, []string{
+		`b/c.gql:3:3: fragment "F" is already defined at a.graphql:5:1`,
+		`b/c.gql:4:1: operation "A" is already defined at a.graphql:1:1`,
+		"b/d.graphql:1:5: error at index 4 ('}'): " +
+			"unexpected token; expected argument name",
+		`a.graphql:1:1: fragment "G" spread in query "A" is undefined`,
+		`a.graphql:5:1: fragment "H" spread in fragment "F" is undefined`,
+	}, problems)
+}
+
+func TestScanProjectLocations(t *testing.T) {
+	var src []byte
+	for _, sep := range []string{"\r\n", "\n", "\r", " # ä\r\n\r", "\n\r"} {
+		src = append(src, `query { a(b: "ä") }`+sep...)
+	}
+	src = append(src, "  query { a }"...)
+	p, err := gqlscan.ScanProject(fstest.MapFS{"a.graphql": {Data: src}})
+	require.NoError(t, err)
+	require.Len(t, p.Operations, 6)
+	for _, o := range p.Operations {
+		l, c := gqlscan.LineColumn(src, o.Location.Index)
+		require.Equal(t, l, o.Location.Line, "index %d", o.Location.Index)
+		require.Equal(t, c, o.Location.Column, "index %d", o.Location.Index)
+	}
+	require.Equal(t, 8, p.Operations[5].Location.Line)
+	require.Equal(t, 3, p.Operations[5].Location.Column)
+}
+
+func TestScanProjectExtensions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.graphql": {Data: []byte("query A { a }")},
+		"b.gql":     {Data: []byte("query B { b }")},
+		"c.txt":     {Data: []byte("query C { c }")},
+	}
+	names := func(p *gqlscan.Project) (n []string) {
+		for _, o := range p.Operations {
+			n = append(n, o.Name)
+		}
+		return n
+	}
+
+	p, err := gqlscan.ScanProject(fsys)
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, names(p))
+
+	p, err = gqlscan.ScanProject(fsys, ".txt", ".gql")
+	require.NoError(t, err)
+	require.Equal(t, []string{"B", "C"}, names(p))
+}
@@ -0,0 +1,145 @@
+// Package prometheus provides a gqlscan.Observer exposing
+// scan metrics to Prometheus.
+package prometheus
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/graph-guard/gqlscan"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Observer is a gqlscan.Observer recording scan metrics.
+// Observer is a prometheus.Collector and must be registered.
+type Observer struct {
+	scans    prom.Counter
+	errors   *prom.CounterVec
+	limits   *prom.CounterVec
+	bytes    prom.Counter
+	duration prom.Histogram
+}
+
+var _ gqlscan.Observer = new(Observer)
+var _ prom.Collector = new(Observer)
+
+// NewObserver creates a new observer with metrics prefixed with namespace.
+// If namespace is empty then "gqlscan" is used.
+func NewObserver(namespace string) *Observer {
+	if namespace == "" {
+		namespace = "gqlscan"
+	}
+	return &Observer{
+		scans: prom.NewCounter(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "scans_total",
+			Help:      "Total number of scanned documents.",
+		}),
+		errors: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Total number of documents that failed to scan by error code.",
+		}, []string{"code"}),
+		limits: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "limit_rejections_total",
+			Help:      "Total number of documents rejected for exceeding a limit by kind of limit.",
+		}, []string{"limit"}),
+		bytes: prom.NewCounter(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "scanned_bytes_total",
+			Help:      "Total number of scanned bytes.",
+		}),
+		duration: prom.NewHistogram(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "scan_duration_seconds",
+			Help:      "Duration of scans in seconds.",
+			Buckets:   prom.ExponentialBuckets(1e-6, 4, 12),
+		}),
+	}
+}
+
+// ObserveScan implements gqlscan.Observer.
+func (o *Observer) ObserveScan(s gqlscan.ScanStats) {
+	o.scans.Inc()
+	o.bytes.Add(float64(s.Bytes))
+	o.duration.Observe(s.Duration.Seconds())
+	if s.Err.IsErr() {
+		l := codeLabel(s.Err.Code)
+		o.errors.WithLabelValues(l).Inc()
+		var limitErr *gqlscan.LimitError
+		if errors.As(s.Err, &limitErr) {
+			o.limits.WithLabelValues(l).Inc()
+		}
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (o *Observer) Describe(c chan<- *prom.Desc) {
+	o.scans.Describe(c)
+	o.errors.Describe(c)
+	o.limits.Describe(c)
+	o.bytes.Describe(c)
+	o.duration.Describe(c)
+}
+
+// Collect implements prometheus.Collector.
+func (o *Observer) Collect(c chan<- prom.Metric) {
+	o.scans.Collect(c)
+	o.errors.Collect(c)
+	o.limits.Collect(c)
+	o.bytes.Collect(c)
+	o.duration.Collect(c)
+}
+
+// codeLabels holds the label of each error code.
+// Labels must never change once released since
+// they're part of the exposed time series.
+var codeLabels = [...]string{
+	gqlscan.ErrCallbackFn:        "callback_fn",
+	gqlscan.ErrUnexpToken:        "unexpected_token",
+	gqlscan.ErrUnexpEOF:          "unexpected_eof",
+	gqlscan.ErrIllegalFragName:   "illegal_fragment_name",
+	gqlscan.ErrInvalNum:          "invalid_number",
+	gqlscan.ErrInvalType:         "invalid_type",
+	gqlscan.ErrUntrustedDoc:      "untrusted_document",
+	gqlscan.ErrDocMismatch:       "document_mismatch",
+	gqlscan.ErrInvalJSON:         "invalid_json",
+	gqlscan.ErrReservedName:      "reserved_name",
+	gqlscan.ErrInvalRequest:      "invalid_request",
+	gqlscan.ErrNameAfterNum:      "name_after_number",
+	gqlscan.ErrInvalSourceChar:   "invalid_source_character",
+	gqlscan.ErrSelTooDeep:        "selection_too_deep",
+	gqlscan.ErrTooManyTokens:     "too_many_tokens",
+	gqlscan.ErrValTooDeep:        "value_too_deep",
+	gqlscan.ErrDocTooLarge:       "document_too_large",
+	gqlscan.ErrTooManyAliases:    "too_many_aliases",
+	gqlscan.ErrTooManyRootFields: "too_many_root_fields",
+	gqlscan.ErrTooManyFrags:      "too_many_fragments",
+	gqlscan.ErrTooManySpreads:    "too_many_spreads",
+	gqlscan.ErrNameTooLong:       "name_too_long",
+	gqlscan.ErrStrTooLong:        "string_too_long",
+	gqlscan.ErrTooManyOprs:       "too_many_operations",
+	gqlscan.ErrTooManyArgs:       "too_many_arguments",
+	gqlscan.ErrTooManyDirs:       "too_many_directives",
+	gqlscan.ErrAnonOprNotAlone:   "anonymous_operation_not_alone",
+	gqlscan.ErrSubMultiRoot:      "subscription_multiple_root_fields",
+	gqlscan.ErrSubIntrospection:  "subscription_introspection",
+	gqlscan.ErrUndefFrag:         "undefined_fragment",
+	gqlscan.ErrTooComplex:        "too_complex",
+	gqlscan.ErrTimeout:           "timeout",
+	gqlscan.ErrTooManyTypenames:  "too_many_typenames",
+	gqlscan.ErrInvalOffset:       "invalid_offset",
+	gqlscan.ErrInvalCheckpoint:   "invalid_checkpoint",
+}
+
+// codeLabel returns the label of error code c,
+// for example "unexpected_token" for ErrUnexpToken.
+// Codes without a label are labeled by their number.
+func codeLabel(c gqlscan.ErrorCode) string {
+	if c > 0 && int(c) < len(codeLabels) && codeLabels[c] != "" {
+		return codeLabels[c]
+	}
+	return strconv.Itoa(int(c))
+}
@@ -0,0 +1,56 @@
+package prometheus_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+	"github.com/graph-guard/gqlscan/prometheus"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver(t *testing.T) {
+	o := prometheus.NewObserver("")
+	r := prom.NewPedanticRegistry()
+	require.NoError(t, r.Register(o))
+
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, input := range []string{`{a}`, `{b c}`, `{`, `{a(}`, `{a(}`} {
+		gqlscan.ScanObserved([]byte(input), o, noop)
+	}
+	for _, input := range []string{`{a{b}}`, `{a{b{c}}}`, `{a b}`} {
+		gqlscan.ScanWithOptions([]byte(input), noop,
+			gqlscan.WithObserver(o),
+			gqlscan.WithMaxSelectionDepth(1),
+			gqlscan.WithMaxTokens(5),
+		)
+	}
+
+	require.NoError(t, testutil.GatherAndCompare(r, strings.NewReader(`
+# HELP gqlscan_errors_total Total number of documents that failed to scan by error code.
+# TYPE gqlscan_errors_total counter
+gqlscan_errors_total{code="selection_too_deep"} 2
+gqlscan_errors_total{code="too_many_tokens"} 1
+gqlscan_errors_total{code="unexpected_eof"} 1
+gqlscan_errors_total{code="unexpected_token"} 2
+# HELP gqlscan_limit_rejections_total Total number of documents rejected for exceeding a limit by kind of limit.
+# TYPE gqlscan_limit_rejections_total counter
+gqlscan_limit_rejections_total{limit="selection_too_deep"} 2
+gqlscan_limit_rejections_total{limit="too_many_tokens"} 1
+# HELP gqlscan_scanned_bytes_total Total number of scanned bytes.
+# TYPE gqlscan_scanned_bytes_total counter
+gqlscan_scanned_bytes_total 37
+# HELP gqlscan_scans_total Total number of scanned documents.
+# TYPE gqlscan_scans_total counter
+gqlscan_scans_total 8
+`),
+		"gqlscan_errors_total",
+		"gqlscan_limit_rejections_total",
+		"gqlscan_scanned_bytes_total",
+		"gqlscan_scans_total",
+	))
+	require.Equal(t, 1, testutil.CollectAndCount(o, "gqlscan_scan_duration_seconds"))
+}
@@ -0,0 +1,61 @@
+package gqlscan
+
+// Record is a recorded token stream of a document
+// that can be replayed multiple times without scanning
+// the document again.
+// The zero value is an empty record ready to use.
+type Record struct {
+	str    []byte
+	tokens []lexerToken
+	err    Error
+}
+
+// Scan resets r and records the tokens of str,
+// the buffer of r is reused.
+// Returns the error of the scan, the tokens preceding
+// the error are recorded.
+func (r *Record) Scan(str []byte) Error {
+	r.str, r.tokens = str, r.tokens[:0]
+	r.err = ScanAll(str, func(i *Iterator) {
+		r.tokens = append(r.tokens, lexerToken{
+			token:    i.token,
+			tail:     i.tail,
+			head:     i.head,
+			levelSel: i.levelSel,
+		})
+	})
+	return r.err
+}
+
+// Len returns the number of recorded tokens.
+func (r *Record) Len() int { return len(r.tokens) }
+
+// Replay calls fn for every recorded token providing its type,
+// value, indexes and selector level, and returns the error
+// of the recorded scan.
+// If fn returns true or calls Abort on the iterator then replaying
+// stops and an error with code ErrCallbackFn is returned, the error
+// passed to Abort is held in its field Err.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after Replay returns!
+func (r *Record) Replay(fn func(*Iterator) (err bool)) Error {
+	i := iteratorPool.Get().(*Iterator)
+	defer iteratorPool.Put(i)
+	i.Reset(r.str)
+	i.expect, i.def, i.defHead, i.skipEnd = 0, 0, 0, 0
+	def := 0
+	for x, t := range r.tokens {
+		i.token, i.tail, i.head, i.levelSel = t.token, t.tail, t.head, t.levelSel
+		if fn(i) || i.abortErr != nil {
+			err := errorAt(r.str, t.head, ErrCallbackFn)
+			err.DefinitionIndex, err.TokenOrdinal = def, x
+			err.Err = i.abortErr
+			return err
+		}
+		if t.token == TokenDefEnd {
+			def++
+		}
+	}
+	return r.err
+}
@@ -0,0 +1,98 @@
+package gqlscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	var r gqlscan.Record
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			err := r.Scan([]byte(td.input))
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, len(td.expect), r.Len())
+
+			var levels []int
+			gqlscan.ScanAll([]byte(td.input), func(i *gqlscan.Iterator) {
+				levels = append(levels, i.LevelSelect())
+			})
+
+			// Replay twice.
+			for n := 0; n < 2; n++ {
+				j := 0
+				err = r.Replay(func(i *gqlscan.Iterator) bool {
+					require.Equal(t, td.expect[j].Type, i.Token())
+					require.Equal(t, td.expect[j].Value, string(i.Value()))
+					require.Equal(t, levels[j], i.LevelSelect())
+					j++
+					return false
+				})
+				require.False(t, err.IsErr(), "unexpected error: %s", err)
+				require.Len(t, td.expect, j)
+			}
+		})
+	}
+}
+
+func TestRecordErr(t *testing.T) {
+	var r gqlscan.Record
+	expect := "error at index 3 ('}'): unexpected token; expected argument name"
+	require.Equal(t, expect, r.Scan([]byte(`{a(}`)).Error())
+
+	var tokens []gqlscan.Token
+	err := r.Replay(func(i *gqlscan.Iterator) bool {
+		tokens = append(tokens, i.Token())
+		return false
+	})
+	require.Equal(t, expect, err.Error())
+	require.Equal(t, []gqlscan.Token{
+		gqlscan.TokenDefQry, gqlscan.TokenSet,
+		gqlscan.TokenField, gqlscan.TokenArgList,
+	}, tokens)
+}
+
+func TestRecordReplayCallbackErr(t *testing.T) {
+	var r gqlscan.Record
+	require.False(t, r.Scan([]byte(`{a b}`)).IsErr())
+	err := r.Replay(func(i *gqlscan.Iterator) bool {
+		return string(i.Value()) == "b"
+	})
+	require.Equal(t, "error at index 4 ('}'): callback function returned error",
+		err.Error())
+}
+
+func TestRecordReplayAbort(t *testing.T) {
+	var r gqlscan.Record
+	require.False(t, r.Scan([]byte(`{a} {b c}`)).IsErr())
+
+	errAbort := errors.New("abort")
+	var values []string
+	err := r.Replay(func(i *gqlscan.Iterator) bool {
+		values = append(values, string(i.Value()))
+		if string(i.Value()) == "b" {
+			i.Abort(errAbort)
+		}
+		return false
+	})
+	require.Equal(t, "error at index 6 (' '): callback function returned error: abort",
+		err.Error())
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+	require.Equal(t, errAbort, err.Err)
+	require.Equal(t, 1, err.DefinitionIndex)
+	require.Equal(t, 7, err.TokenOrdinal)
+	require.Equal(t, []string{"", "", "a", "", "", "", "", "b"}, values)
+
+	// The aborted iterator mustn't affect subsequent replays.
+	n := 0
+	err = r.Replay(func(i *gqlscan.Iterator) bool {
+		n++
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, r.Len(), n)
+}
@@ -0,0 +1,94 @@
+package gqlscan
+
+// ScanAllRecover is similar to ScanAll but doesn't stop at errors.
+// When an error occurs the scan continues at the beginning of the next
+// definition, which is the next definition keyword or selection set
+// outside of any selection set of the erroneous definition,
+// and fn is called for its tokens.
+// Returns all errors in order of their occurrence or nil if there were
+// no errors.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanAllRecover returns!
+func ScanAllRecover(str []byte, fn func(*Iterator)) (errs []Error) {
+	return ScanAllRecoverN(str, 0, fn)
+}
+
+// ScanAllRecoverN is similar to ScanAllRecover but stops after
+// max errors returning them. There's no limit if max < 1.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanAllRecoverN returns!
+func ScanAllRecoverN(str []byte, max int, fn func(*Iterator)) (errs []Error) {
+	// defs and tokens are the numbers of definitions and tokens
+	// scanned before start.
+	var defs, tokens int
+	for start := 0; ; {
+		defStart := start
+		err := scan(str, start, func(i *Iterator) bool {
+			switch i.token {
+			case TokenDefQry, TokenDefMut, TokenDefSub, TokenDefFrag:
+				defStart = i.defHead
+			}
+			fn(i)
+			return false
+		})
+		if !err.IsErr() {
+			return errs
+		}
+		err.DefinitionIndex += defs
+		err.TokenOrdinal += tokens
+		defs, tokens = err.DefinitionIndex+1, err.TokenOrdinal
+		errs = append(errs, err)
+		if err.Code == ErrUnexpEOF || len(errs) == max {
+			return errs
+		}
+		if start = nextDefinition(str, defStart, err.Index); start < 0 {
+			return errs
+		}
+	}
+}
+
+// nextDefinition returns the index of the first definition keyword
+// or opening brace following index after outside of any braces
+// counted from index start.
+// Returns -1 if there's none.
+func nextDefinition(str []byte, start, after int) int {
+	depth := 0
+	for x := start; x < len(str); {
+		switch c := str[x]; {
+		case c == '#' || c == '"':
+			x = skipLiteral(str, x)
+			continue
+		case c == '{':
+			if depth < 1 && x > after {
+				return x
+			}
+			depth++
+		case c == '}':
+			if depth > 0 {
+				depth--
+			}
+		case charClass[c]&className != 0:
+			e := x
+			for e < len(str) && charClass[str[e]]&className != 0 {
+				e++
+			}
+			if depth < 1 && x > after && isDefinitionKeyword(str[x:e]) {
+				return x
+			}
+			x = e
+			continue
+		}
+		x++
+	}
+	return -1
+}
+
+func isDefinitionKeyword(n []byte) bool {
+	switch string(n) {
+	case "query", "mutation", "subscription", "fragment":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanAllRecover(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			errs := gqlscan.ScanAllRecover(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) {
+					require.Equal(t, td.expect[j].Type, i.Token())
+					require.Equal(t, td.expect[j].Value, string(i.Value()))
+					j++
+				},
+			)
+			require.Nil(t, errs)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanAllRecoverErrs(t *testing.T) {
+	for _, td := range []struct {
+		decl         string
+		input        string
+		expectErrs   []string
+		expectFields []string
+	}{
+		{decl(1), `{a(}`, []string{
+			"error at index 3 ('}'): unexpected token; expected argument name",
+		}, []string{"a"}},
+		{decl(1), `{a(} {b} query Q {c{(}} fragment F on T {d}`, []string{
+			"error at index 3 ('}'): unexpected token; expected argument name",
+			"error at index 20 ('('): unexpected token; " +
+				"expected field name or alias",
+		}, []string{"a", "b", "c", "d"}},
+		{decl(1), `{a(x:"query {", y:"""}"""} query {b}`, []string{
+			"error at index 25 ('}'): unexpected token; " +
+				"expected argument name",
+		}, []string{"a", "b"}},
+		{decl(1), "xyz # {\n {a} mutation M {b(x:[1 2)}", []string{
+			"error at index 0 ('x'): unexpected token; expected definition",
+			"error at index 33 (')'): unexpected token; " +
+				"expected enum value",
+		}, []string{"a", "b"}},
+		{decl(1), `{a {query} {b}`, []string{
+			"error at index 11 ('{'): unexpected token; " +
+				"expected field name or alias",
+		}, []string{"a", "query"}},
+		{decl(1), `{a} {b(`, []string{
+			"error at index 7: unexpected end of file; " +
+				"expected argument name",
+		}, []string{"a", "b"}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var fields []string
+			errs := gqlscan.ScanAllRecover(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) {
+					if i.Token() == gqlscan.TokenField {
+						fields = append(fields, string(i.Value()))
+					}
+				},
+			)
+			var msgs []string
+			for _, e := range errs {
+				msgs = append(msgs, e.Error())
+			}
+			require.Equal(t, td.expectErrs, msgs)
+			require.Equal(t, td.expectFields, fields)
+		})
+	}
+}
+
+func TestScanAllRecoverN(t *testing.T) {
+	const input = `{a(} {b} query Q {c{(}} fragment F on T {d(} {e}`
+	for _, td := range []struct {
+		decl         string
+		max          int
+		expectErrs   []string
+		expectFields []string
+	}{
+		{decl(1), 0, []string{
+			"error at index 3 ('}'): unexpected token; expected argument name",
+			"error at index 20 ('('): unexpected token; " +
+				"expected field name or alias",
+			"error at index 43 ('}'): unexpected token; expected argument name",
+		}, []string{"a", "b", "c", "d", "e"}},
+		{decl(1), 1, []string{
+			"error at index 3 ('}'): unexpected token; expected argument name",
+		}, []string{"a"}},
+		{decl(1), 2, []string{
+			"error at index 3 ('}'): unexpected token; expected argument name",
+			"error at index 20 ('('): unexpected token; " +
+				"expected field name or alias",
+		}, []string{"a", "b", "c"}},
+		{decl(1), 3, []string{
+			"error at index 3 ('}'): unexpected token; expected argument name",
+			"error at index 20 ('('): unexpected token; " +
+				"expected field name or alias",
+			"error at index 43 ('}'): unexpected token; expected argument name",
+		}, []string{"a", "b", "c", "d"}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var fields []string
+			errs := gqlscan.ScanAllRecoverN(
+				[]byte(input), td.max,
+				func(i *gqlscan.Iterator) {
+					if i.Token() == gqlscan.TokenField {
+						fields = append(fields, string(i.Value()))
+					}
+				},
+			)
+			var msgs []string
+			for _, e := range errs {
+				msgs = append(msgs, e.Error())
+			}
+			require.Equal(t, td.expectErrs, msgs)
+			require.Equal(t, td.expectFields, fields)
+		})
+	}
+}
+
+func TestScanAllRecoverOrdinals(t *testing.T) {
+	src := []byte(`{a(} {b} query Q {c{(}} fragment F on T {d(} {e}`)
+	var actual [][2]int
+	for _, e := range gqlscan.ScanAllRecover(src, func(*gqlscan.Iterator) {}) {
+		actual = append(actual, [2]int{e.DefinitionIndex, e.TokenOrdinal})
+	}
+	require.Equal(t, [][2]int{{0, 4}, {2, 14}, {3, 20}}, actual)
+}
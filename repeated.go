@@ -0,0 +1,122 @@
+package gqlscan
+
+import "sort"
+
+// RepeatedField describes an unaliased field repeated
+// within a selection set.
+type RepeatedField struct {
+	// Field is the name of the field.
+	Field string
+
+	// Arguments is the source of the argument list of the field
+	// including the parentheses, which is empty if there's none.
+	Arguments string
+
+	// Count is the number of occurrences of the field.
+	Count int
+
+	// Index is the index of the name of the first occurrence.
+	Index int
+}
+
+// ScanRepeatedFields returns the unaliased fields of document src that
+// occur at least min times within a selection set with identical
+// argument lists in order of their first occurrence.
+// Argument lists are compared byte by byte and selections of inline
+// fragments count as selections of the selection set containing
+// the inline fragment. There's no minimum if min < 2.
+// Returns an error if src is invalid.
+func ScanRepeatedFields(src []byte, min int) ([]RepeatedField, Error) {
+	type scope struct {
+		fields []RepeatedField
+		// index maps the name followed by the arguments of every field
+		// to its index in fields.
+		index map[string]int
+	}
+	var (
+		repeated []RepeatedField
+		scopes   []*scope
+		// owned holds for every open selection set whether it
+		// owns its scope rather than sharing it with its parent.
+		owned  []bool
+		inline bool
+		// field is the last unaliased field until its arguments
+		// are scanned and args is the index of the opening parenthesis
+		// of its arguments or -1.
+		field *RepeatedField
+		args  = -1
+		alias bool
+	)
+	add := func(f RepeatedField) {
+		s := scopes[len(scopes)-1]
+		k := f.Field + f.Arguments
+		if x, ok := s.index[k]; ok {
+			s.fields[x].Count++
+			return
+		}
+		s.index[k] = len(s.fields)
+		s.fields = append(s.fields, f)
+	}
+	err := ScanAll(src, func(i *Iterator) {
+		if field != nil {
+			switch {
+			case i.token == TokenArgList && args < 0:
+				args = i.head
+				return
+			case args > -1 && i.token != TokenArgListEnd:
+				return
+			case args > -1:
+				field.Arguments = string(src[args : i.head+1])
+			}
+			add(*field)
+			field, args = nil, -1
+			if i.token == TokenArgListEnd {
+				return
+			}
+		}
+		switch i.token {
+		case TokenSet:
+			if l := len(scopes); inline && l > 0 {
+				scopes = append(scopes, scopes[l-1])
+			} else {
+				scopes = append(scopes, &scope{index: map[string]int{}})
+			}
+			owned = append(owned, !inline)
+			inline = false
+		case TokenSetEnd:
+			l := len(scopes) - 1
+			if owned[l] {
+				for _, f := range scopes[l].fields {
+					if f.Count >= min {
+						repeated = append(repeated, f)
+					}
+				}
+			}
+			scopes, owned = scopes[:l], owned[:l]
+		case TokenFragInline:
+			inline = true
+		case TokenNamedSpread:
+			inline = false
+		case TokenFieldAlias:
+			alias = true
+		case TokenField:
+			inline = false
+			if alias {
+				alias = false
+				break
+			}
+			field = &RepeatedField{
+				Field: string(i.Value()),
+				Count: 1,
+				Index: i.tail,
+			}
+		}
+	})
+	if err.IsErr() {
+		return nil, err
+	}
+	sort.Slice(repeated, func(a, b int) bool {
+		return repeated[a].Index < repeated[b].Index
+	})
+	return repeated, Error{}
+}
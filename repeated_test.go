@@ -0,0 +1,52 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanRepeatedFields(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		min    int
+		expect []gqlscan.RepeatedField
+		err    string
+	}{
+		{decl(1), `{a b c}`, 2, nil, ""},
+		{decl(1), `{a b c}`, 0, []gqlscan.RepeatedField{
+			{Field: "a", Count: 1, Index: 1},
+			{Field: "b", Count: 1, Index: 3},
+			{Field: "c", Count: 1, Index: 5},
+		}, ""},
+		{decl(1), `{a x: a a a(x: 1) a(x:1) a(x: 1) @d(y: 2)}`, 2,
+			[]gqlscan.RepeatedField{
+				{Field: "a", Count: 2, Index: 1},
+				{Field: "a", Arguments: "(x: 1)", Count: 2, Index: 10},
+			}, ""},
+		{decl(1), `{b {c c c} a ... {a ... on T {a}} d {a}}`, 3,
+			[]gqlscan.RepeatedField{
+				{Field: "c", Count: 3, Index: 4},
+				{Field: "a", Count: 3, Index: 11},
+			}, ""},
+		{decl(1), `{a {b} a {c} ...F ...F}`, 2,
+			[]gqlscan.RepeatedField{
+				{Field: "a", Count: 2, Index: 1},
+			}, ""},
+		{decl(1), `{a(x: {y: [1]}) a(x: {y: [1]})}`, 2,
+			[]gqlscan.RepeatedField{
+				{Field: "a", Arguments: "(x: {y: [1]})", Count: 2, Index: 1},
+			}, ""},
+		{decl(1), `{a a`, 2, nil,
+			"error at index 4: unexpected end of file; expected field name or alias"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			r, err := gqlscan.ScanRepeatedFields([]byte(td.input), td.min)
+			require.Equal(t, td.err, err.Error())
+			require.Equal(t, td.expect, r)
+		})
+	}
+}
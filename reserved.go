@@ -0,0 +1,41 @@
+package gqlscan
+
+import "bytes"
+
+// ScanNoReservedNames is similar to Scan but returns an error with
+// code ErrReservedName at the index of the first name of a field,
+// argument, fragment or variable starting with "__" except for
+// the introspection fields __typename, __schema and __type.
+// fn isn't called for the token of the reserved name.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanNoReservedNames returns!
+func ScanNoReservedNames(str []byte, fn func(*Iterator) (err bool)) Error {
+	return ScanWithOptions(str, fn, WithNoReservedNames())
+}
+
+// WithNoReservedNames makes the scan fail with ErrReservedName at the
+// first name of a field, argument, fragment or variable starting with
+// "__" except for the introspection fields __typename, __schema and
+// __type similar to ScanNoReservedNames.
+func WithNoReservedNames() Option {
+	return func(o *options) { o.noReserved = true }
+}
+
+// isReservedName returns true if the value v of token t
+// is a reserved name.
+func isReservedName(t Token, v []byte) bool {
+	switch t {
+	case TokenField:
+		if len(v) < 2 || v[0] != '_' || v[1] != '_' {
+			return false
+		}
+		return !bytes.Equal(v, []byte("__typename")) &&
+			!bytes.Equal(v, []byte("__schema")) &&
+			!bytes.Equal(v, []byte("__type"))
+	case TokenArgName, TokenFragName, TokenNamedSpread,
+		TokenVarName, TokenVarRef:
+		return len(v) > 1 && v[0] == '_' && v[1] == '_'
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanNoReservedNames(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `{__typename __schema { types { name } } __type(name: "T") { kind }}`, ""},
+		{decl(1), `{a_ _a __Type: a ... on __Type { b }}`, ""},
+		{decl(1), `query($v: __T) { a(b: __ENUM, c: {__d: 1}) }`, ""},
+		{decl(1), `{__ }`,
+			"error at index 1 ('_'): reserved name"},
+		{decl(1), `{ a { __secret } }`,
+			"error at index 6 ('_'): reserved name"},
+		{decl(1), `{ a(__b: 1) }`,
+			"error at index 4 ('_'): reserved name"},
+		{decl(1), `{ ...__F }`,
+			"error at index 5 ('_'): reserved name"},
+		{decl(1), `fragment __F on T { a }`,
+			"error at index 9 ('_'): reserved name"},
+		{decl(1), `query($__v: Int) { a }`,
+			"error at index 7 ('_'): reserved name"},
+		{decl(1), `{ a(b: $__v) }`,
+			"error at index 8 ('_'): reserved name"},
+		{decl(1), `{ __x(`,
+			"error at index 2 ('_'): reserved name"},
+		{decl(1), `{ a(`,
+			"error at index 4: unexpected end of file; expected argument name"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanNoReservedNames(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) bool { return false },
+			)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
+
+func TestWithNoReservedNames(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		opts   []gqlscan.Option
+		expect string
+	}{
+		{decl(1), `{__typename __schema { types { name } } __type(name: "T") { kind }}`,
+			nil, ""},
+		{decl(1), `{ a { __secret } }`, nil,
+			"error at index 6 ('_'): reserved name"},
+		{decl(1), `query($__v: Int) { a }`, nil,
+			"error at index 7 ('_'): reserved name"},
+		{decl(1), `{ a } { b(__c: 1) }`, nil,
+			"error at index 10 ('_'): reserved name"},
+		{decl(1), `{ a { __secret } }`,
+			[]gqlscan.Option{gqlscan.WithMaxSelectionDepth(1)},
+			"error at index 4 ('{'): selection set nesting limit exceeded: " +
+				"got 2, limit 1"},
+		{decl(1), `{ a(__b: 1) }`,
+			[]gqlscan.Option{gqlscan.WithUTF8Validation()},
+			"error at index 4 ('_'): reserved name"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			called := 0
+			err := gqlscan.ScanWithOptions(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) bool {
+					called++
+					return false
+				},
+				append(td.opts, gqlscan.WithNoReservedNames())...,
+			)
+			require.Equal(t, td.expect, err.Error())
+			if err.Code == gqlscan.ErrReservedName {
+				require.Equal(t, called, err.TokenOrdinal)
+			}
+		})
+	}
+}
+
+func TestNoReservedNamesSkip(t *testing.T) {
+	called := 0
+	err := gqlscan.ScanWithOptions(
+		[]byte(`{a{__secret}}`),
+		func(i *gqlscan.Iterator) bool {
+			called++
+			i.SkipSelectionSet()
+			return false
+		},
+		gqlscan.WithNoReservedNames(),
+	)
+	require.Equal(t, "error at index 3 ('_'): reserved name", err.Error())
+	require.Equal(t, 2, called)
+}
+
+func TestScanNoReservedNamesErrDetails(t *testing.T) {
+	err := gqlscan.ScanNoReservedNames(
+		[]byte(`{a} {b{__c}}`),
+		func(i *gqlscan.Iterator) bool { return false },
+	)
+	require.Equal(t, gqlscan.ErrReservedName, err.Code)
+	require.Equal(t, 1, err.DefinitionIndex)
+	require.Equal(t, 9, err.TokenOrdinal)
+	require.Equal(t, gqlscan.TokenDefQry, err.Trail.Definition)
+	require.Equal(t, gqlscan.Span{Tail: 5, Head: 6}, err.Trail.Field)
+}
@@ -0,0 +1,56 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+const scanAtPrefix = `POST {"query":`
+
+func TestScanAt(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			err := gqlscan.ScanAt(
+				[]byte(scanAtPrefix+td.input), len(scanAtPrefix),
+				func(i *gqlscan.Iterator) bool {
+					require.True(t, j < len(td.expect))
+					require.Equal(t, td.expect[j].Type, i.Token())
+					require.Equal(t, td.expect[j].Value, string(i.Value()))
+					j++
+					return false
+				},
+			)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanAtErr(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range testdataErr {
+		t.Run(td.decl, func(t *testing.T) {
+			expect := gqlscan.Scan([]byte(td.input), noop)
+			err := gqlscan.ScanAt(
+				[]byte(scanAtPrefix+td.input), len(scanAtPrefix), noop,
+			)
+			require.Equal(t, expect.Index+len(scanAtPrefix), err.Index)
+			require.Equal(t, expect.AtIndex, err.AtIndex)
+			require.Equal(t, expect.Code, err.Code)
+			require.Equal(t, expect.Expectation, err.Expectation)
+		})
+	}
+}
+
+func TestScanAtOutOfRange(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	require.Panics(t, func() { gqlscan.ScanAt([]byte(`{a}`), -1, noop) })
+	require.Panics(t, func() { gqlscan.ScanAt([]byte(`{a}`), 4, noop) })
+	require.Equal(t,
+		"error at index 3: unexpected end of file; expected definition",
+		gqlscan.ScanAt([]byte(`{a}`), 3, noop).Error())
+}
@@ -0,0 +1,19 @@
+package gqlscan
+
+// ScanErr is similar to Scan but fn returns an error that stops
+// the scan if it's not nil. The returned error has code ErrCallbackFn
+// and holds the error returned by fn in field Err.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanErr returns!
+func ScanErr(str []byte, fn func(*Iterator) error) Error {
+	var fnErr error
+	err := scan(str, 0, func(i *Iterator) bool {
+		fnErr = fn(i)
+		return fnErr != nil
+	})
+	if err.Code == ErrCallbackFn && fnErr != nil {
+		err.Err = fnErr
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package gqlscan_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errForbiddenField = errors.New("forbidden field")
+
+func TestScanErrCallback(t *testing.T) {
+	err := gqlscan.ScanErr(
+		[]byte(`{a secret b}`),
+		func(i *gqlscan.Iterator) error {
+			if string(i.Value()) == "secret" {
+				return fmt.Errorf("%w %q", errForbiddenField, i.Value())
+			}
+			return nil
+		},
+	)
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+	require.True(t, errors.Is(err, errForbiddenField))
+	require.Equal(t, "error at index 9 (' '): "+
+		"callback function returned error; "+
+		"expected field name or alias: "+
+		`forbidden field "secret"`, err.Error())
+}
+
+func TestScanErrNil(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			err := gqlscan.ScanErr(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) error {
+					require.Equal(t, td.expect[j].Type, i.Token())
+					j++
+					return nil
+				},
+			)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Nil(t, err.Err)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanErrSyntax(t *testing.T) {
+	err := gqlscan.ScanErr([]byte(`{a(}`), func(*gqlscan.Iterator) error {
+		return nil
+	})
+	require.Equal(t, gqlscan.ErrUnexpToken, err.Code)
+	require.Nil(t, errors.Unwrap(err))
+}
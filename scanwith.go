@@ -0,0 +1,14 @@
+package gqlscan
+
+// ScanWith is similar to Scan but passes ctx to fn which allows
+// passing state to a function that isn't a closure.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanWith returns!
+func ScanWith[T any](
+	str []byte,
+	ctx T,
+	fn func(ctx T, i *Iterator) (err bool),
+) Error {
+	return scan(str, 0, func(i *Iterator) bool { return fn(ctx, i) })
+}
@@ -0,0 +1,61 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+type scanWithState struct {
+	t      *testing.T
+	expect []Expect
+	j      int
+}
+
+func scanWithCheck(s *scanWithState, i *gqlscan.Iterator) bool {
+	require.True(s.t, s.j < len(s.expect))
+	require.Equal(s.t, s.expect[s.j].Type, i.Token())
+	require.Equal(s.t, s.expect[s.j].Value, string(i.Value()))
+	s.j++
+	return false
+}
+
+func TestScanWith(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			s := &scanWithState{t: t, expect: td.expect}
+			err := gqlscan.ScanWith([]byte(td.input), s, scanWithCheck)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, s.j)
+		})
+	}
+}
+
+type scanWithCounter struct{ fields int }
+
+func scanWithCount(c *scanWithCounter, i *gqlscan.Iterator) bool {
+	if i.Token() == gqlscan.TokenField {
+		c.fields++
+	}
+	return c.fields > 2
+}
+
+func TestScanWithErr(t *testing.T) {
+	var c scanWithCounter
+	err := gqlscan.ScanWith([]byte(`{a b c d}`), &c, scanWithCount)
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+	require.Equal(t, 3, c.fields)
+}
+
+func TestScanWithAllocs(t *testing.T) {
+	src := []byte(`query Q($a: [[ID!]!]!) { f(a: $a) { g h } }`)
+	var c scanWithCounter
+	allocs := testing.AllocsPerRun(100, func() {
+		c.fields = 0
+		gqlscan.ScanWith(src, &c, scanWithCount)
+	})
+	require.Zero(t, allocs)
+	require.Equal(t, 3, c.fields)
+}
@@ -0,0 +1,19 @@
+//go:build go1.23
+
+package gqlscan
+
+import "iter"
+
+// Tokens returns a sequence of the tokens of str and their raw values.
+// The sequence ends after the last token or before the first
+// syntax error, use Scan to get the error.
+//
+// WARNING: The values refer to the same underlying memory
+// as str, copy them or use with caution!
+func Tokens(str []byte) iter.Seq2[Token, []byte] {
+	return func(yield func(Token, []byte) bool) {
+		Scan(str, func(i *Iterator) bool {
+			return !yield(i.Token(), i.Value())
+		})
+	}
+}
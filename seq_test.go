@@ -0,0 +1,49 @@
+//go:build go1.23
+
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokens(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			for tok, val := range gqlscan.Tokens([]byte(td.input)) {
+				require.Equal(t, td.expect[j].Type, tok)
+				require.Equal(t, td.expect[j].Value, string(val))
+				j++
+			}
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestTokensBreak(t *testing.T) {
+	var tokens []gqlscan.Token
+	for tok := range gqlscan.Tokens([]byte(`{a b c}`)) {
+		tokens = append(tokens, tok)
+		if tok == gqlscan.TokenField {
+			break
+		}
+	}
+	require.Equal(t, []gqlscan.Token{
+		gqlscan.TokenDefQry, gqlscan.TokenSet, gqlscan.TokenField,
+	}, tokens)
+}
+
+func TestTokensErr(t *testing.T) {
+	var tokens []gqlscan.Token
+	for tok := range gqlscan.Tokens([]byte(`{a(}`)) {
+		tokens = append(tokens, tok)
+	}
+	require.Equal(t, []gqlscan.Token{
+		gqlscan.TokenDefQry, gqlscan.TokenSet,
+		gqlscan.TokenField, gqlscan.TokenArgList,
+	}, tokens)
+}
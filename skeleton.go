@@ -0,0 +1,192 @@
+package gqlscan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AppendResponseSkeleton appends the skeleton of the JSON response
+// data of the operation with the given name in document src to dst
+// and returns the extended buffer.
+// If operationName is empty then src must contain exactly one operation.
+// Every field is represented by its alias or name as key, fields with
+// selection sets are represented by objects and leaf fields by null,
+// fragments are expanded and fields with identical keys are merged.
+// Since the scanner knows no schema, lists are represented as objects.
+func AppendResponseSkeleton(
+	dst, src []byte,
+	operationName string,
+) ([]byte, error) {
+	var s skeleton
+	if err := s.scan(src); err.IsErr() {
+		return dst, err
+	}
+	opr := -1
+	for _, o := range s.operations {
+		if operationName != "" && s.nodes[o].key != operationName {
+			continue
+		}
+		if opr > -1 {
+			return dst, errors.New("operation name required")
+		}
+		opr = o
+	}
+	if opr < 0 {
+		if operationName == "" {
+			return dst, errors.New("no operation")
+		}
+		return dst, fmt.Errorf("operation %q not found", operationName)
+	}
+	if err := s.checkSpreads(opr, map[int]bool{}); err != nil {
+		return dst, err
+	}
+	return s.appendObject(dst, s.nodes[opr].children), nil
+}
+
+type skeletonKind int8
+
+const (
+	_ skeletonKind = iota
+	skeletonDef
+	skeletonField
+	skeletonSpread
+	skeletonInline
+)
+
+// skeletonNode is either a definition, a field
+// or a fragment spread or inline fragment.
+type skeletonNode struct {
+	kind skeletonKind
+
+	// key is the name of definitions and spreads
+	// and the alias or name of fields.
+	key      string
+	children []int
+}
+
+type skeleton struct {
+	nodes      []skeletonNode
+	operations []int
+	fragments  map[string]int
+}
+
+func (s *skeleton) scan(src []byte) Error {
+	s.fragments = map[string]int{}
+	var (
+		// stack holds the nodes whose selections are scanned.
+		stack []int
+		// last is the node of the last selection.
+		last  int
+		alias string
+	)
+	add := func(kind skeletonKind, key string) {
+		last = len(s.nodes)
+		s.nodes = append(s.nodes, skeletonNode{kind: kind, key: key})
+		if l := len(stack); l > 0 {
+			p := stack[l-1]
+			s.nodes[p].children = append(s.nodes[p].children, last)
+		}
+	}
+	return ScanAll(src, func(i *Iterator) {
+		switch i.Token() {
+		case TokenDefQry, TokenDefMut, TokenDefSub:
+			add(skeletonDef, "")
+			s.operations = append(s.operations, last)
+		case TokenDefFrag:
+			add(skeletonDef, "")
+		case TokenOprName:
+			s.nodes[last].key = string(i.Value())
+		case TokenFragName:
+			s.nodes[last].key = string(i.Value())
+			s.fragments[s.nodes[last].key] = last
+		case TokenFieldAlias:
+			alias = string(i.Value())
+		case TokenField:
+			if alias == "" {
+				alias = string(i.Value())
+			}
+			add(skeletonField, alias)
+			alias = ""
+		case TokenNamedSpread:
+			add(skeletonSpread, string(i.Value()))
+		case TokenFragInline:
+			add(skeletonInline, "")
+		case TokenSet:
+			stack = append(stack, last)
+		case TokenSetEnd:
+			stack = stack[:len(stack)-1]
+		}
+	})
+}
+
+// checkSpreads returns an error if a spread in the subtree of node n
+// refers to an undefined fragment or a fragment spreading itself.
+// path holds the fragments whose subtrees are being checked.
+func (s *skeleton) checkSpreads(n int, path map[int]bool) error {
+	for _, c := range s.nodes[n].children {
+		if s.nodes[c].kind != skeletonSpread {
+			if err := s.checkSpreads(c, path); err != nil {
+				return err
+			}
+			continue
+		}
+		name := s.nodes[c].key
+		f, ok := s.fragments[name]
+		if !ok {
+			return fmt.Errorf("fragment %q not found", name)
+		}
+		if path[f] {
+			return fmt.Errorf("fragment %q spreads itself", name)
+		}
+		path[f] = true
+		err := s.checkSpreads(f, path)
+		delete(path, f)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendObject appends the object of the merged selections sels.
+// The spreads must be checked using checkSpreads before.
+func (s *skeleton) appendObject(dst []byte, sels []int) []byte {
+	var (
+		keys   []string
+		fields = map[string][]int{}
+	)
+	var collect func(sels []int)
+	collect = func(sels []int) {
+		for _, x := range sels {
+			switch n := s.nodes[x]; n.kind {
+			case skeletonField:
+				c, ok := fields[n.key]
+				if !ok {
+					keys = append(keys, n.key)
+				}
+				fields[n.key] = append(c, n.children...)
+			case skeletonInline:
+				collect(n.children)
+			case skeletonSpread:
+				collect(s.nodes[s.fragments[n.key]].children)
+			}
+		}
+	}
+	collect(sels)
+
+	dst = append(dst, '{')
+	for x, k := range keys {
+		if x > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, '"')
+		dst = append(dst, k...)
+		dst = append(dst, `":`...)
+		if c := fields[k]; len(c) > 0 {
+			dst = s.appendObject(dst, c)
+			continue
+		}
+		dst = append(dst, "null"...)
+	}
+	return append(dst, '}')
+}
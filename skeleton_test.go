@@ -0,0 +1,74 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendResponseSkeleton(t *testing.T) {
+	for _, td := range []struct {
+		decl      string
+		input     string
+		operation string
+		expect    string
+	}{
+		{decl(1), `{a}`, "", `{"a":null}`},
+		{decl(1), `query Q($v: Int) {
+			me: user(id: $v) @include(if: true) {
+				name
+				...UserFields
+				... on Admin { role friends { name } }
+				friends { id }
+			}
+			__typename
+		}
+		fragment UserFields on User { id name friends { avatar(size: 2) } }`,
+			"", `{"me":{"name":null,"id":null,` +
+				`"friends":{"avatar":null,"name":null,"id":null},` +
+				`"role":null},"__typename":null}`},
+		{decl(1), `query A { a } mutation B { b { c } }`,
+			"B", `{"b":{"c":null}}`},
+		{decl(1), `{ ...F a { ...F } } fragment F on T { b }`,
+			"", `{"b":null,"a":{"b":null}}`},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			a, err := gqlscan.AppendResponseSkeleton(
+				[]byte("prefix:"), []byte(td.input), td.operation,
+			)
+			require.NoError(t, err)
+			require.Equal(t, "prefix:"+td.expect, string(a))
+		})
+	}
+}
+
+func TestAppendResponseSkeletonErr(t *testing.T) {
+	for _, td := range []struct {
+		decl      string
+		input     string
+		operation string
+		expect    string
+	}{
+		{decl(1), `{a`, "",
+			"error at index 2: unexpected end of file; " +
+				"expected field name or alias"},
+		{decl(1), `fragment F on T { a }`, "", "no operation"},
+		{decl(1), `query A { a } query B { b }`, "", "operation name required"},
+		{decl(1), `query A { a }`, "B", `operation "B" not found`},
+		{decl(1), `{ a { ...F } }`, "", `fragment "F" not found`},
+		{decl(1), `{ ...F } fragment F on T { a { ...G } }
+			fragment G on T { ...F }`, "", `fragment "F" spreads itself`},
+		{decl(1), `{ ...F } fragment F on T { ... on T { ...F } }`,
+			"", `fragment "F" spreads itself`},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			a, err := gqlscan.AppendResponseSkeleton(
+				[]byte("prefix:"), []byte(td.input), td.operation,
+			)
+			require.Equal(t, td.expect, err.Error())
+			require.Equal(t, "prefix:", string(a))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package gqlscan
+
+// SkipSelectionSet makes the scan not call fn for the tokens of
+// the selection set of the current TokenSet except for its
+// TokenSetEnd. The skipped tokens are still scanned and
+// errors are still returned.
+// Has no effect if the current token isn't TokenSet.
+func (i *Iterator) SkipSelectionSet() {
+	if i.token == TokenSet {
+		i.skipEnd, i.skipDepth = TokenSetEnd, i.levelSel+1
+	}
+}
+
+// SkipValue makes the scan not call fn for the tokens of the array
+// or object of the current TokenArr or TokenObj except for its
+// TokenArrEnd or TokenObjEnd. The skipped tokens are still scanned
+// and errors are still returned.
+// Has no effect if the current token is neither TokenArr nor TokenObj.
+func (i *Iterator) SkipValue() {
+	switch i.token {
+	case TokenArr:
+		i.skipEnd, i.skipDepth = TokenArrEnd, i.stackLen()
+	case TokenObj:
+		i.skipEnd, i.skipDepth = TokenObjEnd, i.stackLen()
+	}
+}
+
+// skipping returns true if fn must not be called for the current token
+// because it's skipped, otherwise stops skipping if the current token
+// is the end token of the skipped selection set or value.
+func (i *Iterator) skipping() bool {
+	if i.token != i.skipEnd {
+		return true
+	}
+	depth := i.stackLen()
+	if i.token == TokenSetEnd {
+		depth = i.levelSel
+	}
+	if depth != i.skipDepth {
+		return true
+	}
+	i.skipEnd = 0
+	return false
+}
@@ -0,0 +1,89 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkip(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect []string
+	}{
+		{decl(1),
+			`{a {b {c} d} e}`,
+			[]string{"query", "{", "a", "{", "}", "e", "}", "end"}},
+		{decl(1),
+			`query {a(x: [1, [2, {y: 3}], []], z: {w: {v: 4}}) {b} c(x: [])}`,
+			[]string{
+				"query", "{", "a", "(", "x", "[", "]", "z", "{", "}", ")",
+				"{", "}", "c", "(", "x", "[", "]", ")", "}", "end",
+			}},
+		{decl(1),
+			`query($v: [Int] = [1 2]) {a(x: {y: [3]}) {b}}`,
+			[]string{
+				"query", "(", "v", "[", "Int", "]", "[", "]", ")",
+				"{", "a", "(", "x", "{", "}", ")", "{", "}", "}", "end",
+			}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var actual []string
+			err := gqlscan.Scan([]byte(td.input), func(i *gqlscan.Iterator) bool {
+				actual = append(actual, skipTestToken(i))
+				if i.LevelSelect() > 0 {
+					i.SkipSelectionSet()
+				}
+				i.SkipValue()
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, td.expect, actual)
+		})
+	}
+}
+
+func TestSkipErr(t *testing.T) {
+	var actual []string
+	err := gqlscan.ScanAll([]byte(`{a {b(x: [1, {y: }])}}`), func(i *gqlscan.Iterator) {
+		actual = append(actual, skipTestToken(i))
+		i.SkipSelectionSet()
+	})
+	require.Equal(t,
+		"error at index 17 ('}'): unexpected token; expected enum value",
+		err.Error())
+	require.Equal(t, []string{"query", "{"}, actual)
+
+	// The skip state isn't kept across scans.
+	actual = nil
+	err = gqlscan.ScanAll([]byte(`{a}`), func(i *gqlscan.Iterator) {
+		actual = append(actual, skipTestToken(i))
+	})
+	require.False(t, err.IsErr())
+	require.Equal(t, []string{"query", "{", "a", "}", "end"}, actual)
+}
+
+func skipTestToken(i *gqlscan.Iterator) string {
+	switch i.Token() {
+	case gqlscan.TokenDefQry:
+		return "query"
+	case gqlscan.TokenDefEnd:
+		return "end"
+	case gqlscan.TokenSet, gqlscan.TokenObj:
+		return "{"
+	case gqlscan.TokenSetEnd, gqlscan.TokenObjEnd:
+		return "}"
+	case gqlscan.TokenArr, gqlscan.TokenVarTypeArr:
+		return "["
+	case gqlscan.TokenArrEnd, gqlscan.TokenVarTypeArrEnd:
+		return "]"
+	case gqlscan.TokenArgList, gqlscan.TokenVarList:
+		return "("
+	case gqlscan.TokenArgListEnd, gqlscan.TokenVarListEnd:
+		return ")"
+	}
+	return string(i.Value())
+}
@@ -0,0 +1,90 @@
+//go:build go1.21
+
+package gqlscan
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// SlogLogger logs scan failures using log/slog.
+// The zero value is not usable, use NewSlogLogger instead.
+// SlogLogger is safe for concurrent use.
+type SlogLogger struct {
+	logger *slog.Logger
+
+	// Level is the level scan failures are logged at.
+	Level slog.Level
+
+	// TraceEvery enables token traces for every TraceEvery-th scan
+	// logging each token at slog.LevelDebug.
+	// Token traces are disabled if TraceEvery < 1.
+	TraceEvery int64
+
+	scans atomic.Int64
+}
+
+// NewSlogLogger creates a new logger logging scan failures
+// to l at slog.LevelWarn with token traces disabled.
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	return &SlogLogger{logger: l, Level: slog.LevelWarn}
+}
+
+// WithLogger makes the scan log its failure to l
+// and trace its tokens if enabled.
+func WithLogger(l *SlogLogger) Option {
+	return func(o *options) { o.logger = l }
+}
+
+// Scan is similar to gqlscan.Scan but logs the error if the scan fails.
+// It's a shorthand for ScanWithOptions with WithLogger(l).
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after Scan returns!
+func (l *SlogLogger) Scan(
+	str []byte,
+	fn func(*Iterator) (err bool),
+) Error {
+	return ScanWithOptions(str, fn, WithLogger(l))
+}
+
+func (l *SlogLogger) trace(
+	fn func(*Iterator) (err bool),
+) func(*Iterator) (err bool) {
+	scan := l.scans.Add(1)
+	if l.TraceEvery < 1 || scan%l.TraceEvery != 0 {
+		return fn
+	}
+	return func(i *Iterator) bool {
+		l.logger.LogAttrs(context.Background(), slog.LevelDebug, "token",
+			slog.Int64("scan", scan),
+			slog.String("token", i.Token().String()),
+			slog.String("value", string(i.Value())),
+			slog.Int("index", i.IndexHead()),
+		)
+		return fn(i)
+	}
+}
+
+func (l *SlogLogger) log(str []byte, err Error) {
+	line, column := LineColumn(str, err.Index)
+	attrs := []slog.Attr{
+		slog.String("code", err.Code.String()),
+		slog.Int("index", err.Index),
+		slog.Int("line", line),
+		slog.Int("column", column),
+	}
+	if err.Expectation != 0 {
+		attrs = append(attrs,
+			slog.String("expected", err.Expectation.String()))
+	}
+	if n := err.Trail.DefinitionName; !n.IsEmpty() {
+		key := "operation"
+		if err.Trail.Definition == TokenDefFrag {
+			key = "fragment"
+		}
+		attrs = append(attrs, slog.String(key, string(str[n.Tail:n.Head])))
+	}
+	l.logger.LogAttrs(context.Background(), l.Level, err.Error(), attrs...)
+}
@@ -0,0 +1,88 @@
+//go:build go1.21
+
+package gqlscan_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogLogger(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), "{a}", ""},
+		{decl(1), "query Q {\n  a(\n}",
+			`{"level":"WARN","msg":"error at index 15 ('}'): ` +
+				`unexpected token; expected argument name",` +
+				`"code":"unexpected token","index":15,"line":3,"column":1,` +
+				`"expected":"argument name","operation":"Q"}`},
+		{decl(1), "fragment F on T { a(b: \"ä\") b( }",
+			`{"level":"WARN","msg":"error at index 32 ('}'): ` +
+				`unexpected token; expected argument name",` +
+				`"code":"unexpected token","index":32,"line":1,"column":32,` +
+				`"expected":"argument name","fragment":"F"}`},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var b bytes.Buffer
+			l := gqlscan.NewSlogLogger(slog.New(newTestHandler(&b)))
+			l.Scan([]byte(td.input), noop)
+			require.Equal(t, td.expect, strings.TrimSpace(b.String()))
+		})
+	}
+}
+
+func TestSlogLoggerTrace(t *testing.T) {
+	var b bytes.Buffer
+	l := gqlscan.NewSlogLogger(slog.New(newTestHandler(&b)))
+	l.TraceEvery = 2
+
+	noop := func(*gqlscan.Iterator) bool { return false }
+	l.Scan([]byte(`{a}`), noop)
+	require.Zero(t, b.Len())
+
+	l.Scan([]byte(`{b}`), noop)
+	require.Equal(t, ""+
+		`{"level":"DEBUG","msg":"token","scan":2,"token":"query definition","value":"","index":0}`+"\n"+
+		`{"level":"DEBUG","msg":"token","scan":2,"token":"selection set","value":"","index":0}`+"\n"+
+		`{"level":"DEBUG","msg":"token","scan":2,"token":"field","value":"b","index":2}`+"\n"+
+		`{"level":"DEBUG","msg":"token","scan":2,"token":"selection set end","value":"","index":2}`+"\n"+
+		`{"level":"DEBUG","msg":"token","scan":2,"token":"definition end","value":"","index":2}`+"\n",
+		b.String())
+}
+
+func newTestHandler(b *bytes.Buffer) slog.Handler {
+	return slog.NewJSONHandler(b, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) < 1 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+func TestWithLogger(t *testing.T) {
+	var b bytes.Buffer
+	l := gqlscan.NewSlogLogger(slog.New(newTestHandler(&b)))
+	err := gqlscan.ScanWithOptions(
+		[]byte("{a{b}}"),
+		func(*gqlscan.Iterator) bool { return false },
+		gqlscan.WithLogger(l),
+		gqlscan.WithMaxSelectionDepth(1),
+	)
+	require.Equal(t, gqlscan.ErrSelTooDeep, err.Code)
+	require.Equal(t, `{"level":"WARN","msg":"`+err.Error()+`",`+
+		`"code":"`+err.Code.String()+`","index":2,"line":1,"column":3}`,
+		strings.TrimSpace(b.String()))
+}
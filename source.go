@@ -0,0 +1,97 @@
+package gqlscan
+
+import "sync"
+
+// Source provides the document to scan.
+type Source interface {
+	// Document returns the contiguous document.
+	// buffer points to a reusable buffer the source may assemble
+	// the document in, a grown buffer must be stored back to *buffer.
+	// The buffer is reused after the scan finished.
+	Document(buffer *[]byte) ([]byte, Error)
+}
+
+// BytesSource is a contiguous document.
+type BytesSource []byte
+
+// Document implements Source.
+func (s BytesSource) Document(*[]byte) ([]byte, Error) {
+	return s, Error{}
+}
+
+// SegmentsSource is a document split into segments
+// that are concatenated before the scan.
+type SegmentsSource [][]byte
+
+// Document implements Source.
+func (s SegmentsSource) Document(buffer *[]byte) ([]byte, Error) {
+	if len(s) == 1 {
+		return s[0], Error{}
+	}
+	b := (*buffer)[:0]
+	for _, s := range s {
+		b = append(b, s...)
+	}
+	*buffer = b
+	return b, Error{}
+}
+
+// JSONSource is a JSON string literal holding the document,
+// see ScanJSON. The whole literal is decoded into the buffer
+// before the document is scanned and isn't modified.
+type JSONSource []byte
+
+// Document implements Source.
+func (s JSONSource) Document(buffer *[]byte) ([]byte, Error) {
+	return jsonSource{str: s}.Document(buffer)
+}
+
+// jsonSource is a JSONSource recording the positions of
+// the escape sequences in m if m != nil, see ScanJSONIndexed.
+type jsonSource struct {
+	str []byte
+	m   *JSONIndexMap
+}
+
+// Document implements Source.
+func (s jsonSource) Document(buffer *[]byte) ([]byte, Error) {
+	b, err := appendUnescapedJSON((*buffer)[:0], s.str, s.m)
+	if err.IsErr() {
+		return nil, err
+	}
+	*buffer = b
+	return b, Error{}
+}
+
+var sourceBufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 1024)
+		return &b
+	},
+}
+
+// ScanSource is similar to Scan but scans the document provided by s.
+// Errors returned by s are returned as is.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanSource returns!
+func ScanSource(s Source, fn func(*Iterator) (err bool)) Error {
+	return scanSource(s, nil, fn)
+}
+
+// scanSource is similar to ScanSource but sets check
+// as the check of the iterator if it's not nil.
+func scanSource(s Source, check, fn func(*Iterator) (err bool)) Error {
+	b := sourceBufferPool.Get().(*[]byte)
+	defer sourceBufferPool.Put(b)
+
+	str, err := s.Document(b)
+	if err.IsErr() {
+		return err
+	}
+	i := iteratorPool.Get().(*Iterator)
+	defer iteratorPool.Put(i)
+	i.str, i.check = str, check
+	defer func() { i.check = nil }()
+	return i.scan(0, fn)
+}
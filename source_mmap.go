@@ -0,0 +1,54 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package gqlscan
+
+import (
+	"os"
+	"syscall"
+)
+
+// MmapSource is a memory mapped document file.
+type MmapSource struct {
+	data []byte
+}
+
+// OpenMmap maps the file at path into memory.
+// The returned source must be closed when no longer needed.
+func OpenMmap(path string) (*MmapSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() < 1 {
+		return &MmapSource{}, nil
+	}
+	d, err := syscall.Mmap(
+		int(f.Fd()), 0, int(fi.Size()),
+		syscall.PROT_READ, syscall.MAP_SHARED,
+	)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return &MmapSource{data: d}, nil
+}
+
+// Document implements Source.
+func (s *MmapSource) Document(*[]byte) ([]byte, Error) {
+	return s.data, Error{}
+}
+
+// Close unmaps the file.
+func (s *MmapSource) Close() error {
+	if s.data == nil {
+		return nil
+	}
+	d := s.data
+	s.data = nil
+	return syscall.Munmap(d)
+}
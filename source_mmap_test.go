@@ -0,0 +1,43 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package gqlscan_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMmapSource(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "document.graphql")
+			require.NoError(t, os.WriteFile(p, []byte(td.input), 0o600))
+
+			s, err := gqlscan.OpenMmap(p)
+			require.NoError(t, err)
+			testScanSource(t, td, s)
+			require.NoError(t, s.Close())
+			require.NoError(t, s.Close())
+		})
+	}
+}
+
+func TestMmapSourceErr(t *testing.T) {
+	_, err := gqlscan.OpenMmap(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	p := filepath.Join(t.TempDir(), "empty.graphql")
+	require.NoError(t, os.WriteFile(p, nil, 0o600))
+	s, err := gqlscan.OpenMmap(p)
+	require.NoError(t, err)
+	require.Equal(t,
+		"error at index 0: unexpected end of file; expected definition",
+		gqlscan.ScanSource(s, func(*gqlscan.Iterator) bool { return false }).Error(),
+	)
+	require.NoError(t, s.Close())
+}
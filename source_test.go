@@ -0,0 +1,93 @@
+package gqlscan_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanSource(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j, err := json.Marshal(td.input)
+			require.NoError(t, err)
+
+			var segments gqlscan.SegmentsSource
+			for x := 0; x < len(td.input); x += 3 {
+				e := x + 3
+				if e > len(td.input) {
+					e = len(td.input)
+				}
+				segments = append(segments, []byte(td.input[x:e]))
+			}
+
+			for _, s := range []gqlscan.Source{
+				gqlscan.BytesSource(td.input),
+				segments,
+				gqlscan.SegmentsSource{[]byte(td.input)},
+				gqlscan.JSONSource(j),
+			} {
+				testScanSource(t, td, s)
+			}
+		})
+	}
+}
+
+func testScanSource(t *testing.T, td TestInput, s gqlscan.Source) {
+	j := 0
+	err := gqlscan.ScanSource(s, func(i *gqlscan.Iterator) bool {
+		require.Equal(t, td.expect[j].Type, i.Token(), "%T", s)
+		require.Equal(t, td.expect[j].Value, string(i.Value()), "%T", s)
+		j++
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error (%T): %s", s, err)
+	require.Len(t, td.expect, j, "%T", s)
+}
+
+func TestScanSourceErr(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range []struct {
+		decl   string
+		source gqlscan.Source
+		expect string
+	}{
+		{decl(1), gqlscan.BytesSource(`{a(}`),
+			"error at index 3 ('}'): unexpected token; expected argument name"},
+		{decl(1), gqlscan.SegmentsSource{[]byte(`{a`), []byte(`(}`)},
+			"error at index 3 ('}'): unexpected token; expected argument name"},
+		{decl(1), gqlscan.SegmentsSource{},
+			"error at index 0: unexpected end of file; expected definition"},
+		{decl(1), gqlscan.JSONSource(`"{a}`),
+			"error at index 4 (0x0): invalid JSON string"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanSource(td.source, noop)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
+
+func TestSourceDocumentBuffer(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		source gqlscan.Source
+		expect string
+	}{
+		{decl(1), gqlscan.SegmentsSource{[]byte(`{a `), []byte(`b}`)}, `{a b}`},
+		{decl(1), gqlscan.JSONSource(`"{a\nb}"`), "{a\nb}"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			buffer := make([]byte, 0, 1)
+			d, err := td.source.Document(&buffer)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, td.expect, string(d))
+			// The grown buffer is stored back.
+			require.Equal(t, td.expect, string(buffer))
+			require.GreaterOrEqual(t, cap(buffer), len(td.expect))
+		})
+	}
+}
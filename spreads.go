@@ -0,0 +1,33 @@
+package gqlscan
+
+// CheckFragmentSpreads checks that every fragment spread in document src
+// refers to a fragment defined in src and returns a violation with
+// code ErrUndefFrag at the index of the name of every unresolved spread
+// in order of appearance.
+// Returns an error if src is invalid.
+func CheckFragmentSpreads(src []byte) (violations []Error, err Error) {
+	type spread struct {
+		name  string
+		index int
+	}
+	var (
+		spreads   []spread
+		fragments = map[string]bool{}
+	)
+	if err = ScanAll(src, func(i *Iterator) {
+		switch i.Token() {
+		case TokenFragName:
+			fragments[string(i.Value())] = true
+		case TokenNamedSpread:
+			spreads = append(spreads, spread{string(i.Value()), i.tail})
+		}
+	}); err.IsErr() {
+		return nil, err
+	}
+	for _, s := range spreads {
+		if !fragments[s.name] {
+			violations = append(violations, errorAt(src, s.index, ErrUndefFrag))
+		}
+	}
+	return violations, Error{}
+}
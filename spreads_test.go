@@ -0,0 +1,40 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFragmentSpreads(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect []string
+		err    string
+	}{
+		{decl(1), `{a}`, nil, ""},
+		{decl(1), `{...F} fragment F on T {a ...G} fragment G on T {b}`, nil, ""},
+		{decl(1), `fragment F on T {a} {...F}`, nil, ""},
+		{decl(1), `{...F ...G a {...F}} fragment G on T {...H}`, []string{
+			"error at index 4 ('F'): undefined fragment",
+			"error at index 17 ('F'): undefined fragment",
+			"error at index 41 ('H'): undefined fragment",
+		}, ""},
+		{decl(1), `{... on T {a}}`, nil, ""},
+		{decl(1), `{...F`, nil,
+			"error at index 4: unexpected end of file; expected fragment"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			v, err := gqlscan.CheckFragmentSpreads([]byte(td.input))
+			require.Equal(t, td.err, err.Error())
+			var actual []string
+			for _, e := range v {
+				actual = append(actual, e.Error())
+			}
+			require.Equal(t, td.expect, actual)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package gqlscan
+
+// Stream is an incremental scanner for documents
+// arriving in chunks.
+// The chunks are accumulated and fn is called for the tokens of
+// every definition once the definition is complete.
+// The scanned definitions are released from the buffer once they
+// take up at least half of it, the indexes of the tokens refer to
+// the buffer beginning at index Offset of the accumulated document
+// and the indexes of errors refer to the accumulated document.
+// The bytes fed are framed incrementally to find where definitions
+// end and a definition is scanned once it's complete. An incomplete
+// definition is checked for syntax errors only as long as the bytes
+// checked don't exceed twice the size of the document, thus feeding
+// a document takes linear time regardless of the size of the chunks.
+type Stream struct {
+	buf  []byte
+	done int
+	fn   func(*Iterator) (err bool)
+
+	// offset is the number of bytes released from the buffer,
+	// lines and columns the numbers of line terminators and of
+	// columns following the last one in the released bytes.
+	offset, lines, columns int
+
+	// checked is the number of bytes of incomplete
+	// definitions checked for syntax errors.
+	checked int
+
+	// pos is the index of the first byte not yet framed,
+	// lex the kind of the literal pos is in if any and
+	// depth and parens the numbers of open braces and parentheses.
+	pos           int
+	lex           literal
+	depth, parens int
+}
+
+// NewStream creates a new stream calling fn for every token
+// of the document fed. If fn returns true then an error
+// with code ErrCallbackFn is returned.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after Feed or Close returns! The values returned by the iterator
+// refer to the buffer, copy them if they're used after Feed returns.
+func NewStream(fn func(*Iterator) (err bool)) *Stream {
+	return &Stream{fn: fn}
+}
+
+// Reset resets the stream reusing its buffer.
+func (s *Stream) Reset() {
+	s.buf, s.done, s.checked = s.buf[:0], 0, 0
+	s.offset, s.lines, s.columns = 0, 0, 0
+	s.pos, s.lex, s.depth, s.parens = 0, 0, 0, 0
+}
+
+// Feed appends chunk to the document and scans all definitions
+// that were completed by chunk.
+// The stream must be reset after an error.
+// A syntax error is returned as soon as it can't be caused by
+// the document being incomplete and the definition it occurred in
+// is either complete or checked.
+func (s *Stream) Feed(chunk []byte) Error {
+	s.release()
+	s.buf = append(s.buf, chunk...)
+	if end := s.frame(); end > s.done {
+		last := s.done
+		err := scan(s.buf[:end], s.done, func(i *Iterator) bool {
+			if i.token == TokenDefEnd {
+				last = i.head + 1
+			}
+			return false
+		})
+		if err.IsErr() && s.isFinal(err) {
+			// Call fn for the tokens preceding the error.
+			return s.error(scan(s.buf, s.done, s.fn))
+		}
+		if last > s.done {
+			if err := scan(s.buf[:last], s.done, s.fn); err.IsErr() {
+				return s.error(err)
+			}
+			s.done = last
+		}
+	}
+
+	n := len(s.buf) - s.done
+	if n < 1 || s.checked+n > 2*(s.offset+len(s.buf)) {
+		return Error{}
+	}
+	// Check the incomplete definition for syntax errors.
+	s.checked += n
+	err := scan(s.buf, s.done, func(*Iterator) bool { return false })
+	if err.IsErr() && s.isFinal(err) {
+		// Call fn for the tokens preceding the error.
+		return s.error(scan(s.buf, s.done, s.fn))
+	}
+	return Error{}
+}
+
+// Offset returns the index in the accumulated document
+// of the first byte of the buffer.
+func (s *Stream) Offset() int { return s.offset }
+
+// release releases the scanned definitions from the buffer if they take
+// up at least half of it, thus every byte is moved at most once
+// on average and the buffer doesn't grow with the document.
+func (s *Stream) release() {
+	if s.done < 1 || 2*s.done < len(s.buf) {
+		return
+	}
+	l, c := LineColumn(s.buf, s.done)
+	if l == 1 {
+		s.columns += c - 1
+	} else {
+		s.columns = c - 1
+	}
+	s.lines += l - 1
+	s.buf = s.buf[:copy(s.buf, s.buf[s.done:])]
+	s.offset, s.pos, s.done = s.offset+s.done, s.pos-s.done, 0
+}
+
+// error returns err with its index, position and trail
+// referring to the accumulated document instead of the buffer.
+func (s *Stream) error(err Error) Error {
+	if !err.IsErr() || s.offset == 0 {
+		return err
+	}
+	err.Index += s.offset
+	if err.Line != 0 {
+		if err.Line == 1 {
+			err.Column += s.columns
+		}
+		err.Line += s.lines
+	}
+	for _, p := range []*Span{
+		&err.Trail.DefinitionName, &err.Trail.Field, &err.Trail.Directive,
+	} {
+		if !p.IsEmpty() {
+			p.Tail, p.Head = p.Tail+s.offset, p.Head+s.offset
+		}
+	}
+	return err
+}
+
+// Close scans the remainder of the document
+// and returns an error if it's incomplete.
+func (s *Stream) Close() Error {
+	err := scan(s.buf, s.done, s.fn)
+	if s.offset+s.done > 0 &&
+		err.Code == ErrUnexpEOF &&
+		err.Expectation == ExpectDef {
+		// Only ignored characters follow the last definition.
+		return Error{}
+	}
+	return s.error(err)
+}
+
+// frame advances the framing over the bytes fed and returns
+// the end index of the last definition completed by them, which is
+// the index following a closing brace that isn't enclosed by any
+// braces or parentheses. Returns s.done if no definition
+// was completed. Strings, block strings and comments are skipped.
+// The framing pauses at a byte that can't be classified
+// before the following bytes are fed.
+func (s *Stream) frame() (end int) {
+	end = s.done
+	b, x := s.buf, s.pos
+	defer func() { s.pos = x }()
+	for x < len(b) {
+		if s.lex != 0 {
+			e, ok := s.lex.end(b, x)
+			if x = e; !ok {
+				return end
+			}
+			s.lex = 0
+			continue
+		}
+		switch b[x] {
+		case '#', '"':
+			k, body, more := openLiteral(b, x)
+			if more {
+				return end
+			}
+			s.lex, x = k, body
+			continue
+		case '(':
+			s.parens++
+		case ')':
+			s.parens--
+		case '{':
+			s.depth++
+		case '}':
+			if s.depth--; s.depth < 1 && s.parens < 1 {
+				s.depth, s.parens, end = 0, 0, x+1
+			}
+		}
+		x++
+	}
+	return end
+}
+
+// isFinal returns true if err remains an error
+// no matter what is appended to the document.
+func (s *Stream) isFinal(err Error) bool {
+	if err.Code == ErrUnexpEOF {
+		return false
+	}
+	// The erroneous token could be incomplete
+	// unless a byte terminating it follows it.
+	x := err.Index
+	if x < len(s.buf) && isStreamPunct(s.buf[x]) {
+		return true
+	}
+	for ; x < len(s.buf); x++ {
+		if c := s.buf[x]; charClass[c]&classIgnored != 0 ||
+			c == '#' || c == '"' || isStreamPunct(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStreamPunct returns true for the punctuators
+// that are tokens of a single character.
+func isStreamPunct(c byte) bool {
+	switch c {
+	case '!', '$', '&', '(', ')', ':', '=', '@', '[', ']', '{', '}', '|':
+		return true
+	}
+	return false
+}
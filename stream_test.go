@@ -0,0 +1,173 @@
+package gqlscan_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	for _, td := range testdata {
+		for _, size := range []int{1, 2, 7, len(td.input)} {
+			t.Run(fmt.Sprintf("%s_%d", td.decl, size), func(t *testing.T) {
+				j := 0
+				s := gqlscan.NewStream(func(i *gqlscan.Iterator) bool {
+					require.True(t, j < len(td.expect))
+					require.Equal(t, td.expect[j].Type, i.Token())
+					require.Equal(t, td.expect[j].Value, string(i.Value()))
+					j++
+					return false
+				})
+				in := []byte(td.input)
+				for len(in) > 0 {
+					c := in
+					if len(c) > size {
+						c = c[:size]
+					}
+					in = in[len(c):]
+					err := s.Feed(c)
+					require.False(t, err.IsErr(), "unexpected error: %s", err)
+				}
+				err := s.Close()
+				require.False(t, err.IsErr(), "unexpected error: %s", err)
+				require.Len(t, td.expect, j)
+			})
+		}
+	}
+}
+
+func TestStreamErr(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	s := gqlscan.NewStream(noop)
+	for _, td := range testdataErr {
+		for _, size := range []int{1, 3, len(td.input)} {
+			t.Run(fmt.Sprintf("%s_%d", td.decl, size), func(t *testing.T) {
+				s.Reset()
+				in := []byte(td.input)
+				var err gqlscan.Error
+				for len(in) > 0 && !err.IsErr() {
+					c := in
+					if len(c) > size {
+						c = c[:size]
+					}
+					in = in[len(c):]
+					err = s.Feed(c)
+				}
+				if !err.IsErr() {
+					err = s.Close()
+				}
+				require.Equal(t, td.expectErr, err.Error())
+			})
+		}
+	}
+}
+
+func TestStreamEarlyErr(t *testing.T) {
+	var tokens []gqlscan.Token
+	s := gqlscan.NewStream(func(i *gqlscan.Iterator) bool {
+		tokens = append(tokens, i.Token())
+		return false
+	})
+	require.False(t, s.Feed([]byte(`{a} {b(`)).IsErr())
+	require.Equal(t, []gqlscan.Token{
+		gqlscan.TokenDefQry, gqlscan.TokenSet,
+		gqlscan.TokenField, gqlscan.TokenSetEnd,
+		gqlscan.TokenDefEnd,
+	}, tokens)
+
+	err := s.Feed([]byte(`} `))
+	require.Equal(t,
+		"error at index 7 ('}'): unexpected token; expected argument name",
+		err.Error())
+	require.Len(t, tokens, 9)
+}
+
+func TestStreamCallbackErr(t *testing.T) {
+	s := gqlscan.NewStream(func(i *gqlscan.Iterator) bool {
+		return i.Token() == gqlscan.TokenField
+	})
+	require.False(t, s.Feed([]byte(`{a`)).IsErr())
+	err := s.Feed([]byte(`}`))
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+}
+
+func TestStreamEmpty(t *testing.T) {
+	s := gqlscan.NewStream(func(*gqlscan.Iterator) bool { return false })
+	require.False(t, s.Feed([]byte(`  `)).IsErr())
+	require.Equal(t,
+		"error at index 2: unexpected end of file; expected definition",
+		s.Close().Error())
+}
+
+func TestStreamLargeDefinition(t *testing.T) {
+	// Feeding a large definition byte by byte must take linear time.
+	const fields = 100_000
+	in := []byte("{" + strings.Repeat(`a(b: "}") # }`+"\n", fields) + "}")
+	n := 0
+	s := gqlscan.NewStream(func(i *gqlscan.Iterator) bool {
+		if i.Token() == gqlscan.TokenField {
+			n++
+		}
+		return false
+	})
+	for x := range in {
+		err := s.Feed(in[x : x+1])
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+		if x < len(in)-1 {
+			require.Zero(t, n)
+		}
+	}
+	require.Equal(t, fields, n)
+	require.False(t, s.Close().IsErr())
+}
+
+func TestStreamTokenAcrossChunks(t *testing.T) {
+	var values []string
+	s := gqlscan.NewStream(func(i *gqlscan.Iterator) bool {
+		values = append(values, string(i.Value()))
+		return false
+	})
+	for _, c := range []string{`{a} fragmen`, `t F on T { b(c: """x`, `\"""}""") }`} {
+		err := s.Feed([]byte(c))
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+	}
+	require.False(t, s.Close().IsErr())
+	require.Equal(t, []string{
+		"", "", "a", "", "",
+		"", "F", "T", "", "b", "", "c", `x\"""}`, "", "", "",
+	}, values)
+}
+
+func TestStreamRelease(t *testing.T) {
+	var doc []byte
+	var s *gqlscan.Stream
+	s = gqlscan.NewStream(func(i *gqlscan.Iterator) bool {
+		if i.Token() == gqlscan.TokenField {
+			x := s.Offset() + i.IndexTail()
+			require.Equal(t, string(i.Value()), string(doc[x:x+1]))
+		}
+		return false
+	})
+	for c := byte('a'); c <= 'z'; c++ {
+		chunk := []byte("{" + string(c) + "}\n")
+		doc = append(doc, chunk...)
+		require.False(t, s.Feed(chunk).IsErr())
+	}
+	require.Greater(t, s.Offset(), 0)
+	require.Less(t, s.Offset(), len(doc))
+
+	n := len(doc)
+	doc = append(doc, "{a(}"...)
+	err := s.Feed(doc[n:])
+	require.Equal(t, n+3, err.Index)
+	require.Equal(t, 27, err.Line)
+	require.Equal(t, 4, err.Column)
+	require.Equal(t, gqlscan.Span{Tail: n + 1, Head: n + 2}, err.Trail.Field)
+
+	s.Reset()
+	require.Zero(t, s.Offset())
+}
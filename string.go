@@ -0,0 +1,27 @@
+package gqlscan
+
+import "unsafe"
+
+// ScanString is similar to Scan but scans string s without copying it.
+//
+// WARNING: The values returned by the iterator refer to the memory of s
+// and must never be modified! *Iterator passed to fn should never
+// be aliased and used after ScanString returns!
+func ScanString(s string, fn func(*Iterator) (err bool)) Error {
+	return Scan(unsafeBytes(s), fn)
+}
+
+// ValidateString is similar to Validate but validates string s
+// without copying it.
+func ValidateString(s string) Error {
+	return Validate(unsafeBytes(s))
+}
+
+// unsafeBytes returns a byte slice referring to the memory of s.
+// The byte slice must never be modified.
+func unsafeBytes(s string) []byte {
+	return *(*[]byte)(unsafe.Pointer(&struct {
+		string
+		int
+	}{s, len(s)}))
+}
@@ -0,0 +1,46 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanString(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			err := gqlscan.ScanString(td.input, func(i *gqlscan.Iterator) bool {
+				require.Equal(t, td.expect[j].Type, i.Token())
+				require.Equal(t, td.expect[j].Value, string(i.Value()))
+				j++
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, j)
+			require.False(t, gqlscan.ValidateString(td.input).IsErr())
+		})
+	}
+}
+
+func TestScanStringErr(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range testdataErr {
+		t.Run(td.decl, func(t *testing.T) {
+			require.Equal(t, td.expectErr, gqlscan.ScanString(td.input, noop).Error())
+			require.Equal(t, td.expectErr, gqlscan.ValidateString(td.input).Error())
+		})
+	}
+}
+
+func TestScanStringAllocs(t *testing.T) {
+	s := `query Q($a: [[ID!]!]!) { f(a: $a) { g h } }`
+	noop := func(*gqlscan.Iterator) bool { return false }
+	allocs := testing.AllocsPerRun(100, func() {
+		gqlscan.ScanString(s, noop)
+		gqlscan.ValidateString(s)
+	})
+	require.Zero(t, allocs)
+}
@@ -0,0 +1,118 @@
+package gqlscan
+
+// CheckSubscriptions checks that every subscription operation in
+// document src has exactly one root field, which must not be an
+// introspection field, and returns the violations in order of the
+// subscriptions.
+// Root fields are collected from inline fragments and fragment spreads
+// and fields with identical response keys count as one.
+// A violation has code ErrSubMultiRoot at the index of the first field
+// with a second distinct response key or code ErrSubIntrospection at
+// the index of the introspection root field.
+// Returns an error if src is invalid.
+func CheckSubscriptions(src []byte) (violations []Error, err Error) {
+	type selection struct {
+		// spread is the fragment name of fragment spreads
+		// and key is the response key of fields.
+		spread, key, name string
+		index             int
+	}
+	type definition struct {
+		subscription bool
+		name         string
+		selections   []selection
+	}
+	var (
+		defs []definition
+		// roots holds for every open selection set whether
+		// it's a root selection set.
+		roots []bool
+		// inline is true if the last selection is an inline fragment.
+		inline bool
+		alias  selection
+	)
+	if err = ScanAll(src, func(i *Iterator) {
+		switch i.Token() {
+		case TokenDefQry, TokenDefMut, TokenDefFrag:
+			defs = append(defs, definition{})
+		case TokenDefSub:
+			defs = append(defs, definition{subscription: true})
+		case TokenFragName:
+			defs[len(defs)-1].name = string(i.Value())
+		case TokenSet:
+			root := len(roots) < 1 || inline && roots[len(roots)-1]
+			roots = append(roots, root)
+			inline = false
+		case TokenSetEnd:
+			roots = roots[:len(roots)-1]
+		case TokenFragInline:
+			inline = true
+		case TokenFieldAlias:
+			alias = selection{key: string(i.Value()), index: i.tail}
+		case TokenField, TokenNamedSpread:
+			inline = false
+			s := selection{name: string(i.Value()), index: i.tail}
+			switch {
+			case i.token == TokenNamedSpread:
+				s.spread = s.name
+			case alias.key != "":
+				s.key, s.index = alias.key, alias.index
+			default:
+				s.key = s.name
+			}
+			alias = selection{}
+			if !roots[len(roots)-1] {
+				break
+			}
+			d := &defs[len(defs)-1]
+			d.selections = append(d.selections, s)
+		}
+	}); err.IsErr() {
+		return nil, err
+	}
+
+	fragments := map[string]int{}
+	for x, d := range defs {
+		if d.name != "" {
+			fragments[d.name] = x
+		}
+	}
+	for _, d := range defs {
+		if !d.subscription {
+			continue
+		}
+		var (
+			key     string
+			visited = map[int]bool{}
+			v       = Error{Index: -1}
+		)
+		var collect func(sels []selection)
+		collect = func(sels []selection) {
+			for _, s := range sels {
+				if v.Index > -1 {
+					return
+				}
+				if s.spread != "" {
+					f, ok := fragments[s.spread]
+					if ok && !visited[f] {
+						visited[f] = true
+						collect(defs[f].selections)
+					}
+					continue
+				}
+				switch {
+				case len(s.name) > 1 && s.name[0] == '_' && s.name[1] == '_':
+					v = errorAt(src, s.index, ErrSubIntrospection)
+				case key != "" && s.key != key:
+					v = errorAt(src, s.index, ErrSubMultiRoot)
+				}
+				key = s.key
+			}
+		}
+		collect(d.selections)
+		if v.Index > -1 {
+			violations = append(violations, v)
+		}
+	}
+	return violations, Error{}
+}
@@ -0,0 +1,54 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSubscriptions(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect []string
+		err    string
+	}{
+		{decl(1), `subscription {a {b c}}`, nil, ""},
+		{decl(1), `{a b __typename} mutation {c d}`, nil, ""},
+		{decl(1), `subscription {a a {b} a: a}`, nil, ""},
+		{decl(1), `subscription {... on S {a} ...F} fragment F on S {a {b}}`,
+			nil, ""},
+		{decl(1), `subscription {a b}`, []string{
+			"error at index 16 ('b'): subscription has multiple root fields",
+		}, ""},
+		{decl(1), `subscription {a x: a}`, []string{
+			"error at index 16 ('x'): subscription has multiple root fields",
+		}, ""},
+		{decl(1), `subscription {__typename}`, []string{
+			"error at index 14 ('_'): introspection root field in subscription",
+		}, ""},
+		{decl(1), "subscription A {a ... on S {... on S {b}}}\n" +
+			"subscription B {...F}\n" +
+			"fragment F on S {a ...G}\n" +
+			"fragment G on S {__schema {types {name}}}", []string{
+			"error at index 38 ('b'): subscription has multiple root fields",
+			"error at index 107 ('_'): introspection root field in subscription",
+		}, ""},
+		{decl(1), `subscription {...F} fragment F on S {a ...F}`, nil, ""},
+		{decl(1), `subscription {...X}`, nil, ""},
+		{decl(1), `subscription {a`, nil,
+			"error at index 15: unexpected end of file; expected field name or alias"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			v, err := gqlscan.CheckSubscriptions([]byte(td.input))
+			require.Equal(t, td.err, err.Error())
+			var actual []string
+			for _, e := range v {
+				actual = append(actual, e.Error())
+			}
+			require.Equal(t, td.expect, actual)
+		})
+	}
+}
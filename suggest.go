@@ -0,0 +1,98 @@
+package gqlscan
+
+// Suggestion returns the keyword the name at the index of error e
+// in src is most likely a misspelling of, for example "query" for
+// `qurey { a }`, or an empty string if there's no such keyword.
+// Misspelled value keywords such as `flase` are valid enum values
+// and never cause an error.
+func (e Error) Suggestion(src []byte) string {
+	if e.Code != ErrUnexpToken || e.Index >= len(src) {
+		return ""
+	}
+	var keywords []string
+	switch e.Expectation {
+	case ExpectDef:
+		keywords = []string{"query", "mutation", "subscription", "fragment"}
+	case ExpectFragKeywordOn:
+		keywords = []string{"on"}
+	default:
+		return ""
+	}
+
+	n := src[e.Index:]
+	for x, c := range n {
+		if charClass[c]&className == 0 {
+			n = n[:x]
+			break
+		}
+	}
+	if len(n) < 1 {
+		return ""
+	}
+
+	// Allow one edit for short and two edits for longer keywords.
+	best, bestDist := "", 3
+	for _, k := range keywords {
+		max := 1
+		if len(k) > 4 {
+			max = 2
+		}
+		if d := editDistance(n, k, max+1); d <= max && d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the optimal string alignment distance
+// between a and b counting transpositions as a single edit.
+// Returns bound if the distance is at least bound.
+func editDistance(a []byte, b string, bound int) int {
+	if d := len(a) - len(b); d >= bound || -d >= bound {
+		return bound
+	}
+	// Keywords are short, three rows of at most 16 columns
+	// are kept on the stack.
+	var rows [3][16]int
+	if len(b)+1 > len(rows[0]) {
+		return bound
+	}
+	prev2, prev, cur := &rows[0], &rows[1], &rows[2]
+	for j := 0; j <= len(b); j++ {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := prev[j-1] + cost
+			if v := prev[j] + 1; v < d {
+				d = v
+			}
+			if v := cur[j-1] + 1; v < d {
+				d = v
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				if v := prev2[j-2] + 1; v < d {
+					d = v
+				}
+			}
+			cur[j] = d
+			if d < rowMin {
+				rowMin = d
+			}
+		}
+		if rowMin >= bound {
+			return bound
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	if prev[len(b)] > bound {
+		return bound
+	}
+	return prev[len(b)]
+}
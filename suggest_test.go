@@ -0,0 +1,44 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorSuggestion(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `qurey { a }`, "query"},
+		{decl(1), `quer { a }`, "query"},
+		{decl(1), `Query { a }`, "query"},
+		{decl(1), `fragmet F on T { a }`, "fragment"},
+		{decl(1), `subscriptoin { a }`, "subscription"},
+		{decl(1), `{ a } mutatoin { b }`, "mutation"},
+		{decl(1), `mutaton M { b }`, "mutation"},
+		{decl(1), `fragment F of T { a }`, "on"},
+		{decl(1), `fragment F no T { a }`, "on"},
+		{decl(1), `fragment F T { a }`, ""},
+		{decl(1), `fragment F onn T { a }`, ""},
+		{decl(1), `foo { a }`, ""},
+		{decl(1), `qqqqqqq { a }`, ""},
+		{decl(1), `mute { a }`, ""},
+		{decl(1), `{ a(b: ) }`, ""},
+		{decl(1), `( a )`, ""},
+		{decl(1), `qurey`, "query"},
+		{decl(1), `fragment F`, ""},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			src := []byte(td.input)
+			err := gqlscan.ScanAll(src, func(*gqlscan.Iterator) {})
+			require.True(t, err.IsErr())
+			require.Equal(t, td.expect, err.Suggestion(src))
+		})
+	}
+	require.Equal(t, "", gqlscan.Error{}.Suggestion([]byte(`qurey`)))
+}
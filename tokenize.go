@@ -0,0 +1,28 @@
+package gqlscan
+
+// Tokenize appends the tokens of str to dst
+// and returns the extended buffer.
+// If str is invalid then the tokens preceding the error
+// are appended and the error is returned.
+//
+// WARNING: The values of the tokens refer to the same underlying
+// memory as str, copy them or use with caution!
+func Tokenize(str []byte, dst []TokenValue) ([]TokenValue, Error) {
+	err := ScanAll(str, func(i *Iterator) {
+		dst = append(dst, TokenValue{
+			Token: i.token,
+			Value: i.Value(),
+			Index: i.index(),
+		})
+	})
+	return dst, err
+}
+
+// index returns the tail index of the current token
+// if it has a value, otherwise returns the head index.
+func (i *Iterator) index() int {
+	if i.tail > -1 {
+		return i.tail
+	}
+	return i.head
+}
@@ -0,0 +1,51 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	var tokens []gqlscan.TokenValue
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			var err gqlscan.Error
+			tokens, err = gqlscan.Tokenize([]byte(td.input), tokens[:0])
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, tokens, len(td.expect))
+			for j, e := range td.expect {
+				require.Equal(t, e.Type, tokens[j].Token)
+				require.Equal(t, e.Value, string(tokens[j].Value))
+			}
+		})
+	}
+}
+
+func TestTokenizeIndex(t *testing.T) {
+	src := []byte(`query Q { a(b: "c") }`)
+	tokens, err := gqlscan.Tokenize(src, nil)
+	require.False(t, err.IsErr())
+	require.Equal(t, []gqlscan.TokenValue{
+		{Token: gqlscan.TokenDefQry, Index: 0},
+		{Token: gqlscan.TokenOprName, Value: src[6:7], Index: 6},
+		{Token: gqlscan.TokenSet, Index: 8},
+		{Token: gqlscan.TokenField, Value: src[10:11], Index: 10},
+		{Token: gqlscan.TokenArgList, Index: 11},
+		{Token: gqlscan.TokenArgName, Value: src[12:13], Index: 12},
+		{Token: gqlscan.TokenStr, Value: src[16:17], Index: 16},
+		{Token: gqlscan.TokenArgListEnd, Index: 18},
+		{Token: gqlscan.TokenSetEnd, Index: 20},
+		{Token: gqlscan.TokenDefEnd, Index: 20},
+	}, tokens)
+}
+
+func TestTokenizeErr(t *testing.T) {
+	tokens, err := gqlscan.Tokenize([]byte(`{a(}`), nil)
+	require.Equal(t,
+		"error at index 3 ('}'): unexpected token; expected argument name",
+		err.Error())
+	require.Len(t, tokens, 4)
+}
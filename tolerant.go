@@ -0,0 +1,27 @@
+package gqlscan
+
+// ScanTolerant is similar to ScanAll but tolerates documents ending
+// inside an incomplete construct, such as documents being edited.
+// Instead of returning an error with code ErrUnexpEOF it calls fn
+// for TokenMissing at the end of str with Iterator.Expect
+// returning the construct that's missing, and returns no error.
+// All other errors are returned as usual.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanTolerant returns!
+func ScanTolerant(str []byte, fn func(*Iterator)) Error {
+	i := iteratorPool.Get().(*Iterator)
+	defer iteratorPool.Put(i)
+	i.Reset(str)
+	err := i.scan(0, func(i *Iterator) bool {
+		fn(i)
+		return false
+	})
+	if err.Code != ErrUnexpEOF {
+		return err
+	}
+	i.token, i.expect = TokenMissing, err.Expectation
+	i.tail, i.head = -1, len(str)
+	fn(i)
+	return Error{}
+}
@@ -0,0 +1,93 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTolerant(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			err := gqlscan.ScanTolerant(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) {
+					require.Equal(t, td.expect[j].Type, i.Token())
+					require.Equal(t, td.expect[j].Value, string(i.Value()))
+					j++
+				},
+			)
+			require.False(t, err.IsErr())
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanTolerantMissing(t *testing.T) {
+	for _, td := range []struct {
+		decl         string
+		input        string
+		expectTokens []gqlscan.Token
+		expect       gqlscan.Expect
+	}{
+		{decl(1), "", []gqlscan.Token{
+			gqlscan.TokenMissing,
+		}, gqlscan.ExpectDef},
+		{decl(1), "{a{", []gqlscan.Token{
+			gqlscan.TokenDefQry,
+			gqlscan.TokenSet,
+			gqlscan.TokenField,
+			gqlscan.TokenSet,
+			gqlscan.TokenMissing,
+		}, gqlscan.ExpectSel},
+		{decl(1), "query Q($v: ", []gqlscan.Token{
+			gqlscan.TokenDefQry,
+			gqlscan.TokenOprName,
+			gqlscan.TokenVarList,
+			gqlscan.TokenVarName,
+			gqlscan.TokenMissing,
+		}, gqlscan.ExpectVarType},
+		{decl(1), "{a(b: ", []gqlscan.Token{
+			gqlscan.TokenDefQry,
+			gqlscan.TokenSet,
+			gqlscan.TokenField,
+			gqlscan.TokenArgList,
+			gqlscan.TokenArgName,
+			gqlscan.TokenMissing,
+		}, gqlscan.ExpectVal},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			src := []byte(td.input)
+			var tokens []gqlscan.Token
+			var expect gqlscan.Expect
+			err := gqlscan.ScanTolerant(src, func(i *gqlscan.Iterator) {
+				tokens = append(tokens, i.Token())
+				if i.Token() == gqlscan.TokenMissing {
+					expect = i.Expect()
+					require.Nil(t, i.Value())
+					require.Equal(t, len(src), i.IndexHead())
+				}
+			})
+			require.False(t, err.IsErr())
+			require.Equal(t, td.expectTokens, tokens)
+			require.Equal(t, td.expect, expect)
+		})
+	}
+}
+
+func TestScanTolerantErr(t *testing.T) {
+	var tokens []gqlscan.Token
+	err := gqlscan.ScanTolerant([]byte("{a(}"), func(i *gqlscan.Iterator) {
+		tokens = append(tokens, i.Token())
+	})
+	require.Equal(t, gqlscan.ErrUnexpToken, err.Code)
+	require.Equal(t, []gqlscan.Token{
+		gqlscan.TokenDefQry,
+		gqlscan.TokenSet,
+		gqlscan.TokenField,
+		gqlscan.TokenArgList,
+	}, tokens)
+}
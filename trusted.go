@@ -0,0 +1,138 @@
+package gqlscan
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"unicode/utf8"
+)
+
+// DocumentHash is the SHA-256 hash of a document.
+type DocumentHash [sha256.Size]byte
+
+// HashDocument returns the hash of document str.
+func HashDocument(str []byte) DocumentHash {
+	return sha256.Sum256(str)
+}
+
+// TokenValue is a token together with its value.
+type TokenValue struct {
+	Token Token
+	Value []byte
+
+	// Index is the index of the token in the scanned document,
+	// the tail index for tokens with a value and the head index otherwise.
+	Index int
+}
+
+// DocumentStore provides trusted documents.
+type DocumentStore interface {
+	// Lookup returns the token stream of the trusted document
+	// with the given hash. Returns false if the document is unknown.
+	Lookup(hash DocumentHash) (tokens []TokenValue, ok bool)
+}
+
+// DocumentMap is a DocumentStore keeping documents in memory.
+// DocumentMap is safe for concurrent reads but not for
+// concurrent use of Add.
+type DocumentMap map[DocumentHash][]TokenValue
+
+// Add scans document str and adds it to the map.
+// The map doesn't retain str.
+func (m DocumentMap) Add(str []byte) Error {
+	var tokens []TokenValue
+	if err := ScanAll(str, func(i *Iterator) {
+		tokens = append(tokens, TokenValue{
+			Token: i.Token(),
+			Value: append([]byte(nil), i.Value()...),
+			Index: i.index(),
+		})
+	}); err.IsErr() {
+		return err
+	}
+	m[HashDocument(str)] = tokens
+	return Error{}
+}
+
+// Lookup implements DocumentStore.
+func (m DocumentMap) Lookup(hash DocumentHash) ([]TokenValue, bool) {
+	t, ok := m[hash]
+	return t, ok
+}
+
+// ScanTrusted is similar to Scan but only accepts trusted documents.
+// If store doesn't know the hash of document str
+// then an error with code ErrUntrustedDoc is returned before fn
+// is called for any token. If the token stream of str differs
+// from the one provided by store then an error with code
+// ErrDocMismatch is returned at the index of the first
+// mismatching token, fn is only called for the matching tokens.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanTrusted returns!
+func ScanTrusted(
+	str []byte,
+	store DocumentStore,
+	fn func(*Iterator) (err bool),
+) Error {
+	return ScanWithOptions(str, fn, WithTrustedStore(store))
+}
+
+// WithTrustedStore makes the scan only accept documents trusted
+// by store similar to ScanTrusted. The document is hashed
+// starting at the offset of the scan.
+func WithTrustedStore(store DocumentStore) Option {
+	return func(o *options) { o.trusted = store }
+}
+
+// scanTrusted is similar to scanLimited but only accepts
+// documents trusted by o.trusted.
+func (o *options) scanTrusted(
+	str []byte,
+	fn func(*Iterator) (err bool),
+) Error {
+	tokens, ok := o.trusted.Lookup(HashDocument(str[o.offset:]))
+	if !ok {
+		return errorAt(str, o.offset, ErrUntrustedDoc)
+	}
+	n, mismatch := 0, -1
+	err := o.scanLimited(str, func(i *Iterator) bool {
+		if n >= len(tokens) ||
+			tokens[n].Token != i.Token() ||
+			!bytes.Equal(tokens[n].Value, i.Value()) {
+			if mismatch = i.IndexTail(); mismatch < 0 {
+				mismatch = i.IndexHead()
+			}
+			return true
+		}
+		n++
+		return fn(i)
+	})
+	switch {
+	case mismatch > -1 && err.Code == ErrCallbackFn:
+		e := errorAt(str, mismatch, ErrDocMismatch)
+		e.DefinitionIndex, e.TokenOrdinal = err.DefinitionIndex, err.TokenOrdinal
+		e.Trail = err.Trail
+		return e
+	case err.IsErr():
+		return err
+	case n < len(tokens):
+		return errorAt(str, len(str), ErrDocMismatch)
+	}
+	return Error{}
+}
+
+// errorAt returns an error with the given code at index of str.
+func errorAt(str []byte, index int, code ErrorCode) Error {
+	var atIndex rune
+	if index < len(str) {
+		atIndex, _ = utf8.DecodeRune(str[index:])
+	}
+	line, column := LineColumn(str, index)
+	return Error{
+		Index:   index,
+		AtIndex: atIndex,
+		Code:    code,
+		Line:    line,
+		Column:  column,
+	}
+}
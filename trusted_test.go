@@ -0,0 +1,118 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTrusted(t *testing.T) {
+	m := gqlscan.DocumentMap{}
+	for _, td := range testdata {
+		require.False(t, m.Add([]byte(td.input)).IsErr(), td.decl)
+	}
+
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			err := gqlscan.ScanTrusted(
+				[]byte(td.input), m,
+				func(i *gqlscan.Iterator) bool {
+					require.Equal(t, td.expect[j].Type, i.Token())
+					require.Equal(t, td.expect[j].Value, string(i.Value()))
+					j++
+					return false
+				},
+			)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanTrustedErr(t *testing.T) {
+	m := gqlscan.DocumentMap{}
+	require.False(t, m.Add([]byte(`{a b}`)).IsErr())
+	require.Equal(t,
+		"error at index 3 ('}'): unexpected token; "+
+			"expected argument name",
+		m.Add([]byte(`{a(}`)).Error(),
+	)
+	require.Len(t, m, 1)
+
+	noop := func(*gqlscan.Iterator) bool { return false }
+
+	for _, td := range []struct {
+		decl   string
+		store  gqlscan.DocumentStore
+		input  string
+		expect string
+	}{
+		{decl(1), m, `{a  b}`,
+			"error at index 0 ('{'): untrusted document"},
+		{decl(1), m, ``,
+			"error at index 0 (0x0): untrusted document"},
+		{decl(1), mismatchStore{
+			{Token: gqlscan.TokenDefQry},
+			{Token: gqlscan.TokenSet},
+			{Token: gqlscan.TokenField, Value: []byte("a")},
+			{Token: gqlscan.TokenField, Value: []byte("c")},
+		}, `{a b}`,
+			"error at index 3 ('b'): document mismatches trusted document"},
+		{decl(1), mismatchStore{
+			{Token: gqlscan.TokenDefQry},
+			{Token: gqlscan.TokenSet},
+			{Token: gqlscan.TokenField, Value: []byte("a")},
+			{Token: gqlscan.TokenSetEnd},
+			{Token: gqlscan.TokenDefEnd},
+			{Token: gqlscan.TokenDefQry},
+		}, `{a}`,
+			"error at index 3 (0x0): document mismatches trusted document"},
+		{decl(1), mismatchStore{
+			{Token: gqlscan.TokenDefQry},
+			{Token: gqlscan.TokenSet},
+		}, `{a}`,
+			"error at index 1 ('a'): document mismatches trusted document"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanTrusted([]byte(td.input), td.store, noop)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
+
+// mismatchStore returns its token stream for any hash.
+type mismatchStore []gqlscan.TokenValue
+
+func (s mismatchStore) Lookup(
+	gqlscan.DocumentHash,
+) ([]gqlscan.TokenValue, bool) {
+	return s, true
+}
+
+func TestWithTrustedStore(t *testing.T) {
+	m := gqlscan.DocumentMap{}
+	require.False(t, m.Add([]byte(`{a b}`)).IsErr())
+	noop := func(*gqlscan.Iterator) bool { return false }
+
+	err := gqlscan.ScanWithOptions([]byte(`# {a b}`), noop,
+		gqlscan.WithOffset(2),
+		gqlscan.WithTrustedStore(m),
+	)
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+
+	err = gqlscan.ScanWithOptions([]byte(`# {a c}`), noop,
+		gqlscan.WithOffset(2),
+		gqlscan.WithTrustedStore(m),
+	)
+	require.Equal(t,
+		"error at index 2 ('{'): untrusted document", err.Error())
+
+	err = gqlscan.ScanWithOptions([]byte(`{a b}`), noop,
+		gqlscan.WithTrustedStore(m),
+		gqlscan.WithMaxTokens(3),
+	)
+	require.Equal(t, gqlscan.ErrTooManyTokens, err.Code)
+}
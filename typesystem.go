@@ -0,0 +1,761 @@
+package gqlscan
+
+// ScanTypeSystem calls fn for every token it scans in the type system
+// document str consisting of schema, type, interface, union, enum,
+// input, scalar and directive definitions.
+//
+// A definition begins with TokenDefType, TokenDefInterface,
+// TokenDefUnion, TokenDefEnum, TokenDefInput or TokenDefScalar
+// followed by TokenTypeName, with TokenDefDir followed by
+// TokenDirName, or with TokenDefSchema, and ends with TokenDefEnd.
+// Fields, input fields, enum values and root operation types
+// are enclosed in TokenDefBody and TokenDefBodyEnd, argument
+// definitions in TokenArgList and TokenArgListEnd.
+// The root operation types are scanned as TokenRootQry,
+// TokenRootMut and TokenRootSub with the name of the type as value.
+// Directive definitions are followed by TokenDirRepeatable if the
+// directive is repeatable and TokenDirLocation for every location. Types are scanned as TokenVarTypeName,
+// TokenVarTypeArr, TokenVarTypeArrEnd and TokenVarTypeNotNull.
+// Descriptions are scanned as TokenStr or TokenStrBlock preceding
+// the definition, field, argument or enum value they describe.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanTypeSystem returns!
+func ScanTypeSystem(str []byte, fn func(*Iterator) (err bool)) Error {
+	i := iteratorPool.Get().(*Iterator)
+	defer iteratorPool.Put(i)
+	i.Reset(str)
+	i.levelSel = 0
+
+	s := typeSystemScanner{
+		Iterator:  i,
+		fn:        fn,
+		skipped:   -1,
+		expectDef: ExpectTypeSysDef,
+	}
+	for s.skip(); s.head < len(s.str); s.skip() {
+		if !s.definition() {
+			return s.err
+		}
+	}
+	return Error{}
+}
+
+// ScanDocument is similar to Scan but accepts documents mixing
+// executable definitions with the type system definitions
+// scanned by ScanTypeSystem.
+//
+// WARNING: *Iterator passed to fn should never be aliased and
+// used after ScanDocument returns!
+func ScanDocument(str []byte, fn func(*Iterator) (err bool)) Error {
+	i := iteratorPool.Get().(*Iterator)
+	defer iteratorPool.Put(i)
+	i.Reset(str)
+	i.levelSel = 0
+
+	s := typeSystemScanner{
+		Iterator:  i,
+		fn:        fn,
+		skipped:   -1,
+		expectDef: ExpectDef,
+	}
+	for s.skip(); s.head < len(s.str); s.skip() {
+		if !s.isHeadExecutableDefinition() {
+			if !s.definition() {
+				return s.err
+			}
+			continue
+		}
+		end, tokens := -1, 0
+		err := scan(str, s.head, func(i *Iterator) bool {
+			if fn(i) {
+				return true
+			}
+			if i.token == TokenDefEnd {
+				end, tokens = i.head+1, i.tokens
+				return true
+			}
+			return false
+		})
+		if end < 0 {
+			err.DefinitionIndex += s.defs
+			err.TokenOrdinal += s.tokens
+			return err
+		}
+		s.head = end
+		s.defs, s.tokens = s.defs+1, s.tokens+tokens
+	}
+	return Error{}
+}
+
+// typeSystemKeywords maps the keywords of the type system
+// definitions to their tokens.
+var typeSystemKeywords = [...]struct {
+	keyword string
+	token   Token
+}{
+	{"type", TokenDefType},
+	{"interface", TokenDefInterface},
+	{"union", TokenDefUnion},
+	{"enum", TokenDefEnum},
+	{"input", TokenDefInput},
+	{"scalar", TokenDefScalar},
+	{"schema", TokenDefSchema},
+	{"directive", TokenDefDir},
+}
+
+// rootOperations maps the operation types of the root operation
+// type definitions to their tokens.
+var rootOperations = [...]struct {
+	keyword string
+	token   Token
+}{
+	{"query", TokenRootQry},
+	{"mutation", TokenRootMut},
+	{"subscription", TokenRootSub},
+}
+
+// typeSystemScanner scans type system documents.
+// Its methods return false after setting err.
+type typeSystemScanner struct {
+	*Iterator
+	fn  func(*Iterator) (err bool)
+	err Error
+
+	// end is the index following the last scanned token and
+	// skipped is the index the head was last advanced to by skip.
+	end, skipped int
+
+	// expectDef is the expectation of errors at the beginning
+	// of a definition.
+	expectDef Expect
+}
+
+// definition scans the definition at the head.
+func (s *typeSystemScanner) definition() bool {
+	s.defs++
+	if !s.description() {
+		return false
+	}
+	s.skip()
+	if s.head >= len(s.str) {
+		return s.fail(ErrUnexpEOF, s.expectDef)
+	}
+	var t Token
+	for _, k := range typeSystemKeywords {
+		if s.isHeadKeyword(k.keyword) {
+			t = k.token
+			s.tail = -1
+			if !s.emit(t) {
+				return false
+			}
+			s.head += len(k.keyword)
+			break
+		}
+	}
+	if t == 0 {
+		return s.fail(ErrUnexpToken, s.expectDef)
+	}
+
+	switch t {
+	case TokenDefSchema:
+		return s.directives() && s.schemaBody() && s.definitionEnd()
+	case TokenDefDir:
+		return s.directiveDefinition() && s.definitionEnd()
+	}
+
+	if !s.name(TokenTypeName, ExpectTypeName) {
+		return false
+	}
+	var ok bool
+	switch t {
+	case TokenDefType, TokenDefInterface:
+		ok = s.implements() && s.directives() && s.body(t)
+	case TokenDefUnion:
+		ok = s.directives() && s.unionMembers()
+	case TokenDefEnum, TokenDefInput:
+		ok = s.directives() && s.body(t)
+	case TokenDefScalar:
+		ok = s.directives()
+	}
+	return ok && s.definitionEnd()
+}
+
+// definitionEnd calls fn for TokenDefEnd at the last character
+// of the definition.
+func (s *typeSystemScanner) definitionEnd() bool {
+	s.skip()
+	head := s.head
+	s.tail, s.head = -1, s.end-1
+	if !s.emit(TokenDefEnd) {
+		return false
+	}
+	s.head = head
+	return true
+}
+
+// implements scans the optional implements clause at the head.
+func (s *typeSystemScanner) implements() bool {
+	s.skip()
+	if !s.isHeadKeyword("implements") {
+		return true
+	}
+	s.head += len("implements")
+	return s.names(TokenImplements, '&', ExpectInterfaceName)
+}
+
+// unionMembers scans the optional union member types at the head.
+func (s *typeSystemScanner) unionMembers() bool {
+	s.skip()
+	if s.head >= len(s.str) || s.str[s.head] != '=' {
+		return true
+	}
+	s.head++
+	return s.names(TokenUnionMember, '|', ExpectUnionMember)
+}
+
+// names scans the names of token t separated by sep
+// which may also precede the first name.
+func (s *typeSystemScanner) names(t Token, sep byte, expect Expect) bool {
+	s.skip()
+	if s.head < len(s.str) && s.str[s.head] == sep {
+		s.head++
+	}
+	for {
+		if !s.name(t, expect) {
+			return false
+		}
+		s.skip()
+		if s.head >= len(s.str) || s.str[s.head] != sep {
+			return true
+		}
+		s.head++
+	}
+}
+
+// body scans the optional body of a definition of kind t.
+func (s *typeSystemScanner) body(t Token) bool {
+	s.skip()
+	if s.head >= len(s.str) || s.str[s.head] != '{' {
+		return true
+	}
+	s.tail = -1
+	if !s.emit(TokenDefBody) {
+		return false
+	}
+	s.head++
+	for {
+		var ok bool
+		switch t {
+		case TokenDefEnum:
+			ok = s.enumValueDefinition()
+		case TokenDefInput:
+			ok = s.inputValueDefinition(TokenFieldDef)
+		default:
+			ok = s.fieldDefinition()
+		}
+		if !ok {
+			return false
+		}
+		s.skip()
+		if s.head < len(s.str) && s.str[s.head] == '}' {
+			break
+		}
+	}
+	s.tail = -1
+	if !s.emit(TokenDefBodyEnd) {
+		return false
+	}
+	s.head++
+	return true
+}
+
+// schemaBody scans the root operation type definitions
+// of a schema definition.
+func (s *typeSystemScanner) schemaBody() bool {
+	s.skip()
+	if s.head >= len(s.str) {
+		return s.fail(ErrUnexpEOF, ExpectDefBody)
+	} else if s.str[s.head] != '{' {
+		return s.fail(ErrUnexpToken, ExpectDefBody)
+	}
+	s.tail = -1
+	if !s.emit(TokenDefBody) {
+		return false
+	}
+	s.head++
+	for {
+		s.skip()
+		if s.head >= len(s.str) {
+			return s.fail(ErrUnexpEOF, ExpectRootOpr)
+		}
+		var t Token
+		for _, o := range rootOperations {
+			if s.isHeadKeyword(o.keyword) {
+				t = o.token
+				s.head += len(o.keyword)
+				break
+			}
+		}
+		if t == 0 {
+			return s.fail(ErrUnexpToken, ExpectRootOpr)
+		}
+		if !s.punct(':', ExpectColumnAfterRootOpr) ||
+			!s.name(t, ExpectTypeName) {
+			return false
+		}
+		s.skip()
+		if s.head < len(s.str) && s.str[s.head] == '}' {
+			break
+		}
+	}
+	s.tail = -1
+	if !s.emit(TokenDefBodyEnd) {
+		return false
+	}
+	s.head++
+	return true
+}
+
+// directiveDefinition scans the name, arguments and locations
+// of a directive definition.
+func (s *typeSystemScanner) directiveDefinition() bool {
+	if !s.punct('@', ExpectDir) || !s.name(TokenDirName, ExpectDirName) {
+		return false
+	}
+	s.skip()
+	if s.head < len(s.str) && s.str[s.head] == '(' {
+		if !s.argumentDefinitions() {
+			return false
+		}
+		s.skip()
+	}
+	if s.isHeadKeyword("repeatable") {
+		s.tail = -1
+		if !s.emit(TokenDirRepeatable) {
+			return false
+		}
+		s.head += len("repeatable")
+		s.skip()
+	}
+	if s.head >= len(s.str) {
+		return s.fail(ErrUnexpEOF, ExpectFragKeywordOn)
+	} else if !s.isHeadKeyword("on") {
+		return s.fail(ErrUnexpToken, ExpectFragKeywordOn)
+	}
+	s.head += len("on")
+	s.skip()
+	if s.head < len(s.str) && s.str[s.head] == '|' {
+		s.head++
+	}
+	for {
+		s.skip()
+		if s.head >= len(s.str) {
+			return s.fail(ErrUnexpEOF, ExpectDirLocation)
+		}
+		if code := s.scanName(); code != 0 {
+			return s.fail(code, ExpectDirLocation)
+		}
+		if !isDirectiveLocation(s.str[s.tail:s.head]) {
+			s.head = s.tail
+			return s.fail(ErrUnexpToken, ExpectDirLocation)
+		}
+		if !s.emit(TokenDirLocation) {
+			return false
+		}
+		s.skip()
+		if s.head >= len(s.str) || s.str[s.head] != '|' {
+			return true
+		}
+		s.head++
+	}
+}
+
+// fieldDefinition scans the field definition at the head.
+func (s *typeSystemScanner) fieldDefinition() bool {
+	if !s.description() || !s.name(TokenFieldDef, ExpectFieldDef) {
+		return false
+	}
+	s.skip()
+	if s.head < len(s.str) && s.str[s.head] == '(' {
+		if !s.argumentDefinitions() {
+			return false
+		}
+	}
+	return s.punct(':', ExpectColumnAfterField) &&
+		s.typeRef() && s.directives()
+}
+
+// argumentDefinitions scans the argument definitions
+// beginning with the parenthesis at the head.
+func (s *typeSystemScanner) argumentDefinitions() bool {
+	s.tail = -1
+	if !s.emit(TokenArgList) {
+		return false
+	}
+	s.head++
+	for {
+		if !s.inputValueDefinition(TokenArgDef) {
+			return false
+		}
+		s.skip()
+		if s.head < len(s.str) && s.str[s.head] == ')' {
+			break
+		}
+	}
+	s.tail = -1
+	if !s.emit(TokenArgListEnd) {
+		return false
+	}
+	s.head++
+	return true
+}
+
+// inputValueDefinition scans the argument or input field
+// definition at the head, t is either TokenArgDef or TokenFieldDef.
+func (s *typeSystemScanner) inputValueDefinition(t Token) bool {
+	expectName, expectColumn := ExpectFieldDef, ExpectColumnAfterField
+	if t == TokenArgDef {
+		expectName, expectColumn = ExpectArgName, ExpectColumnAfterArg
+	}
+	if !s.description() || !s.name(t, expectName) ||
+		!s.punct(':', expectColumn) || !s.typeRef() {
+		return false
+	}
+	s.skip()
+	if s.head < len(s.str) && s.str[s.head] == '=' {
+		s.head++
+		if !s.value() {
+			return false
+		}
+	}
+	return s.directives()
+}
+
+// enumValueDefinition scans the enum value definition at the head.
+func (s *typeSystemScanner) enumValueDefinition() bool {
+	if !s.description() {
+		return false
+	}
+	s.skip()
+	if s.head >= len(s.str) {
+		return s.fail(ErrUnexpEOF, ExpectValEnum)
+	}
+	if code := s.scanName(); code != 0 {
+		return s.fail(code, ExpectValEnum)
+	}
+	switch string(s.str[s.tail:s.head]) {
+	case "true", "false", "null":
+		s.head = s.tail
+		return s.fail(ErrUnexpToken, ExpectValEnum)
+	}
+	return s.emit(TokenEnumValDef) && s.directives()
+}
+
+// typeRef scans the type at the head.
+// Nested list types are scanned iteratively to not exhaust the stack.
+func (s *typeSystemScanner) typeRef() bool {
+	// lists is the number of open list types.
+	lists := 0
+	for s.skip(); s.head < len(s.str) && s.str[s.head] == '['; s.skip() {
+		s.tail = -1
+		if !s.emit(TokenVarTypeArr) {
+			return false
+		}
+		s.head++
+		lists++
+	}
+	if s.head >= len(s.str) {
+		return s.fail(ErrUnexpEOF, ExpectType)
+	}
+	if !s.name(TokenVarTypeName, ExpectType) {
+		return false
+	}
+	for {
+		s.skip()
+		if s.head < len(s.str) && s.str[s.head] == '!' {
+			s.tail = -1
+			if !s.emit(TokenVarTypeNotNull) {
+				return false
+			}
+			s.head++
+		}
+		if lists < 1 {
+			return true
+		}
+		s.skip()
+		if s.head >= len(s.str) {
+			return s.fail(ErrUnexpEOF, ExpectType)
+		} else if s.str[s.head] != ']' {
+			return s.fail(ErrInvalType, ExpectType)
+		}
+		s.tail = -1
+		if !s.emit(TokenVarTypeArrEnd) {
+			return false
+		}
+		s.head++
+		lists--
+	}
+}
+
+// directives scans the directives at the head if any.
+func (s *typeSystemScanner) directives() bool {
+	for s.skip(); s.head < len(s.str) && s.str[s.head] == '@'; s.skip() {
+		s.head++
+		if !s.name(TokenDirName, ExpectDirName) {
+			return false
+		}
+		s.skip()
+		if s.head < len(s.str) && s.str[s.head] == '(' {
+			if !s.arguments() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// arguments scans the directive arguments
+// beginning with the parenthesis at the head.
+func (s *typeSystemScanner) arguments() bool {
+	s.tail = -1
+	if !s.emit(TokenArgList) {
+		return false
+	}
+	s.head++
+	for {
+		if !s.name(TokenArgName, ExpectArgName) ||
+			!s.punct(':', ExpectColumnAfterArg) || !s.value() {
+			return false
+		}
+		s.skip()
+		if s.head < len(s.str) && s.str[s.head] == ')' {
+			break
+		}
+	}
+	s.tail = -1
+	if !s.emit(TokenArgListEnd) {
+		return false
+	}
+	s.head++
+	return true
+}
+
+// value scans the constant value at the head.
+// Nested lists and objects are scanned iteratively
+// to not exhaust the stack.
+func (s *typeSystemScanner) value() bool {
+	// stack holds the closing brackets of the open lists and objects.
+	var stack []byte
+	for {
+		s.skip()
+		if l := len(stack); l > 0 {
+			if c := stack[l-1]; s.head < len(s.str) && s.str[s.head] == c {
+				s.tail = -1
+				t := TokenArrEnd
+				if c == '}' {
+					t = TokenObjEnd
+				}
+				if !s.emit(t) {
+					return false
+				}
+				s.head++
+				if stack = stack[:l-1]; len(stack) < 1 {
+					return true
+				}
+				continue
+			} else if c == '}' {
+				if !s.name(TokenObjField, ExpectObjFieldName) ||
+					!s.punct(':', ExpectColObjFieldName) {
+					return false
+				}
+				s.skip()
+			}
+		}
+		if s.head >= len(s.str) {
+			return s.fail(ErrUnexpEOF, ExpectVal)
+		}
+		switch c := s.str[s.head]; {
+		case c == '"':
+			if !s.stringValue() {
+				return false
+			}
+		case c == '[' || c == '{':
+			s.tail = -1
+			t, end := TokenArr, byte(']')
+			if c == '{' {
+				t, end = TokenObj, '}'
+			}
+			if !s.emit(t) {
+				return false
+			}
+			s.head++
+			stack = append(stack, end)
+			continue
+		case c == '+' || c == '-' || charClass[c]&classDigit != 0:
+			s.tail = s.head
+			if code := s.scanNum(); code != 0 {
+				return s.fail(code, s.expect)
+			}
+			if !s.emit(s.token) {
+				return false
+			}
+		case charClass[c]&classNameStart != 0:
+			if code := s.scanName(); code != 0 {
+				return s.fail(code, ExpectVal)
+			}
+			t := TokenEnumVal
+			switch string(s.str[s.tail:s.head]) {
+			case "true":
+				s.tail, t = -1, TokenTrue
+			case "false":
+				s.tail, t = -1, TokenFalse
+			case "null":
+				s.tail, t = -1, TokenNull
+			}
+			if !s.emit(t) {
+				return false
+			}
+		default:
+			return s.fail(ErrUnexpToken, ExpectVal)
+		}
+		if len(stack) < 1 {
+			return true
+		}
+	}
+}
+
+// description scans the optional description at the head.
+func (s *typeSystemScanner) description() bool {
+	s.skip()
+	if s.head < len(s.str) && s.str[s.head] == '"' {
+		return s.stringValue()
+	}
+	return true
+}
+
+// stringValue scans the string or block string at the head.
+func (s *typeSystemScanner) stringValue() bool {
+	s.head++
+	s.tail = s.head
+	if s.head+1 < len(s.str) &&
+		s.str[s.head] == '"' &&
+		s.str[s.head+1] == '"' {
+		s.head += 2
+		s.tail = s.head
+		if code := s.scanBlockStr(); code != 0 {
+			return s.fail(code, ExpectEndOfBlockString)
+		}
+		if !s.emit(TokenStrBlock) {
+			return false
+		}
+		s.head += len(`"""`)
+		return true
+	}
+	if code := s.scanStr(); code != 0 {
+		return s.fail(code, s.expect)
+	}
+	if !s.emit(TokenStr) {
+		return false
+	}
+	s.head++
+	return true
+}
+
+// name scans the name at the head and calls fn for token t.
+func (s *typeSystemScanner) name(t Token, expect Expect) bool {
+	s.skip()
+	if s.head >= len(s.str) {
+		return s.fail(ErrUnexpEOF, expect)
+	}
+	if code := s.scanName(); code != 0 {
+		return s.fail(code, expect)
+	}
+	return s.emit(t)
+}
+
+// punct advances the head past punctuator b.
+func (s *typeSystemScanner) punct(b byte, expect Expect) bool {
+	s.skip()
+	if s.head >= len(s.str) {
+		return s.fail(ErrUnexpEOF, expect)
+	} else if s.str[s.head] != b {
+		return s.fail(ErrUnexpToken, expect)
+	}
+	s.head++
+	return true
+}
+
+// isDirectiveLocation returns true if n is
+// an executable or type system directive location.
+func isDirectiveLocation(n []byte) bool {
+	switch string(n) {
+	case "QUERY", "MUTATION", "SUBSCRIPTION", "FIELD",
+		"FRAGMENT_DEFINITION", "FRAGMENT_SPREAD", "INLINE_FRAGMENT",
+		"VARIABLE_DEFINITION",
+		"SCHEMA", "SCALAR", "OBJECT", "FIELD_DEFINITION",
+		"ARGUMENT_DEFINITION", "INTERFACE", "UNION", "ENUM",
+		"ENUM_VALUE", "INPUT_OBJECT", "INPUT_FIELD_DEFINITION":
+		return true
+	}
+	return false
+}
+
+// isHeadExecutableDefinition returns true if an executable
+// definition begins at the head.
+func (s *typeSystemScanner) isHeadExecutableDefinition() bool {
+	if s.str[s.head] == '{' {
+		return true
+	}
+	e := s.head
+	for e < len(s.str) && charClass[s.str[e]]&className != 0 {
+		e++
+	}
+	return isDefinitionKeyword(s.str[s.head:e])
+}
+
+// isHeadKeyword returns true if the name at the head is keyword k.
+func (s *typeSystemScanner) isHeadKeyword(k string) bool {
+	e := s.head + len(k)
+	return e <= len(s.str) && string(s.str[s.head:e]) == k &&
+		(e == len(s.str) || charClass[s.str[e]]&className == 0)
+}
+
+// skip advances the head past ignored characters and comments.
+func (s *typeSystemScanner) skip() {
+	if s.head != s.skipped {
+		s.end = s.head
+	}
+	for s.head < len(s.str) {
+		if s.isHeadIgnored() {
+			s.skipIgnored()
+		} else if s.str[s.head] == '#' {
+			s.skipComment()
+		} else {
+			break
+		}
+	}
+	s.skipped = s.head
+}
+
+// emit calls fn for token t.
+func (s *typeSystemScanner) emit(t Token) bool {
+	s.token = t
+	s.tokens++
+	if s.fn(s.Iterator) || s.abortErr != nil {
+		return s.fail(ErrCallbackFn, 0)
+	}
+	return true
+}
+
+// fail sets err at the head.
+func (s *typeSystemScanner) fail(code ErrorCode, expect Expect) bool {
+	s.err = errorAt(s.str, s.head, code)
+	s.err.Expectation = expect
+	s.err.Err = s.abortErr
+	s.err.DefinitionIndex, s.err.TokenOrdinal = s.defs-1, s.tokens
+	if code == ErrCallbackFn {
+		s.err.TokenOrdinal--
+	}
+	return false
+}
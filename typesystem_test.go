@@ -0,0 +1,442 @@
+package gqlscan_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testdataTypeSystem = []TestInput{
+	Input(`scalar Date`,
+		Token(gqlscan.TokenDefScalar),
+		Token(gqlscan.TokenTypeName, "Date"),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`"""
+	A point in time.
+	"""
+	scalar Time @specifiedBy(url: "https://example.com") # comment
+	type Query`,
+		Token(gqlscan.TokenStrBlock, "\n\tA point in time.\n\t"),
+		Token(gqlscan.TokenDefScalar),
+		Token(gqlscan.TokenTypeName, "Time"),
+		Token(gqlscan.TokenDirName, "specifiedBy"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "url"),
+		Token(gqlscan.TokenStr, "https://example.com"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenDefEnd),
+		Token(gqlscan.TokenDefType),
+		Token(gqlscan.TokenTypeName, "Query"),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`type User implements & Node & Entity @key(fields: "id") {
+		"The ID." id: ID!
+		friends(
+			first: Int = 10 @deprecated
+			filter: [Filter!] = [{a: -1.5e3, b: [true null ASC]}]
+		): [User!]! @auth
+	}`,
+		Token(gqlscan.TokenDefType),
+		Token(gqlscan.TokenTypeName, "User"),
+		Token(gqlscan.TokenImplements, "Node"),
+		Token(gqlscan.TokenImplements, "Entity"),
+		Token(gqlscan.TokenDirName, "key"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "fields"),
+		Token(gqlscan.TokenStr, "id"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenDefBody),
+		Token(gqlscan.TokenStr, "The ID."),
+		Token(gqlscan.TokenFieldDef, "id"),
+		Token(gqlscan.TokenVarTypeName, "ID"),
+		Token(gqlscan.TokenVarTypeNotNull),
+		Token(gqlscan.TokenFieldDef, "friends"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgDef, "first"),
+		Token(gqlscan.TokenVarTypeName, "Int"),
+		Token(gqlscan.TokenInt, "10"),
+		Token(gqlscan.TokenDirName, "deprecated"),
+		Token(gqlscan.TokenArgDef, "filter"),
+		Token(gqlscan.TokenVarTypeArr),
+		Token(gqlscan.TokenVarTypeName, "Filter"),
+		Token(gqlscan.TokenVarTypeNotNull),
+		Token(gqlscan.TokenVarTypeArrEnd),
+		Token(gqlscan.TokenArr),
+		Token(gqlscan.TokenObj),
+		Token(gqlscan.TokenObjField, "a"),
+		Token(gqlscan.TokenFloat, "-1.5e3"),
+		Token(gqlscan.TokenObjField, "b"),
+		Token(gqlscan.TokenArr),
+		Token(gqlscan.TokenTrue),
+		Token(gqlscan.TokenNull),
+		Token(gqlscan.TokenEnumVal, "ASC"),
+		Token(gqlscan.TokenArrEnd),
+		Token(gqlscan.TokenObjEnd),
+		Token(gqlscan.TokenArrEnd),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenVarTypeArr),
+		Token(gqlscan.TokenVarTypeName, "User"),
+		Token(gqlscan.TokenVarTypeNotNull),
+		Token(gqlscan.TokenVarTypeArrEnd),
+		Token(gqlscan.TokenVarTypeNotNull),
+		Token(gqlscan.TokenDirName, "auth"),
+		Token(gqlscan.TokenDefBodyEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`interface Node implements Entity { id: ID! }`,
+		Token(gqlscan.TokenDefInterface),
+		Token(gqlscan.TokenTypeName, "Node"),
+		Token(gqlscan.TokenImplements, "Entity"),
+		Token(gqlscan.TokenDefBody),
+		Token(gqlscan.TokenFieldDef, "id"),
+		Token(gqlscan.TokenVarTypeName, "ID"),
+		Token(gqlscan.TokenVarTypeNotNull),
+		Token(gqlscan.TokenDefBodyEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`union SearchResult @d = | User | Post union U = A`,
+		Token(gqlscan.TokenDefUnion),
+		Token(gqlscan.TokenTypeName, "SearchResult"),
+		Token(gqlscan.TokenDirName, "d"),
+		Token(gqlscan.TokenUnionMember, "User"),
+		Token(gqlscan.TokenUnionMember, "Post"),
+		Token(gqlscan.TokenDefEnd),
+		Token(gqlscan.TokenDefUnion),
+		Token(gqlscan.TokenTypeName, "U"),
+		Token(gqlscan.TokenUnionMember, "A"),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`enum Color {
+		RED
+		"Deprecated." GREEN @deprecated(reason: "no")
+		BLUE, trueish
+	}`,
+		Token(gqlscan.TokenDefEnum),
+		Token(gqlscan.TokenTypeName, "Color"),
+		Token(gqlscan.TokenDefBody),
+		Token(gqlscan.TokenEnumValDef, "RED"),
+		Token(gqlscan.TokenStr, "Deprecated."),
+		Token(gqlscan.TokenEnumValDef, "GREEN"),
+		Token(gqlscan.TokenDirName, "deprecated"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "reason"),
+		Token(gqlscan.TokenStr, "no"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenEnumValDef, "BLUE"),
+		Token(gqlscan.TokenEnumValDef, "trueish"),
+		Token(gqlscan.TokenDefBodyEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`input Filter @oneOf {
+		a: Int = 1 @d
+		b: [[String]!] = {}
+	}`,
+		Token(gqlscan.TokenDefInput),
+		Token(gqlscan.TokenTypeName, "Filter"),
+		Token(gqlscan.TokenDirName, "oneOf"),
+		Token(gqlscan.TokenDefBody),
+		Token(gqlscan.TokenFieldDef, "a"),
+		Token(gqlscan.TokenVarTypeName, "Int"),
+		Token(gqlscan.TokenInt, "1"),
+		Token(gqlscan.TokenDirName, "d"),
+		Token(gqlscan.TokenFieldDef, "b"),
+		Token(gqlscan.TokenVarTypeArr),
+		Token(gqlscan.TokenVarTypeArr),
+		Token(gqlscan.TokenVarTypeName, "String"),
+		Token(gqlscan.TokenVarTypeArrEnd),
+		Token(gqlscan.TokenVarTypeNotNull),
+		Token(gqlscan.TokenVarTypeArrEnd),
+		Token(gqlscan.TokenObj),
+		Token(gqlscan.TokenObjEnd),
+		Token(gqlscan.TokenDefBodyEnd),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`"The schema." schema @link(url: "x") {
+		query: Query, mutation: Mutation
+		subscription: Subscription
+	}
+	type Query`,
+		Token(gqlscan.TokenStr, "The schema."),
+		Token(gqlscan.TokenDefSchema),
+		Token(gqlscan.TokenDirName, "link"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgName, "url"),
+		Token(gqlscan.TokenStr, "x"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenDefBody),
+		Token(gqlscan.TokenRootQry, "Query"),
+		Token(gqlscan.TokenRootMut, "Mutation"),
+		Token(gqlscan.TokenRootSub, "Subscription"),
+		Token(gqlscan.TokenDefBodyEnd),
+		Token(gqlscan.TokenDefEnd),
+		Token(gqlscan.TokenDefType),
+		Token(gqlscan.TokenTypeName, "Query"),
+		Token(gqlscan.TokenDefEnd),
+	),
+	Input(`"Requires a role." directive @auth(role: String! = "user")
+	repeatable on FIELD_DEFINITION | OBJECT
+	directive @tag on | QUERY`,
+		Token(gqlscan.TokenStr, "Requires a role."),
+		Token(gqlscan.TokenDefDir),
+		Token(gqlscan.TokenDirName, "auth"),
+		Token(gqlscan.TokenArgList),
+		Token(gqlscan.TokenArgDef, "role"),
+		Token(gqlscan.TokenVarTypeName, "String"),
+		Token(gqlscan.TokenVarTypeNotNull),
+		Token(gqlscan.TokenStr, "user"),
+		Token(gqlscan.TokenArgListEnd),
+		Token(gqlscan.TokenDirRepeatable),
+		Token(gqlscan.TokenDirLocation, "FIELD_DEFINITION"),
+		Token(gqlscan.TokenDirLocation, "OBJECT"),
+		Token(gqlscan.TokenDefEnd),
+		Token(gqlscan.TokenDefDir),
+		Token(gqlscan.TokenDirName, "tag"),
+		Token(gqlscan.TokenDirLocation, "QUERY"),
+		Token(gqlscan.TokenDefEnd),
+	),
+}
+
+func TestScanTypeSystem(t *testing.T) {
+	for _, td := range testdataTypeSystem {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			err := gqlscan.ScanTypeSystem(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) bool {
+					require.True(t, j < len(td.expect))
+					require.Equal(t, td.expect[j].Type.String(), i.Token().String(),
+						"unexpected type at index %d (%s)", j, td.expect[j].Decl)
+					require.Equal(t, td.expect[j].Value, string(i.Value()),
+						"unexpected value at index %d (%s)", j, td.expect[j].Decl)
+					j++
+					return false
+				},
+			)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanTypeSystemDefinitionEnd(t *testing.T) {
+	src := []byte("scalar A @d(x: 1) # c\n union B = C | D\n type E { f: G }")
+	var ends []int
+	err := gqlscan.ScanTypeSystem(src, func(i *gqlscan.Iterator) bool {
+		if i.Token() == gqlscan.TokenDefEnd {
+			ends = append(ends, i.IndexHead())
+		}
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, []int{16, 37, 54}, ends)
+}
+
+func TestScanTypeSystemErr(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `query { a }`,
+			"error at index 0 ('q'): unexpected token; " +
+				"expected type system definition"},
+		{decl(1), `"desc"`,
+			"error at index 6: unexpected end of file; " +
+				"expected type system definition"},
+		{decl(1), `type`,
+			"error at index 4: unexpected end of file; expected type name"},
+		{decl(1), `type T { }`,
+			"error at index 9 ('}'): unexpected token; " +
+				"expected field definition"},
+		{decl(1), `type T { a }`,
+			"error at index 11 ('}'): unexpected token; " +
+				"expected column after field name"},
+		{decl(1), `type T { a: [Int }`,
+			"error at index 17 ('}'): invalid type; expected type"},
+		{decl(1), `type T { a: Int = 1 }`,
+			"error at index 16 ('='): unexpected token; " +
+				"expected field definition"},
+		{decl(1), `type T { a(): Int }`,
+			"error at index 11 (')'): unexpected token; " +
+				"expected argument name"},
+		{decl(1), `type T implements`,
+			"error at index 17: unexpected end of file; " +
+				"expected interface name"},
+		{decl(1), `union U = A |`,
+			"error at index 13: unexpected end of file; " +
+				"expected union member type"},
+		{decl(1), `enum E { true }`,
+			"error at index 9 ('t'): unexpected token; expected enum value"},
+		{decl(1), `input I { a: Int = $v }`,
+			"error at index 19 ('$'): unexpected token; expected value"},
+		{decl(1), `input I { a: Int = +1 }`,
+			"error at index 19 ('+'): invalid number value; expected value"},
+		{decl(1), `input I { a: Int = 1e5x }`,
+			"error at index 22 ('x'): name immediately following number"},
+		{decl(1), `input I { a: Int = 01 }`,
+			"error at index 20 ('1'): invalid number value; expected value"},
+		{decl(1), `schema @d`,
+			"error at index 9: unexpected end of file; " +
+				"expected definition body"},
+		{decl(1), `schema {}`,
+			"error at index 8 ('}'): unexpected token; " +
+				"expected root operation type"},
+		{decl(1), `schema { fragment: F }`,
+			"error at index 9 ('f'): unexpected token; " +
+				"expected root operation type"},
+		{decl(1), `schema { query Q }`,
+			"error at index 15 ('Q'): unexpected token; " +
+				"expected column after root operation"},
+		{decl(1), `schema { query: }`,
+			"error at index 16 ('}'): unexpected token; expected type name"},
+		{decl(1), `directive auth on FIELD`,
+			"error at index 10 ('a'): unexpected token; " +
+				"expected directive name"},
+		{decl(1), `directive @a(x: Int)`,
+			"error at index 20: unexpected end of file; " +
+				"expected keyword 'on'"},
+		{decl(1), `directive @a repeatable of FIELD`,
+			"error at index 24 ('o'): unexpected token; " +
+				"expected keyword 'on'"},
+		{decl(1), `directive @a on FIELDS`,
+			"error at index 16 ('F'): unexpected token; " +
+				"expected directive location"},
+		{decl(1), `directive @a on FIELD |`,
+			"error at index 23: unexpected end of file; " +
+				"expected directive location"},
+		{decl(1), `scalar S @d(x: "a)`,
+			"error at index 18: unexpected end of file; " +
+				"expected end of string"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanTypeSystem([]byte(td.input), noop)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
+
+func TestScanTypeSystemCallbackErr(t *testing.T) {
+	err := gqlscan.ScanTypeSystem(
+		[]byte(`type T { a: Int }`),
+		func(i *gqlscan.Iterator) bool {
+			return i.Token() == gqlscan.TokenFieldDef
+		},
+	)
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+	require.Equal(t, 10, err.Index)
+}
+
+func TestScanTypeSystemDeepNesting(t *testing.T) {
+	const depth = 5_000_000
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range []struct {
+		input  string
+		expect string
+	}{
+		{"type T { f(a: Int = " + strings.Repeat("[", depth),
+			"error at index 5000020: unexpected end of file; expected value"},
+		{"input I { f: " + strings.Repeat("[", depth),
+			"error at index 5000013: unexpected end of file; expected type"},
+		{"type T { f(a: Int = " + strings.Repeat("[", depth) +
+			strings.Repeat("]", depth) + ") : Int }", ""},
+		{"type T @d(a: " + strings.Repeat("{a:", depth),
+			"error at index 15000013: unexpected end of file; expected value"},
+	} {
+		err := gqlscan.ScanDocument([]byte(td.input), noop)
+		require.Equal(t, td.expect, err.Error())
+	}
+}
+
+func TestScanTypeSystemAllocs(t *testing.T) {
+	src := []byte(`type Query { users(first: Int = 10): [User!]! @auth }`)
+	noop := func(*gqlscan.Iterator) bool { return false }
+	allocs := testing.AllocsPerRun(100, func() {
+		gqlscan.ScanTypeSystem(src, noop)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestScanDocument(t *testing.T) {
+	all := append(append([]TestInput(nil), testdata...), testdataTypeSystem...)
+	all = append(all, Input(`type Query { a: Int }
+	query Q { a }
+	"Scalar." scalar X {b}`,
+		Token(gqlscan.TokenDefType),
+		Token(gqlscan.TokenTypeName, "Query"),
+		Token(gqlscan.TokenDefBody),
+		Token(gqlscan.TokenFieldDef, "a"),
+		Token(gqlscan.TokenVarTypeName, "Int"),
+		Token(gqlscan.TokenDefBodyEnd),
+		Token(gqlscan.TokenDefEnd),
+		Token(gqlscan.TokenDefQry),
+		Token(gqlscan.TokenOprName, "Q"),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "a"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+		Token(gqlscan.TokenStr, "Scalar."),
+		Token(gqlscan.TokenDefScalar),
+		Token(gqlscan.TokenTypeName, "X"),
+		Token(gqlscan.TokenDefEnd),
+		Token(gqlscan.TokenDefQry),
+		Token(gqlscan.TokenSet),
+		Token(gqlscan.TokenField, "b"),
+		Token(gqlscan.TokenSetEnd),
+		Token(gqlscan.TokenDefEnd),
+	))
+	for _, td := range all {
+		t.Run(td.decl, func(t *testing.T) {
+			j := 0
+			err := gqlscan.ScanDocument(
+				[]byte(td.input),
+				func(i *gqlscan.Iterator) bool {
+					require.True(t, j < len(td.expect))
+					require.Equal(t, td.expect[j].Type.String(), i.Token().String(),
+						"unexpected type at index %d (%s)", j, td.expect[j].Decl)
+					require.Equal(t, td.expect[j].Value, string(i.Value()),
+						"unexpected value at index %d (%s)", j, td.expect[j].Decl)
+					j++
+					return false
+				},
+			)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, j)
+		})
+	}
+}
+
+func TestScanDocumentErr(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `type T blah`,
+			"error at index 7 ('b'): unexpected token; expected definition"},
+		{decl(1), `type T mutation { a( } type U`,
+			"error at index 21 ('}'): unexpected token; " +
+				"expected argument name"},
+		{decl(1), `{a} type T { }`,
+			"error at index 13 ('}'): unexpected token; " +
+				"expected field definition"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanDocument([]byte(td.input), noop)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+
+	err := gqlscan.ScanDocument(
+		[]byte(`{a} type U`),
+		func(i *gqlscan.Iterator) bool {
+			return i.Token() == gqlscan.TokenDefEnd
+		},
+	)
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+}
@@ -0,0 +1,92 @@
+package gqlscan
+
+// VariableDefinition is a variable definition of an operation.
+type VariableDefinition struct {
+	// Operation is the name of the operation
+	// and is empty for anonymous operations.
+	Operation Span
+
+	// Name is the name of the variable excluding the dollar sign.
+	Name Span
+
+	// Type is the type designation, for example `[ID!]!`.
+	// Type is only valid until the callback returns.
+	Type []byte
+
+	// HasDefault is true if the variable has a default value.
+	HasDefault bool
+}
+
+// ScanVariables scans str calling fn for every variable definition
+// of every operation in order of appearance.
+// fn may be called for the variables preceding an error.
+func ScanVariables(str []byte, fn func(VariableDefinition)) Error {
+	var (
+		v         VariableDefinition
+		inVar     bool
+		levelArgs int
+		typ       = make([]byte, 0, 64)
+	)
+	flush := func() {
+		if inVar {
+			v.Type = typ
+			fn(v)
+			inVar, v.HasDefault, typ = false, false, typ[:0]
+		}
+	}
+	return ScanAll(str, func(i *Iterator) {
+		switch t := i.Token(); t {
+		case TokenDefQry, TokenDefMut, TokenDefSub:
+			v.Operation = Span{}
+		case TokenOprName:
+			v.Operation = Span{Tail: i.tail, Head: i.head}
+		case TokenVarName:
+			flush()
+			inVar, v.Name = true, Span{Tail: i.tail, Head: i.head}
+		case TokenVarListEnd:
+			flush()
+		case TokenArgList:
+			levelArgs++
+		case TokenArgListEnd:
+			levelArgs--
+		case TokenVarTypeArr, TokenVarTypeName,
+			TokenVarTypeArrEnd, TokenVarTypeNotNull:
+			typ = i.AppendTypeDesignation(typ)
+		default:
+			if inVar && levelArgs < 1 && isValueToken(t) {
+				v.HasDefault = true
+			}
+		}
+	})
+}
+
+// AppendTypeDesignation appends the part of the type designation
+// represented by the current token to dst and returns the extended
+// buffer. Calling it for every token of a variable type assembles
+// the canonical type designation, for example `[[ID!]!]!`.
+// dst is returned unchanged for tokens other than TokenVarTypeArr,
+// TokenVarTypeName, TokenVarTypeArrEnd and TokenVarTypeNotNull.
+func (i *Iterator) AppendTypeDesignation(dst []byte) []byte {
+	switch i.token {
+	case TokenVarTypeArr:
+		return append(dst, '[')
+	case TokenVarTypeArrEnd:
+		return append(dst, ']')
+	case TokenVarTypeNotNull:
+		return append(dst, '!')
+	case TokenVarTypeName:
+		return append(dst, i.Value()...)
+	}
+	return dst
+}
+
+// isValueToken returns true if t is a value token.
+func isValueToken(t Token) bool {
+	switch t {
+	case TokenEnumVal, TokenArr, TokenArrEnd, TokenStr, TokenStrBlock,
+		TokenInt, TokenFloat, TokenTrue, TokenFalse, TokenNull,
+		TokenVarRef, TokenObj, TokenObjEnd, TokenObjField:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package gqlscan_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanVariables(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect []string
+	}{
+		{decl(1), `{a}`, nil},
+		{decl(1), `query Q($a: Int, $b: [ID!]! = ["x"], $c: [[Boolean]!] = null)` +
+			`{ f(a: $a) }` +
+			`mutation($d: String @dir) { g }` +
+			`fragment F on T { h }` +
+			`subscription S($e: Input = {f: [1]}) { i }`,
+			[]string{
+				"Q a Int false",
+				"Q b [ID!]! true",
+				"Q c [[Boolean]!] true",
+				" d String false",
+				"S e Input true",
+			}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			src := []byte(td.input)
+			var actual []string
+			err := gqlscan.ScanVariables(src, func(v gqlscan.VariableDefinition) {
+				actual = append(actual, fmt.Sprintf(
+					"%s %s %s %t",
+					src[v.Operation.Tail:v.Operation.Head],
+					src[v.Name.Tail:v.Name.Head],
+					v.Type, v.HasDefault,
+				))
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, td.expect, actual)
+		})
+	}
+}
+
+func TestScanVariablesErr(t *testing.T) {
+	var names []string
+	src := []byte(`query($a: Int, $b: ) { a }`)
+	err := gqlscan.ScanVariables(src, func(v gqlscan.VariableDefinition) {
+		names = append(names, string(src[v.Name.Tail:v.Name.Head]))
+	})
+	require.Equal(t, "error at index 19 (')'): unexpected token; "+
+		"expected variable type", err.Error())
+	require.Equal(t, []string{"a"}, names)
+}
+
+func TestAppendTypeDesignation(t *testing.T) {
+	var types []string
+	var typ []byte
+	err := gqlscan.ScanAll([]byte(
+		`query($a: [ [ ID! ] ! ] !, $b: String = "x" $c: [[[Int]]]) { f }`,
+	), func(i *gqlscan.Iterator) {
+		switch i.Token() {
+		case gqlscan.TokenVarName:
+			if typ != nil {
+				types = append(types, string(typ))
+			}
+			typ = typ[:0]
+		case gqlscan.TokenVarListEnd:
+			types = append(types, string(typ))
+		}
+		typ = i.AppendTypeDesignation(typ)
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, []string{"[[ID!]!]!", "String", "[[[Int]]]"}, types)
+
+	src := []byte(`query($a: [[ID!]!]!) { f }`)
+	fn := func(i *gqlscan.Iterator) { typ = i.AppendTypeDesignation(typ) }
+	allocs := testing.AllocsPerRun(100, func() {
+		typ = typ[:0]
+		gqlscan.ScanAll(src, fn)
+	})
+	require.Zero(t, allocs)
+}
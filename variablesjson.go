@@ -0,0 +1,220 @@
+package gqlscan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ScanVariablesJSON calls fn for every member of the JSON object
+// variables with the span of its key excluding the quotes and the span
+// of its raw value. An empty or null variables is an empty object.
+// If variables isn't a JSON object then an error with code
+// ErrInvalRequest is returned.
+// If fn returns true then an error with code ErrCallbackFn is returned.
+func ScanVariablesJSON(
+	variables []byte,
+	fn func(key, value Span) (err bool),
+) Error {
+	x := skipJSONSpace(variables, 0)
+	if x >= len(variables) {
+		return Error{}
+	}
+	if v := skipJSONLiteral(variables, x, "null"); v > -1 {
+		if x = skipJSONSpace(variables, v); x < len(variables) {
+			return errorAt(variables, x, ErrInvalRequest)
+		}
+		return Error{}
+	}
+	if variables[x] != '{' {
+		return errorAt(variables, x, ErrInvalRequest)
+	}
+	if x = skipJSONSpace(variables, x+1); x < len(variables) &&
+		variables[x] == '}' {
+		x++
+	} else {
+		for {
+			key := x
+			if x = skipJSONString(variables, x); x < 0 {
+				return errorAt(variables, key, ErrInvalRequest)
+			}
+			k := Span{Tail: key + 1, Head: x - 1}
+			if x = skipJSONSpace(variables, x); x >= len(variables) ||
+				variables[x] != ':' {
+				return errorAt(variables, x, ErrInvalRequest)
+			}
+			v := skipJSONSpace(variables, x+1)
+			if x = skipJSONValue(variables, v); x < 0 {
+				return errorAt(variables, v, ErrInvalRequest)
+			}
+			if fn(k, Span{Tail: v, Head: x}) {
+				return errorAt(variables, key, ErrCallbackFn)
+			}
+			if x = skipJSONSpace(variables, x); x >= len(variables) {
+				return errorAt(variables, x, ErrInvalRequest)
+			}
+			if variables[x] == '}' {
+				x++
+				break
+			}
+			if variables[x] != ',' {
+				return errorAt(variables, x, ErrInvalRequest)
+			}
+			x = skipJSONSpace(variables, x+1)
+		}
+	}
+	if x = skipJSONSpace(variables, x); x < len(variables) {
+		return errorAt(variables, x, ErrInvalRequest)
+	}
+	return Error{}
+}
+
+// VariableProblemKind is the kind of a VariableProblem.
+type VariableProblemKind int8
+
+const (
+	_ VariableProblemKind = iota
+
+	// VariableMissing is a variable of a non-null type
+	// without a default value that's absent in the variables.
+	VariableMissing
+
+	// VariableNull is a variable of a non-null type
+	// that's null in the variables.
+	VariableNull
+
+	// VariableUnknown is a variable in the variables
+	// the operation doesn't define.
+	VariableUnknown
+)
+
+// VariableProblem is a mismatch between the variables of a request
+// and the variable definitions of its operation.
+type VariableProblem struct {
+	Kind VariableProblemKind
+
+	// Name is the name of the variable.
+	Name string
+
+	// Type is the type designation of the variable
+	// and is empty for VariableUnknown.
+	Type string
+
+	// Index is the index of the name of the variable definition
+	// in the document for VariableMissing and the index
+	// of the key in the variables otherwise.
+	Index int
+}
+
+func (p VariableProblem) Error() string {
+	switch p.Kind {
+	case VariableMissing:
+		return fmt.Sprintf("variable %q of type %s is missing", p.Name, p.Type)
+	case VariableNull:
+		return fmt.Sprintf("variable %q of type %s must not be null", p.Name, p.Type)
+	case VariableUnknown:
+		return fmt.Sprintf("variable %q is not defined", p.Name)
+	}
+	return ""
+}
+
+// CheckVariables checks the JSON object variables against the variable
+// definitions of the operation with the given name in document src
+// and returns the problems found in order of the variable definitions
+// followed by the unknown variables in order of appearance.
+// If operationName is empty then src must contain exactly one operation.
+// Returns an error if src or variables is invalid or if the operation
+// isn't found.
+func CheckVariables(
+	src []byte,
+	operationName string,
+	variables []byte,
+) ([]VariableProblem, error) {
+	type member struct {
+		Span
+		value Span
+		used  bool
+	}
+	var members []member
+	if err := ScanVariablesJSON(variables, func(key, value Span) bool {
+		members = append(members, member{Span: key, value: value})
+		return false
+	}); err.IsErr() {
+		return nil, err
+	}
+
+	var (
+		operations int
+		found      bool
+	)
+	if err := ScanAll(src, func(i *Iterator) {
+		switch i.Token() {
+		case TokenDefQry, TokenDefMut, TokenDefSub:
+			operations++
+			if operationName == "" {
+				found = true
+			}
+		case TokenOprName:
+			if operationName != "" && string(i.Value()) == operationName {
+				found = true
+			}
+		}
+	}); err.IsErr() {
+		return nil, err
+	}
+	switch {
+	case operationName == "" && operations > 1:
+		return nil, errors.New("operation name required")
+	case operations < 1:
+		return nil, errors.New("no operation")
+	case !found:
+		return nil, fmt.Errorf("operation %q not found", operationName)
+	}
+
+	var problems []VariableProblem
+	if err := ScanVariables(src, func(v VariableDefinition) {
+		if operationName != "" &&
+			string(src[v.Operation.Tail:v.Operation.Head]) != operationName {
+			return
+		}
+		name := src[v.Name.Tail:v.Name.Head]
+		nonNull := v.Type[len(v.Type)-1] == '!'
+		var m *member
+		for x := range members {
+			if bytes.Equal(variables[members[x].Tail:members[x].Head], name) {
+				// The last duplicate key takes effect.
+				m = &members[x]
+				m.used = true
+			}
+		}
+		switch {
+		case m == nil && nonNull && !v.HasDefault:
+			problems = append(problems, VariableProblem{
+				Kind:  VariableMissing,
+				Name:  string(name),
+				Type:  string(v.Type),
+				Index: v.Name.Tail,
+			})
+		case m != nil && nonNull &&
+			string(variables[m.value.Tail:m.value.Head]) == "null":
+			problems = append(problems, VariableProblem{
+				Kind:  VariableNull,
+				Name:  string(name),
+				Type:  string(v.Type),
+				Index: m.Tail - 1,
+			})
+		}
+	}); err.IsErr() {
+		return nil, err
+	}
+	for _, m := range members {
+		if !m.used {
+			problems = append(problems, VariableProblem{
+				Kind:  VariableUnknown,
+				Name:  string(variables[m.Tail:m.Head]),
+				Index: m.Tail - 1,
+			})
+		}
+	}
+	return problems, nil
+}
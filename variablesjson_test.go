@@ -0,0 +1,142 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanVariablesJSON(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect []string
+	}{
+		{decl(1), ``, nil},
+		{decl(1), ` null `, nil},
+		{decl(1), `{}`, nil},
+		{decl(1), `{"a": 1, "b" : {"c": [null, "}"]}, "d":"x"}`, []string{
+			"a", "1", "b", `{"c": [null, "}"]}`, "d", `"x"`,
+		}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			var actual []string
+			in := []byte(td.input)
+			err := gqlscan.ScanVariablesJSON(in, func(k, v gqlscan.Span) bool {
+				actual = append(actual,
+					string(in[k.Tail:k.Head]), string(in[v.Tail:v.Head]))
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, td.expect, actual)
+		})
+	}
+}
+
+func TestScanVariablesJSONErr(t *testing.T) {
+	noop := func(k, v gqlscan.Span) bool { return false }
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `[]`, "error at index 0 ('['): invalid request"},
+		{decl(1), `null x`, "error at index 5 ('x'): invalid request"},
+		{decl(1), `{"a" 1}`, "error at index 5 ('1'): invalid request"},
+		{decl(1), `{"a": }`, "error at index 6 ('}'): invalid request"},
+		{decl(1), `{"a": 1`, "error at index 7 (0x0): invalid request"},
+		{decl(1), `{"a": 1,}`, "error at index 8 ('}'): invalid request"},
+		{decl(1), `{"a": 1}}`, "error at index 8 ('}'): invalid request"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanVariablesJSON([]byte(td.input), noop)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+
+	err := gqlscan.ScanVariablesJSON([]byte(`{"a": 1, "b": 2}`),
+		func(k, v gqlscan.Span) bool { return v.Tail > 10 })
+	require.Equal(t, "error at index 9 ('\"'): callback function returned error",
+		err.Error())
+}
+
+func TestCheckVariables(t *testing.T) {
+	src := []byte(`
+		query A($id: ID!, $n: Int = 1, $m: Int!, $d: [ID!]! = [], $o: [Int]) {
+			a(id: $id, n: $n, m: $m)
+		}
+		query B($x: String!) { b(x: $x) }
+	`)
+	for _, td := range []struct {
+		decl          string
+		operationName string
+		variables     string
+		expect        []string
+	}{
+		{decl(1), "A", `{"id": "1", "m": 2}`, nil},
+		{decl(1), "A", `{"id": "1", "n": null, "m": 2, "o": null}`, nil},
+		{decl(1), "A", `{"m": null, "x": 1, "d": null}`, []string{
+			`variable "id" of type ID! is missing`,
+			`variable "m" of type Int! must not be null`,
+			`variable "d" of type [ID!]! must not be null`,
+			`variable "x" is not defined`,
+		}},
+		{decl(1), "B", ``, []string{
+			`variable "x" of type String! is missing`,
+		}},
+		{decl(1), "B", `{"id": 1, "x": "y"}`, []string{
+			`variable "id" is not defined`,
+		}},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			problems, err := gqlscan.CheckVariables(
+				src, td.operationName, []byte(td.variables),
+			)
+			require.NoError(t, err)
+			var actual []string
+			for _, p := range problems {
+				actual = append(actual, p.Error())
+			}
+			require.Equal(t, td.expect, actual)
+		})
+	}
+}
+
+func TestCheckVariablesIndex(t *testing.T) {
+	src := []byte(`query ($a: Int!) { f }`)
+	vars := []byte(`{"b": 1}`)
+	problems, err := gqlscan.CheckVariables(src, "", vars)
+	require.NoError(t, err)
+	require.Equal(t, []gqlscan.VariableProblem{
+		{Kind: gqlscan.VariableMissing, Name: "a", Type: "Int!", Index: 8},
+		{Kind: gqlscan.VariableUnknown, Name: "b", Index: 1},
+	}, problems)
+}
+
+func TestCheckVariablesErr(t *testing.T) {
+	for _, td := range []struct {
+		decl          string
+		src           string
+		operationName string
+		variables     string
+		expect        string
+	}{
+		{decl(1), `{a(}`, "", `{}`,
+			"error at index 3 ('}'): unexpected token; expected argument name"},
+		{decl(1), `{a}`, "", `{`,
+			"error at index 1 (0x0): invalid request"},
+		{decl(1), `fragment F on T {a}`, "", `{}`, "no operation"},
+		{decl(1), `query A {a} query B {b}`, "", `{}`,
+			"operation name required"},
+		{decl(1), `query A {a}`, "C", `{}`, `operation "C" not found`},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			_, err := gqlscan.CheckVariables(
+				[]byte(td.src), td.operationName, []byte(td.variables),
+			)
+			require.EqualError(t, err, td.expect)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package gqlscan
+
+// Visitor is visited by ScanVisitor for every token.
+// If a method returns true then the scan is stopped and
+// an error with code ErrCallbackFn is returned.
+// Embed NopVisitor to implement only some of the methods.
+//
+// WARNING: *Iterator passed to the methods should never be aliased
+// and used after ScanVisitor returns!
+type Visitor interface {
+	// OnDefinition is called for TokenDefQry, TokenDefMut,
+	// TokenDefSub and TokenDefFrag.
+	OnDefinition(*Iterator) (err bool)
+	OnDefinitionEnd(*Iterator) (err bool)
+	OnOperationName(*Iterator) (err bool)
+	OnFragmentName(*Iterator) (err bool)
+	OnTypeCondition(*Iterator) (err bool)
+	OnDirective(*Iterator) (err bool)
+	OnVariableList(*Iterator) (err bool)
+	OnVariableListEnd(*Iterator) (err bool)
+	OnVariable(*Iterator) (err bool)
+
+	// OnVariableType is called for TokenVarTypeName, TokenVarTypeArr,
+	// TokenVarTypeArrEnd and TokenVarTypeNotNull.
+	OnVariableType(*Iterator) (err bool)
+	OnSelectionSet(*Iterator) (err bool)
+	OnSelectionSetEnd(*Iterator) (err bool)
+	OnFieldAlias(*Iterator) (err bool)
+	OnField(*Iterator) (err bool)
+	OnFragmentInline(*Iterator) (err bool)
+	OnFragmentSpread(*Iterator) (err bool)
+	OnArgList(*Iterator) (err bool)
+	OnArgListEnd(*Iterator) (err bool)
+	OnArg(*Iterator) (err bool)
+
+	// OnValueStr is called for TokenStr and TokenStrBlock.
+	OnValueStr(*Iterator) (err bool)
+	OnValueInt(*Iterator) (err bool)
+	OnValueFloat(*Iterator) (err bool)
+
+	// OnValueBool is called for TokenTrue and TokenFalse.
+	OnValueBool(*Iterator) (err bool)
+	OnValueNull(*Iterator) (err bool)
+	OnValueEnum(*Iterator) (err bool)
+	OnValueVarRef(*Iterator) (err bool)
+	OnValueList(*Iterator) (err bool)
+	OnValueListEnd(*Iterator) (err bool)
+	OnValueObject(*Iterator) (err bool)
+	OnValueObjectEnd(*Iterator) (err bool)
+	OnValueObjectField(*Iterator) (err bool)
+}
+
+// NopVisitor is a Visitor whose methods do nothing.
+type NopVisitor struct{}
+
+func (NopVisitor) OnDefinition(*Iterator) bool       { return false }
+func (NopVisitor) OnDefinitionEnd(*Iterator) bool    { return false }
+func (NopVisitor) OnOperationName(*Iterator) bool    { return false }
+func (NopVisitor) OnFragmentName(*Iterator) bool     { return false }
+func (NopVisitor) OnTypeCondition(*Iterator) bool    { return false }
+func (NopVisitor) OnDirective(*Iterator) bool        { return false }
+func (NopVisitor) OnVariableList(*Iterator) bool     { return false }
+func (NopVisitor) OnVariableListEnd(*Iterator) bool  { return false }
+func (NopVisitor) OnVariable(*Iterator) bool         { return false }
+func (NopVisitor) OnVariableType(*Iterator) bool     { return false }
+func (NopVisitor) OnSelectionSet(*Iterator) bool     { return false }
+func (NopVisitor) OnSelectionSetEnd(*Iterator) bool  { return false }
+func (NopVisitor) OnFieldAlias(*Iterator) bool       { return false }
+func (NopVisitor) OnField(*Iterator) bool            { return false }
+func (NopVisitor) OnFragmentInline(*Iterator) bool   { return false }
+func (NopVisitor) OnFragmentSpread(*Iterator) bool   { return false }
+func (NopVisitor) OnArgList(*Iterator) bool          { return false }
+func (NopVisitor) OnArgListEnd(*Iterator) bool       { return false }
+func (NopVisitor) OnArg(*Iterator) bool              { return false }
+func (NopVisitor) OnValueStr(*Iterator) bool         { return false }
+func (NopVisitor) OnValueInt(*Iterator) bool         { return false }
+func (NopVisitor) OnValueFloat(*Iterator) bool       { return false }
+func (NopVisitor) OnValueBool(*Iterator) bool        { return false }
+func (NopVisitor) OnValueNull(*Iterator) bool        { return false }
+func (NopVisitor) OnValueEnum(*Iterator) bool        { return false }
+func (NopVisitor) OnValueVarRef(*Iterator) bool      { return false }
+func (NopVisitor) OnValueList(*Iterator) bool        { return false }
+func (NopVisitor) OnValueListEnd(*Iterator) bool     { return false }
+func (NopVisitor) OnValueObject(*Iterator) bool      { return false }
+func (NopVisitor) OnValueObjectEnd(*Iterator) bool   { return false }
+func (NopVisitor) OnValueObjectField(*Iterator) bool { return false }
+
+// ScanVisitor calls the method of v for every token it scans in str.
+func ScanVisitor(str []byte, v Visitor) Error {
+	return scan(str, 0, func(i *Iterator) bool {
+		switch i.token {
+		case TokenDefQry, TokenDefMut, TokenDefSub, TokenDefFrag:
+			return v.OnDefinition(i)
+		case TokenDefEnd:
+			return v.OnDefinitionEnd(i)
+		case TokenOprName:
+			return v.OnOperationName(i)
+		case TokenFragName:
+			return v.OnFragmentName(i)
+		case TokenFragTypeCond:
+			return v.OnTypeCondition(i)
+		case TokenDirName:
+			return v.OnDirective(i)
+		case TokenVarList:
+			return v.OnVariableList(i)
+		case TokenVarListEnd:
+			return v.OnVariableListEnd(i)
+		case TokenVarName:
+			return v.OnVariable(i)
+		case TokenVarTypeName, TokenVarTypeArr,
+			TokenVarTypeArrEnd, TokenVarTypeNotNull:
+			return v.OnVariableType(i)
+		case TokenSet:
+			return v.OnSelectionSet(i)
+		case TokenSetEnd:
+			return v.OnSelectionSetEnd(i)
+		case TokenFieldAlias:
+			return v.OnFieldAlias(i)
+		case TokenField:
+			return v.OnField(i)
+		case TokenFragInline:
+			return v.OnFragmentInline(i)
+		case TokenNamedSpread:
+			return v.OnFragmentSpread(i)
+		case TokenArgList:
+			return v.OnArgList(i)
+		case TokenArgListEnd:
+			return v.OnArgListEnd(i)
+		case TokenArgName:
+			return v.OnArg(i)
+		case TokenStr, TokenStrBlock:
+			return v.OnValueStr(i)
+		case TokenInt:
+			return v.OnValueInt(i)
+		case TokenFloat:
+			return v.OnValueFloat(i)
+		case TokenTrue, TokenFalse:
+			return v.OnValueBool(i)
+		case TokenNull:
+			return v.OnValueNull(i)
+		case TokenEnumVal:
+			return v.OnValueEnum(i)
+		case TokenVarRef:
+			return v.OnValueVarRef(i)
+		case TokenArr:
+			return v.OnValueList(i)
+		case TokenArrEnd:
+			return v.OnValueListEnd(i)
+		case TokenObj:
+			return v.OnValueObject(i)
+		case TokenObjEnd:
+			return v.OnValueObjectEnd(i)
+		case TokenObjField:
+			return v.OnValueObjectField(i)
+		}
+		return false
+	})
+}
@@ -0,0 +1,219 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+// visitorMethods maps the tokens to the names of the Visitor methods.
+var visitorMethods = map[gqlscan.Token]string{
+	gqlscan.TokenDefQry:         "OnDefinition",
+	gqlscan.TokenDefMut:         "OnDefinition",
+	gqlscan.TokenDefSub:         "OnDefinition",
+	gqlscan.TokenDefFrag:        "OnDefinition",
+	gqlscan.TokenDefEnd:         "OnDefinitionEnd",
+	gqlscan.TokenOprName:        "OnOperationName",
+	gqlscan.TokenFragName:       "OnFragmentName",
+	gqlscan.TokenFragTypeCond:   "OnTypeCondition",
+	gqlscan.TokenDirName:        "OnDirective",
+	gqlscan.TokenVarList:        "OnVariableList",
+	gqlscan.TokenVarListEnd:     "OnVariableListEnd",
+	gqlscan.TokenVarName:        "OnVariable",
+	gqlscan.TokenVarTypeName:    "OnVariableType",
+	gqlscan.TokenVarTypeArr:     "OnVariableType",
+	gqlscan.TokenVarTypeArrEnd:  "OnVariableType",
+	gqlscan.TokenVarTypeNotNull: "OnVariableType",
+	gqlscan.TokenSet:            "OnSelectionSet",
+	gqlscan.TokenSetEnd:         "OnSelectionSetEnd",
+	gqlscan.TokenFieldAlias:     "OnFieldAlias",
+	gqlscan.TokenField:          "OnField",
+	gqlscan.TokenFragInline:     "OnFragmentInline",
+	gqlscan.TokenNamedSpread:    "OnFragmentSpread",
+	gqlscan.TokenArgList:        "OnArgList",
+	gqlscan.TokenArgListEnd:     "OnArgListEnd",
+	gqlscan.TokenArgName:        "OnArg",
+	gqlscan.TokenStr:            "OnValueStr",
+	gqlscan.TokenStrBlock:       "OnValueStr",
+	gqlscan.TokenInt:            "OnValueInt",
+	gqlscan.TokenFloat:          "OnValueFloat",
+	gqlscan.TokenTrue:           "OnValueBool",
+	gqlscan.TokenFalse:          "OnValueBool",
+	gqlscan.TokenNull:           "OnValueNull",
+	gqlscan.TokenEnumVal:        "OnValueEnum",
+	gqlscan.TokenVarRef:         "OnValueVarRef",
+	gqlscan.TokenArr:            "OnValueList",
+	gqlscan.TokenArrEnd:         "OnValueListEnd",
+	gqlscan.TokenObj:            "OnValueObject",
+	gqlscan.TokenObjEnd:         "OnValueObjectEnd",
+	gqlscan.TokenObjField:       "OnValueObjectField",
+}
+
+type visitorRecorder struct {
+	t      *testing.T
+	expect []Expect
+	j      int
+}
+
+func (r *visitorRecorder) visit(method string, i *gqlscan.Iterator) bool {
+	require.True(r.t, r.j < len(r.expect))
+	require.Equal(r.t, r.expect[r.j].Type, i.Token())
+	require.Equal(r.t, r.expect[r.j].Value, string(i.Value()))
+	require.Equal(r.t, visitorMethods[i.Token()], method)
+	r.j++
+	return false
+}
+
+func (r *visitorRecorder) OnDefinition(i *gqlscan.Iterator) bool {
+	return r.visit("OnDefinition", i)
+}
+
+func (r *visitorRecorder) OnDefinitionEnd(i *gqlscan.Iterator) bool {
+	return r.visit("OnDefinitionEnd", i)
+}
+
+func (r *visitorRecorder) OnOperationName(i *gqlscan.Iterator) bool {
+	return r.visit("OnOperationName", i)
+}
+
+func (r *visitorRecorder) OnFragmentName(i *gqlscan.Iterator) bool {
+	return r.visit("OnFragmentName", i)
+}
+
+func (r *visitorRecorder) OnTypeCondition(i *gqlscan.Iterator) bool {
+	return r.visit("OnTypeCondition", i)
+}
+
+func (r *visitorRecorder) OnDirective(i *gqlscan.Iterator) bool {
+	return r.visit("OnDirective", i)
+}
+
+func (r *visitorRecorder) OnVariableList(i *gqlscan.Iterator) bool {
+	return r.visit("OnVariableList", i)
+}
+
+func (r *visitorRecorder) OnVariableListEnd(i *gqlscan.Iterator) bool {
+	return r.visit("OnVariableListEnd", i)
+}
+
+func (r *visitorRecorder) OnVariable(i *gqlscan.Iterator) bool {
+	return r.visit("OnVariable", i)
+}
+
+func (r *visitorRecorder) OnVariableType(i *gqlscan.Iterator) bool {
+	return r.visit("OnVariableType", i)
+}
+
+func (r *visitorRecorder) OnSelectionSet(i *gqlscan.Iterator) bool {
+	return r.visit("OnSelectionSet", i)
+}
+
+func (r *visitorRecorder) OnSelectionSetEnd(i *gqlscan.Iterator) bool {
+	return r.visit("OnSelectionSetEnd", i)
+}
+
+func (r *visitorRecorder) OnFieldAlias(i *gqlscan.Iterator) bool {
+	return r.visit("OnFieldAlias", i)
+}
+
+func (r *visitorRecorder) OnField(i *gqlscan.Iterator) bool {
+	return r.visit("OnField", i)
+}
+
+func (r *visitorRecorder) OnFragmentInline(i *gqlscan.Iterator) bool {
+	return r.visit("OnFragmentInline", i)
+}
+
+func (r *visitorRecorder) OnFragmentSpread(i *gqlscan.Iterator) bool {
+	return r.visit("OnFragmentSpread", i)
+}
+
+func (r *visitorRecorder) OnArgList(i *gqlscan.Iterator) bool {
+	return r.visit("OnArgList", i)
+}
+
+func (r *visitorRecorder) OnArgListEnd(i *gqlscan.Iterator) bool {
+	return r.visit("OnArgListEnd", i)
+}
+
+func (r *visitorRecorder) OnArg(i *gqlscan.Iterator) bool {
+	return r.visit("OnArg", i)
+}
+
+func (r *visitorRecorder) OnValueStr(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueStr", i)
+}
+
+func (r *visitorRecorder) OnValueInt(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueInt", i)
+}
+
+func (r *visitorRecorder) OnValueFloat(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueFloat", i)
+}
+
+func (r *visitorRecorder) OnValueBool(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueBool", i)
+}
+
+func (r *visitorRecorder) OnValueNull(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueNull", i)
+}
+
+func (r *visitorRecorder) OnValueEnum(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueEnum", i)
+}
+
+func (r *visitorRecorder) OnValueVarRef(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueVarRef", i)
+}
+
+func (r *visitorRecorder) OnValueList(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueList", i)
+}
+
+func (r *visitorRecorder) OnValueListEnd(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueListEnd", i)
+}
+
+func (r *visitorRecorder) OnValueObject(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueObject", i)
+}
+
+func (r *visitorRecorder) OnValueObjectEnd(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueObjectEnd", i)
+}
+
+func (r *visitorRecorder) OnValueObjectField(i *gqlscan.Iterator) bool {
+	return r.visit("OnValueObjectField", i)
+}
+
+func TestScanVisitor(t *testing.T) {
+	for _, td := range testdata {
+		t.Run(td.decl, func(t *testing.T) {
+			r := &visitorRecorder{t: t, expect: td.expect}
+			err := gqlscan.ScanVisitor([]byte(td.input), r)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Len(t, td.expect, r.j)
+		})
+	}
+}
+
+type fieldVisitor struct {
+	gqlscan.NopVisitor
+	fields []string
+}
+
+func (v *fieldVisitor) OnField(i *gqlscan.Iterator) bool {
+	v.fields = append(v.fields, string(i.Value()))
+	return len(v.fields) > 2
+}
+
+func TestScanVisitorNop(t *testing.T) {
+	v := &fieldVisitor{}
+	err := gqlscan.ScanVisitor([]byte(`{a(x:1) @d b {c} d}`), v)
+	require.Equal(t, gqlscan.ErrCallbackFn, err.Code)
+	require.Equal(t, []string{"a", "b", "c"}, v.fields)
+}
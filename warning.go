@@ -0,0 +1,54 @@
+package gqlscan
+
+import "strconv"
+
+// WarningCode defines the type of a warning.
+type WarningCode int
+
+const (
+	_ WarningCode = iota
+
+	// WarnPlusSignedNum is reported for numbers with a leading '+'
+	// tolerated by WithLenientNumbers.
+	WarnPlusSignedNum
+)
+
+func (c WarningCode) String() string {
+	switch c {
+	case WarnPlusSignedNum:
+		return "number with leading plus sign"
+	}
+	return ""
+}
+
+// Warning is a deviation from the specification that was
+// tolerated instead of failing the scan.
+type Warning struct {
+	Index int
+	Code  WarningCode
+}
+
+func (w Warning) String() string {
+	return "warning at index " + strconv.Itoa(w.Index) + ": " + w.Code.String()
+}
+
+// WithLenientNumbers makes the scan accept numbers with a leading '+'
+// such as `+1` and `+1.5e3`, which the specification forbids.
+// The values of the tokens include the sign.
+// WarnPlusSignedNum is reported for every such number.
+func WithLenientNumbers() Option {
+	return func(o *options) { o.lenientNums = true }
+}
+
+// WithWarnings makes the scan call fn for every tolerated deviation
+// from the specification, see WithLenientNumbers.
+func WithWarnings(fn func(Warning)) Option {
+	return func(o *options) { o.warn = fn }
+}
+
+// warning reports a warning with code c at the head.
+func (i *Iterator) warning(c WarningCode) {
+	if i.warn != nil {
+		i.warn(Warning{Index: i.head, Code: c})
+	}
+}
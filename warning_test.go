@@ -0,0 +1,76 @@
+package gqlscan_test
+
+import (
+	"testing"
+
+	"github.com/graph-guard/gqlscan"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLenientNumbers(t *testing.T) {
+	src := []byte(`query($v: Float = +1.5) {a(b: +42, c: [-1 +2e3])}`)
+	var warnings []gqlscan.Warning
+	var values []string
+	err := gqlscan.ScanWithOptions(
+		src,
+		func(i *gqlscan.Iterator) bool {
+			switch i.Token() {
+			case gqlscan.TokenInt, gqlscan.TokenFloat:
+				values = append(values, string(i.Value()))
+			}
+			return false
+		},
+		gqlscan.WithLenientNumbers(),
+		gqlscan.WithWarnings(func(w gqlscan.Warning) {
+			warnings = append(warnings, w)
+		}),
+	)
+	require.False(t, err.IsErr(), err.Error())
+	require.Equal(t, []string{"+1.5", "+42", "-1", "+2e3"}, values)
+	require.Equal(t, []gqlscan.Warning{
+		{Index: 18, Code: gqlscan.WarnPlusSignedNum},
+		{Index: 30, Code: gqlscan.WarnPlusSignedNum},
+		{Index: 42, Code: gqlscan.WarnPlusSignedNum},
+	}, warnings)
+	require.Equal(t, "warning at index 18: number with leading plus sign",
+		warnings[0].String())
+}
+
+func TestWithLenientNumbersErr(t *testing.T) {
+	for _, td := range []struct {
+		decl   string
+		input  string
+		expect string
+	}{
+		{decl(1), `{a(b: +)}`, "error at index 7 (')'): " +
+			"invalid number value; expected value"},
+		{decl(1), `{a(b: ++1)}`, "error at index 7 ('+'): " +
+			"invalid number value; expected value"},
+		{decl(1), `{a(b: +`, "error at index 7: " +
+			"unexpected end of file; expected value"},
+	} {
+		t.Run(td.decl, func(t *testing.T) {
+			err := gqlscan.ScanWithOptions(
+				[]byte(td.input),
+				func(*gqlscan.Iterator) bool { return false },
+				gqlscan.WithLenientNumbers(),
+			)
+			require.Equal(t, td.expect, err.Error())
+		})
+	}
+}
+
+func TestWithLenientNumbersDisabled(t *testing.T) {
+	noop := func(*gqlscan.Iterator) bool { return false }
+	src := []byte(`{a(b: +42)}`)
+	err := gqlscan.ScanWithOptions(src, noop, gqlscan.WithLenientNumbers())
+	require.False(t, err.IsErr())
+
+	// The pooled iterator mustn't remain lenient.
+	for n := 0; n < 8; n++ {
+		require.Equal(t, gqlscan.ErrInvalNum, gqlscan.Scan(src, noop).Code)
+		require.Equal(t, gqlscan.ErrInvalNum,
+			gqlscan.ScanWithOptions(src, noop).Code)
+	}
+}
@@ -0,0 +1,182 @@
+package gqlscan
+
+// writer writes a token stream as a compact document
+// with no more separators between the tokens than necessary.
+type writer struct {
+	dst []byte
+
+	// prev holds the previously written token.
+	prev Token
+
+	// pendingQry is true if the query keyword of the previous
+	// TokenDefQry wasn't written yet since it can be omitted
+	// when the definition is a shorthand query.
+	pendingQry bool
+
+	// levelArgs is > 0 inside of argument lists and
+	// inVarList is true inside of variable lists.
+	levelArgs int
+	inVarList bool
+}
+
+func (w *writer) reset(dst []byte) {
+	*w = writer{dst: dst}
+}
+
+// write writes token t with the given value.
+func (w *writer) write(t Token, value []byte) {
+	if w.pendingQry {
+		w.pendingQry = false
+		if t != TokenSet {
+			w.word("query")
+		}
+	}
+
+	switch t {
+	case TokenDefQry:
+		w.pendingQry = true
+	case TokenDefMut:
+		w.word("mutation")
+	case TokenDefSub:
+		w.word("subscription")
+	case TokenDefFrag:
+		w.word("fragment")
+	case TokenOprName, TokenFragName, TokenField, TokenVarTypeName:
+		w.wordBytes(value)
+	case TokenFragTypeCond:
+		w.word("on")
+		w.wordBytes(value)
+	case TokenDirName:
+		w.punct('@')
+		w.dst = append(w.dst, value...)
+	case TokenVarList:
+		w.inVarList = true
+		w.punct('(')
+	case TokenVarListEnd:
+		w.inVarList = false
+		w.punct(')')
+	case TokenArgList:
+		w.levelArgs++
+		w.punct('(')
+	case TokenArgListEnd:
+		w.levelArgs--
+		w.punct(')')
+	case TokenSet:
+		w.punct('{')
+	case TokenSetEnd:
+		w.punct('}')
+	case TokenDefEnd:
+		// The end of a definition has no representation.
+		return
+	case TokenFragInline:
+		// The value of TokenFragInline is its optional type condition.
+		w.separate('.')
+		w.dst = append(w.dst, "..."...)
+		if len(value) > 0 {
+			w.dst = append(w.dst, "on "...)
+			w.dst = append(w.dst, value...)
+		}
+	case TokenNamedSpread:
+		w.separate('.')
+		w.dst = append(w.dst, "..."...)
+		w.dst = append(w.dst, value...)
+	case TokenFieldAlias, TokenArgName, TokenObjField:
+		w.wordBytes(value)
+		w.dst = append(w.dst, ':')
+	case TokenVarName:
+		w.punct('$')
+		w.dst = append(w.dst, value...)
+		w.dst = append(w.dst, ':')
+	case TokenVarRef:
+		w.punct('$')
+		w.dst = append(w.dst, value...)
+	case TokenVarTypeArr:
+		w.punct('[')
+	case TokenVarTypeArrEnd:
+		w.punct(']')
+	case TokenVarTypeNotNull:
+		w.punct('!')
+	default:
+		w.value(t, value)
+	}
+	w.prev = t
+}
+
+// value writes a value token, prefixing it with the equals sign
+// if it's the default value of a variable.
+func (w *writer) value(t Token, value []byte) {
+	if w.inVarList && w.levelArgs < 1 {
+		switch w.prev {
+		case TokenVarTypeName, TokenVarTypeNotNull, TokenVarTypeArrEnd:
+			w.punct('=')
+		}
+	}
+	switch t {
+	case TokenArr:
+		w.punct('[')
+	case TokenArrEnd:
+		w.punct(']')
+	case TokenObj:
+		w.punct('{')
+	case TokenObjEnd:
+		w.punct('}')
+	case TokenStr:
+		w.punct('"')
+		w.dst = append(w.dst, value...)
+		w.dst = append(w.dst, '"')
+	case TokenStrBlock:
+		w.separate('"')
+		w.dst = append(w.dst, `"""`...)
+		w.dst = append(w.dst, value...)
+		w.dst = append(w.dst, `"""`...)
+	case TokenTrue:
+		w.word("true")
+	case TokenFalse:
+		w.word("false")
+	case TokenNull:
+		w.word("null")
+	default:
+		// TokenInt, TokenFloat and TokenEnumVal
+		w.wordBytes(value)
+	}
+}
+
+// separate writes a space if the previously written token
+// can't be directly followed by a token starting with b.
+func (w *writer) separate(b byte) {
+	if len(w.dst) < 1 {
+		return
+	}
+	last := w.dst[len(w.dst)-1]
+	switch w.prev {
+	case TokenInt, TokenFloat, TokenTrue, TokenFalse, TokenNull:
+		// Numbers and keyword values must be followed by
+		// either an ignored token or a closing bracket.
+		if b != ')' && b != ']' && b != '}' {
+			w.dst = append(w.dst, ' ')
+		}
+		return
+	}
+	if (charClass[last]&className != 0 && charClass[b]&className != 0) ||
+		(last == '"' && b == '"') {
+		w.dst = append(w.dst, ' ')
+	}
+}
+
+func (w *writer) punct(b byte) {
+	w.separate(b)
+	w.dst = append(w.dst, b)
+}
+
+func (w *writer) word(s string) {
+	w.separate(s[0])
+	w.dst = append(w.dst, s...)
+}
+
+func (w *writer) wordBytes(b []byte) {
+	if len(b) < 1 {
+		return
+	}
+	w.separate(b[0])
+	w.dst = append(w.dst, b...)
+}